@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// findArchivedItem 根据名称查找已归档的启动项
+func findArchivedItem(data *CacheData, name string) (int, *CacheItem) {
+	for i, item := range data.ArchivedItems {
+		if item.Name == name {
+			return i, &item
+		}
+	}
+	return -1, nil
+}
+
+// ArchiveEntry 归档启动项：从注册表移除，并将缓存记录连同所有附加信息移到归档中，以后可以恢复
+func ArchiveEntry(name string) error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	idx, item := findItemByName(cache, name)
+	if idx < 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	if exists, _ := IsCommandInStartup(name); exists {
+		if err := RemoveFromStartup(name); err != nil {
+			return err
+		}
+	}
+
+	// 同名的旧归档被新的覆盖
+	if i, _ := findArchivedItem(cache, name); i >= 0 {
+		cache.ArchivedItems = append(cache.ArchivedItems[:i], cache.ArchivedItems[i+1:]...)
+	}
+	item.ArchivedAt = time.Now()
+	recordHistory(item, HistoryRemoved, item.Value, "")
+	cache.ArchivedItems = append(cache.ArchivedItems, *item)
+	removeItem(cache, name)
+
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	markOrphansStale()
+	return nil
+}
+
+// RestoreArchivedEntry 恢复已归档的启动项，归档时为启用的项重新写入注册表
+func RestoreArchivedEntry(name string) error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	idx, item := findArchivedItem(cache, name)
+	if idx < 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if i, _ := findItemByName(cache, name); i >= 0 {
+		return fmt.Errorf("已存在同名的启动项: %s", name)
+	}
+
+	if item.Enabled {
+		if err := registerStartupItem(*item); err != nil {
+			return err
+		}
+	}
+
+	item.ArchivedAt = time.Time{}
+	recordHistory(item, HistoryAdded, "", item.Value)
+	cache.Items = append(cache.Items, *item)
+	cache.ArchivedItems = append(cache.ArchivedItems[:idx], cache.ArchivedItems[idx+1:]...)
+
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	markOrphansStale()
+	return nil
+}
+
+// PurgeArchivedEntries 彻底删除所有已归档的启动项
+func PurgeArchivedEntries() error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	cache.ArchivedItems = nil
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	return nil
+}
+
+// handleArchive 已归档的启动项子菜单
+func handleArchive() {
+	for {
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println("已归档的启动项")
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Println("1. 查看已归档的启动项")
+		fmt.Println("2. 归档启动项")
+		fmt.Println("3. 恢复已归档的启动项")
+		fmt.Println("4. 彻底删除所有已归档的启动项")
+		fmt.Println(strings.Repeat("=", 60))
+
+		choice := readInput("请选择操作（或输入 'b' 返回）: ")
+
+		switch choice {
+		case "1":
+			showArchivedEntries()
+		case "2":
+			handleArchiveEntry()
+		case "3":
+			handleRestoreArchived()
+		case "4":
+			handlePurgeArchived()
+		case "b", "B":
+			return
+		default:
+			fmt.Println("无效的选择，请重新输入。")
+		}
+	}
+}
+
+// archivedListItems 将已归档的启动项转换为列表项
+func archivedListItems(cache *CacheData) []ListItem {
+	items := make([]ListItem, 0, len(cache.ArchivedItems))
+	for _, item := range cache.ArchivedItems {
+		items = append(items, ListItem{
+			Name:  fmt.Sprintf("%s（归档于 %s）", item.Name, item.ArchivedAt.Format("2006-01-02 15:04")),
+			Value: item.Value,
+		})
+	}
+	return items
+}
+
+// showArchivedEntries 显示已归档的启动项
+func showArchivedEntries() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("已归档的启动项：")
+	fmt.Println(strings.Repeat("=", 60))
+
+	if len(cache.ArchivedItems) == 0 {
+		fmt.Println("没有已归档的启动项。")
+		return
+	}
+
+	for i, item := range archivedListItems(cache) {
+		fmt.Printf("%d. %s\n   %s\n\n", i+1, item.Name, item.Value)
+	}
+}
+
+// handleArchiveEntry 处理归档启动项
+func handleArchiveEntry() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	items := make([]ListItem, 0, len(cache.Items))
+	for _, item := range cache.Items {
+		status := "[启用]"
+		if !item.Enabled {
+			status = "[禁用]"
+		}
+		items = append(items, ListItem{Name: item.Name + " " + status, Value: item.Value})
+	}
+
+	idx, ok := showListWithBack(items, "所有启动项", pageSize)
+	if !ok {
+		return
+	}
+
+	selected := cache.Items[idx]
+	fmt.Println("\n归档后启动项将从注册表移除，原因、备注等信息会保留，可在这里恢复。")
+	if !confirmWithBack("归档", selected.Name, selected.Value) {
+		return
+	}
+
+	if err := ArchiveEntry(selected.Name); err != nil {
+		printError("归档失败 - %v", err)
+		return
+	}
+	fmt.Printf("已归档 %s！\n", selected.Name)
+}
+
+// handleRestoreArchived 处理恢复已归档的启动项
+func handleRestoreArchived() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	idx, ok := showListWithBack(archivedListItems(cache), "已归档的启动项", pageSize)
+	if !ok {
+		return
+	}
+
+	selected := cache.ArchivedItems[idx]
+	if !confirmWithBack("恢复", selected.Name, selected.Value) {
+		return
+	}
+
+	if err := RestoreArchivedEntry(selected.Name); err != nil {
+		printError("恢复失败 - %v", err)
+		return
+	}
+	fmt.Printf("已恢复 %s！\n", selected.Name)
+}
+
+// handlePurgeArchived 处理彻底删除所有已归档的启动项
+func handlePurgeArchived() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	if len(cache.ArchivedItems) == 0 {
+		fmt.Println("没有已归档的启动项。")
+		return
+	}
+
+	fmt.Printf("\n将彻底删除 %d 个已归档的启动项，且无法恢复。\n", len(cache.ArchivedItems))
+	if !confirmYes("确定要删除吗？(y/n): ") {
+		return
+	}
+
+	if err := PurgeArchivedEntries(); err != nil {
+		printError("删除失败 - %v", err)
+		return
+	}
+	fmt.Println("已删除所有已归档的启动项！")
+}
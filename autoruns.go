@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// Autoruns 中当前用户 Run 键的位置写法
+const autorunsRunLocation = `HKCU\SOFTWARE\Microsoft\Windows\CurrentVersion\Run`
+
+// Autoruns 导出 CSV 的列名
+var autorunsHeader = []string{
+	"Time", "Entry Location", "Entry", "Enabled", "Category", "Profile", "Description",
+	"Signer", "Company", "Image Path", "Version", "Launch String",
+	"MD5", "SHA-1", "PESHA-1", "PESHA-256", "SHA-256",
+}
+
+// autorunsLocation 返回 Autoruns 中 scope 下 Run 键的位置写法
+func autorunsLocation(scope RegistryScope) string {
+	if scope == ScopeMachineWide {
+		return `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Run`
+	}
+	return autorunsRunLocation
+}
+
+// ImportFromAutoruns 从 Sysinternals Autoruns 导出的 CSV 中读取当前用户 Run 键的启动项
+// Autoruns 默认以 UTF-16 保存 CSV，也兼容 UTF-8 和 ANSI 编码；RunOnce 等其他位置的行被忽略
+func ImportFromAutoruns(r io.Reader) ([]CacheItem, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(strings.NewReader(decodeRegFile(raw)))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV 文件为空")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取列名失败: %v", err)
+	}
+
+	// 按列名定位各列；Autoruns 的列名可能重复（如 Enabled），以第一次出现的为准
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if _, exists := columns[name]; !exists {
+			columns[name] = i
+		}
+	}
+	for _, required := range []string{"Entry Location", "Entry", "Launch String"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("缺少必填列: %s（不是 Autoruns 导出的 CSV？）", required)
+		}
+	}
+
+	var items []CacheItem
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 CSV 失败: %v", err)
+		}
+
+		row, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		if !strings.EqualFold(field("Entry Location"), autorunsRunLocation) {
+			continue
+		}
+
+		item := CacheItem{
+			Name:        field("Entry"),
+			Value:       field("Launch String"),
+			Enabled:     !strings.EqualFold(field("Enabled"), "disabled"),
+			FileVersion: field("Version"),
+			FileHash:    strings.ToLower(field("SHA-256")),
+		}
+		if item.Name == "" {
+			return nil, fmt.Errorf("第 %d 行: Entry 不能为空", row)
+		}
+		if item.Value == "" {
+			return nil, fmt.Errorf("第 %d 行: Launch String 不能为空", row)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// ExportToAutoruns 将启动项导出为与 Autoruns 列名相同的 CSV，便于在 Autoruns 中继续分析
+// MD5、SHA-1 和 SHA-256 在文件存在时现场计算；本工具不计算的 PE 映像哈希留空
+func ExportToAutoruns(w io.Writer, items []CacheItem) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(autorunsHeader); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		enabled := "enabled"
+		if !item.Enabled {
+			enabled = "disabled"
+		}
+
+		var imagePath, company, md5Sum, sha1Sum, sha256Sum string
+		version := item.FileVersion
+		if path, err := entryFilePath(item); err == nil {
+			imagePath = path
+			if version == "" {
+				version, _ = GetFileVersion(path)
+			}
+			company, _ = GetFilePublisher(path)
+			if sums, err := computeFileHashes(path, md5.New(), sha1.New(), sha256.New()); err == nil {
+				md5Sum, sha1Sum, sha256Sum = sums[0], sums[1], sums[2]
+			}
+		}
+
+		record := []string{
+			"", autorunsLocation(item.Scope), item.Name, enabled, "Logon", "", item.Notes,
+			"", company, imagePath, version, item.Value,
+			md5Sum, sha1Sum, "", "", sha256Sum,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// computeFileHashes 读取一次文件并计算多个哈希，返回大写十六进制（与 Autoruns 一致）
+func computeFileHashes(path string, hashes ...hash.Hash) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	writers := make([]io.Writer, len(hashes))
+	for i, h := range hashes {
+		writers[i] = h
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), f); err != nil {
+		return nil, err
+	}
+
+	sums := make([]string, len(hashes))
+	for i, h := range hashes {
+		sums[i] = strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
+	}
+	return sums, nil
+}
+
+// exportAutorunsFile 将启动项导出为 Autoruns 格式的 CSV 文件
+// 与 Autoruns 一样以带 BOM 的 UTF-16LE 保存
+func exportAutorunsFile(path string, items []CacheItem) error {
+	if dryRunf("导出 %d 项到 %s", len(items), path) {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := ExportToAutoruns(&buf, items); err != nil {
+		return err
+	}
+	return os.WriteFile(path, encodeUTF16LE(buf.String()), 0644)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// readBatchLines 读取批量输入中的非空行，忽略以 # 开头的注释行，返回行号和内容
+func readBatchLines(r io.Reader) ([]int, []string, error) {
+	var lineNos []int
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		lineNos = append(lineNos, lineNo)
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("读取输入失败: %v", err)
+	}
+	return lineNos, lines, nil
+}
+
+// parseBatchEntries 解析每行 name<Tab>command 的批量添加输入
+// 有格式错误的行时返回 *ParseError，不返回任何条目
+func parseBatchEntries(r io.Reader) ([][2]string, error) {
+	lineNos, lines, err := readBatchLines(r)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([][2]string, 0, len(lines))
+	for i, line := range lines {
+		name, command, ok := strings.Cut(line, "\t")
+		name, command = strings.TrimSpace(name), strings.TrimSpace(command)
+		switch {
+		case !ok:
+			return nil, &ParseError{Line: lineNos[i], Message: "缺少制表符分隔的启动命令"}
+		case name == "":
+			return nil, &ParseError{Line: lineNos[i], Message: "名称不能为空"}
+		case command == "":
+			return nil, &ParseError{Line: lineNos[i], Message: "命令不能为空"}
+		}
+		entries = append(entries, [2]string{name, command})
+	}
+	return entries, nil
+}
+
+// AddBatch 逐个添加 {名称, 命令} 启动项并显示进度，返回与 entries 一一对应的错误（成功为 nil）
+// 已存在的名称和批量中重复的名称不会被覆盖，名称与注册表一样不区分大小写
+func AddBatch(entries [][2]string) []error {
+	errs := make([]error, len(entries))
+	cache, err := loadCache()
+	if err != nil {
+		for i := range errs {
+			errs[i] = fmt.Errorf("加载缓存失败: %v", err)
+		}
+		return errs
+	}
+
+	seen := make(map[string]bool)
+	for i, entry := range entries {
+		name, command := entry[0], entry[1]
+		fmt.Printf("[%d/%d] 正在添加 %s...\n", i+1, len(entries), name)
+		key := strings.ToLower(name)
+		switch {
+		case seen[key]:
+			errs[i] = fmt.Errorf("名称 %s 在输入中重复", name)
+		case FindDuplicateName(cache, name):
+			errs[i] = fmt.Errorf("已存在名为 %s 的启动项", name)
+		default:
+			errs[i] = AddCommandEntry(name, command, "")
+		}
+		seen[key] = true
+	}
+	return errs
+}
+
+// RemoveBatch 逐个移除启动项并显示进度，返回与 names 一一对应的错误（成功为 nil）
+// 试运行时只输出将要执行的修改
+func RemoveBatch(names []string) []error {
+	errs := make([]error, len(names))
+	cache, err := loadCache()
+	if err != nil {
+		for i := range errs {
+			errs[i] = fmt.Errorf("加载缓存失败: %v", err)
+		}
+		return errs
+	}
+
+	for i, name := range names {
+		fmt.Printf("[%d/%d] 正在移除 %s...\n", i+1, len(names), name)
+		// 先检查启动项是否存在，试运行时也能发现拼错的名称
+		if inRegistry, _ := IsCommandInStartup(name); !inRegistry && !FindDuplicateName(cache, name) {
+			errs[i] = fmt.Errorf("启动项不存在")
+			continue
+		}
+		errs[i] = RemoveEntry(name)
+	}
+	return errs
+}
+
+// printBatchSummary 输出批量操作的成功和失败数量，以及每个失败项的原因
+func printBatchSummary(action string, names []string, errs []error) {
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	fmt.Printf("\n%s成功 %d 项，失败 %d 项。\n", action, len(errs)-failed, failed)
+	for i, err := range errs {
+		if err != nil {
+			fmt.Printf("  %s: %v\n", names[i], err)
+		}
+	}
+}
+
+// runAddBatch 从标准输入读取 name<Tab>command 行并批量添加，有失败项时返回错误
+func runAddBatch() error {
+	entries, err := parseBatchEntries(os.Stdin)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("输入中没有启动项")
+	}
+
+	errs := AddBatch(entries)
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry[0]
+	}
+	printBatchSummary("添加", names, errs)
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("部分启动项添加失败")
+		}
+	}
+	return nil
+}
+
+// runRemoveBatch 从标准输入读取每行一个的启动项名称并批量移除，有失败项时返回错误
+func runRemoveBatch() error {
+	_, names, err := readBatchLines(os.Stdin)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("输入中没有启动项名称")
+	}
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+
+	errs := RemoveBatch(names)
+	printBatchSummary("移除", names, errs)
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("部分启动项移除失败")
+		}
+	}
+	return nil
+}
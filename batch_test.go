@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBatchEntries(t *testing.T) {
+	input := "# 注释\nChrome\t\"C:\\Chrome\\chrome.exe\" --no-startup-window\r\n\n  Tool \t C:\\tool.exe \n"
+	got, err := parseBatchEntries(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseBatchEntries: %v", err)
+	}
+	want := [][2]string{
+		{"Chrome", `"C:\Chrome\chrome.exe" --no-startup-window`},
+		{"Tool", `C:\tool.exe`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBatchEntries = %q, want %q", got, want)
+	}
+
+	for _, tt := range []struct {
+		input    string
+		wantLine int
+	}{
+		{"A\tC:\\a.exe\nB C:\\b.exe\n", 2},
+		{"\n\t C:\\a.exe\n", 2},
+		{"A\t \n", 1},
+	} {
+		_, err := parseBatchEntries(strings.NewReader(tt.input))
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Line != tt.wantLine {
+			t.Errorf("parseBatchEntries(%q) err = %v, want 第 %d 行的 *ParseError", tt.input, err, tt.wantLine)
+		}
+	}
+}
+
+func TestAddBatch(t *testing.T) {
+	reg := setupTestEnv(t)
+	if err := AddCommandEntry("Existing", `C:\old.exe`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := AddBatch([][2]string{
+		{"A", `C:\a.exe`},
+		{"Existing", `C:\new.exe`},
+		{"B", `"C:\Program Files\b.exe" --min`},
+		{"a", `C:\other.exe`},
+		{"EXISTING", `C:\new.exe`},
+	})
+	if len(errs) != 5 {
+		t.Fatalf("返回 %d 个错误，want 5", len(errs))
+	}
+	// 名称不区分大小写：a 与 A 重复，EXISTING 与已有的 Existing 重复
+	for i, wantErr := range []bool{false, true, false, true, true} {
+		if (errs[i] != nil) != wantErr {
+			t.Errorf("errs[%d] = %v, want error: %v", i, errs[i], wantErr)
+		}
+	}
+
+	for name, want := range map[string]string{
+		"A":        `C:\a.exe`,
+		"B":        `"C:\Program Files\b.exe" --min`,
+		"Existing": `C:\old.exe`,
+	} {
+		if got, err := reg.GetValue(runKeyPath, name); err != nil || got != want {
+			t.Errorf("注册表中 %s = %q (%v), want %q", name, got, err, want)
+		}
+	}
+	cache, err := loadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.Items) != 3 {
+		t.Errorf("缓存中有 %d 项，want 3", len(cache.Items))
+	}
+}
+
+func TestRemoveBatch(t *testing.T) {
+	reg := setupTestEnv(t)
+	for _, name := range []string{"A", "B", "C"} {
+		if err := AddCommandEntry(name, `C:\`+name+`.exe`, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dryRun = true
+	errs := RemoveBatch([]string{"A", "Missing"})
+	dryRun = false
+	if errs[0] != nil || errs[1] == nil {
+		t.Errorf("试运行 errs = %v, want [nil, error]", errs)
+	}
+	if _, err := reg.GetValue(runKeyPath, "A"); err != nil {
+		t.Errorf("试运行不应删除 A: %v", err)
+	}
+
+	errs = RemoveBatch([]string{"A", "Missing", "C"})
+	for i, wantErr := range []bool{false, true, false} {
+		if (errs[i] != nil) != wantErr {
+			t.Errorf("errs[%d] = %v, want error: %v", i, errs[i], wantErr)
+		}
+	}
+	for name, want := range map[string]bool{"A": false, "B": true, "C": false} {
+		if _, err := reg.GetValue(runKeyPath, name); (err == nil) != want {
+			t.Errorf("注册表中 %s 存在 = %v, want %v", name, err == nil, want)
+		}
+	}
+	cache, err := loadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.Items) != 1 || cache.Items[0].Name != "B" {
+		t.Errorf("缓存 = %+v, want 只有 B", cache.Items)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// GenerateSetupBatch 生成在其他电脑上重建启动项的批处理文件
+// 启用的项生成 reg add，禁用的项生成注释掉的 reg add，
+// 生成的脚本只依赖系统自带的 reg.exe，双击即可运行；启动项按调整过的顺序输出
+func GenerateSetupBatch(w io.Writer, items []CacheItem) error {
+	var sb strings.Builder
+	writeBatchHeader(&sb, "重建启动项",
+		"在新电脑上双击运行即可把以下启动项写入当前用户的 Run 键。",
+		"已禁用的项以 REM 注释列出，需要时去掉行首的 REM 再运行。")
+
+	for _, item := range sortByPriority(items) {
+		command := fmt.Sprintf("reg add %s /v %s /t REG_SZ /d %s /f >nul",
+			quoteBatchArg(`HKCU\`+runKeyPath), quoteBatchArg(item.Name), quoteBatchArg(item.Value))
+		if item.Enabled {
+			sb.WriteString(command + "\r\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("REM 已禁用: %s\r\n", item.Name))
+			sb.WriteString("REM " + command + "\r\n")
+		}
+	}
+	writeBatchFooter(&sb)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// GenerateCleanupBatch 生成删除所有列出的启动项的批处理文件，不存在的项忽略
+func GenerateCleanupBatch(w io.Writer, items []CacheItem) error {
+	var sb strings.Builder
+	writeBatchHeader(&sb, "删除启动项",
+		"运行后从当前用户的 Run 键中删除以下启动项，不存在的项会被跳过。")
+
+	for _, item := range sortByPriority(items) {
+		sb.WriteString(fmt.Sprintf("reg delete %s /v %s /f >nul 2>nul\r\n",
+			quoteBatchArg(`HKCU\`+runKeyPath), quoteBatchArg(item.Name)))
+	}
+	writeBatchFooter(&sb)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeBatchHeader 写入批处理文件开头的说明
+// 先切换到 UTF-8 代码页，之后的中文注释和启动项名称才能被正确读取
+func writeBatchHeader(sb *strings.Builder, title string, usage ...string) {
+	hostname, _ := os.Hostname()
+
+	sb.WriteString("@echo off\r\n")
+	sb.WriteString("chcp 65001 >nul\r\n")
+	sb.WriteString("REM " + strings.Repeat("=", 58) + "\r\n")
+	sb.WriteString("REM Windows 自启动配置 - " + title + "\r\n")
+	sb.WriteString("REM 导出时间: " + time.Now().Format("2006-01-02 15:04:05") + "\r\n")
+	sb.WriteString("REM 计算机名: " + hostname + "\r\n")
+	for _, line := range usage {
+		sb.WriteString("REM " + line + "\r\n")
+	}
+	sb.WriteString("REM 由 Windows 自启动设置工具生成\r\n")
+	sb.WriteString("REM " + strings.Repeat("=", 58) + "\r\n\r\n")
+}
+
+// writeBatchFooter 写入批处理文件结尾，双击运行时暂停以便查看结果
+func writeBatchFooter(sb *strings.Builder) {
+	sb.WriteString("\r\necho 完成。\r\n")
+	sb.WriteString("pause\r\n")
+}
+
+// quoteBatchArg 将字符串转为批处理中传给 reg.exe 的一个带引号参数
+// 先按命令行参数规则转义引号和引号前的反斜杠，
+// 再对 cmd.exe 视为在引号外的特殊字符加 ^，% 写成两个以免被当作变量展开
+func quoteBatchArg(s string) string {
+	var arg strings.Builder
+	arg.WriteByte('"')
+	backslashes := 0
+	for _, c := range s {
+		switch c {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			arg.WriteString(strings.Repeat(`\`, backslashes*2+1))
+		default:
+			arg.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		backslashes = 0
+		arg.WriteRune(c)
+	}
+	arg.WriteString(strings.Repeat(`\`, backslashes*2))
+	arg.WriteByte('"')
+
+	var out strings.Builder
+	quoted := false
+	for _, c := range arg.String() {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == '%':
+			out.WriteByte('%')
+		case !quoted && strings.ContainsRune("&|<>^()", c):
+			out.WriteByte('^')
+		}
+		out.WriteRune(c)
+	}
+	return out.String()
+}
+
+// exportBatchFile 使用 generate 将启动项导出为 .cmd 文件
+func exportBatchFile(path string, items []CacheItem, generate func(io.Writer, []CacheItem) error) error {
+	if dryRunf("导出 %d 项到 %s", len(items), path) {
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return generate(file, items)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// 缓存文件格式
+const (
+	cacheFormatJSON = "json"
+	cacheFormatTOML = "toml"
+)
+
+// 新建缓存文件时使用的格式，由 --cache-format 或已有的缓存文件决定
+var cacheFormat = cacheFormatJSON
+
+// isValidCacheFormat 检查缓存文件格式是否受支持
+func isValidCacheFormat(format string) bool {
+	return format == cacheFormatJSON || format == cacheFormatTOML
+}
+
+// detectCacheFormat 根据目录中已有的默认缓存文件确定格式，都不存在时使用 JSON
+func detectCacheFormat(dir string) string {
+	if !isExistingFile(filepath.Join(dir, "autostart.json")) && isExistingFile(filepath.Join(dir, "autostart.toml")) {
+		return cacheFormatTOML
+	}
+	return cacheFormatJSON
+}
+
+// cacheFileFormat 根据扩展名确定缓存文件的格式，.toml 以外的文件都按 JSON 处理
+// backupCacheFile 生成的 .bak 按原文件的扩展名处理
+func cacheFileFormat(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".bak") {
+		path = path[:len(path)-len(".bak")]
+	}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return cacheFormatTOML
+	}
+	return cacheFormatJSON
+}
+
+// loadCacheAuto 按扩展名选择 JSON 或 TOML 解码缓存文件
+// TOML 的键名与 JSON 相同（见结构体的 toml 标签），旧版本以字符串保存的时间也能正常读取
+func loadCacheAuto(path string) (*CacheData, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &CacheData{}
+	if cacheFileFormat(path) == cacheFormatTOML {
+		err = toml.Unmarshal(raw, data)
+	} else {
+		err = json.Unmarshal(raw, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// saveCacheAuto 按扩展名选择 JSON 或 TOML 写入缓存文件
+func saveCacheAuto(path string, data *CacheData) error {
+	var raw []byte
+	var err error
+	if cacheFileFormat(path) == cacheFormatTOML {
+		raw, err = toml.Marshal(data)
+	} else {
+		raw, err = json.MarshalIndent(data, "", "  ")
+		raw = append(raw, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0644)
+}
+
+// ConvertCacheFiles 将所有配置方案的缓存文件转换为 format 格式，转换成功后删除原文件
+func ConvertCacheFiles(format string) (int, error) {
+	if !isValidCacheFormat(format) {
+		return 0, fmt.Errorf("不支持的缓存格式: %s（可选 json、toml）", format)
+	}
+	if format == cacheFormat {
+		return 0, fmt.Errorf("缓存文件已经是 %s 格式", format)
+	}
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		return 0, err
+	}
+
+	oldFormat := cacheFormat
+	converted := 0
+	for _, name := range profiles {
+		src := profileCachePath(name)
+		if !isExistingFile(src) {
+			continue
+		}
+		data, err := loadCacheFile(src)
+		if err != nil {
+			return converted, fmt.Errorf("加载 %s 失败: %v", src, err)
+		}
+
+		cacheFormat = format
+		dst := profileCachePath(name)
+		cacheFormat = oldFormat
+
+		if dryRunf("将 %s 转换为 %s", src, dst) {
+			converted++
+			continue
+		}
+		if err := saveCacheAuto(dst, data); err != nil {
+			return converted, fmt.Errorf("写入 %s 失败: %v", dst, err)
+		}
+		if err := os.Remove(src); err != nil {
+			return converted, fmt.Errorf("删除 %s 失败: %v", src, err)
+		}
+		converted++
+	}
+
+	if !dryRun {
+		cacheFormat = format
+		cacheFilePath = profileCachePath(currentProfile)
+	}
+	return converted, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// 缓存锁文件名，与缓存文件位于同一目录
+	cacheLockFileName = "autostart.lock"
+
+	// 等待其他实例释放缓存锁的最长时间
+	cacheLockTimeout = 5 * time.Second
+	// 重试获取缓存锁的间隔
+	cacheLockRetryInterval = 50 * time.Millisecond
+)
+
+// ErrLockTimeout 在超时时间内无法获取缓存锁（通常是另一个实例正在读写缓存）
+var ErrLockTimeout = errors.New("等待缓存锁超时")
+
+// acquireCacheLock 对缓存文件 path 所在目录的锁文件加排他锁
+// 返回的函数释放锁；超过 cacheLockTimeout 仍未获取时返回 ErrLockTimeout
+func acquireCacheLock(path string) (func(), error) {
+	// 试运行不写入缓存，也不创建锁文件
+	if dryRun {
+		return func() {}, nil
+	}
+
+	// 默认的缓存目录 %APPDATA%\AutostartManager 在第一次使用时创建
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建缓存目录失败: %v", err)
+	}
+	lockPath := filepath.Join(filepath.Dir(path), cacheLockFileName)
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开锁文件失败: %v", err)
+	}
+
+	handle := windows.Handle(file.Fd())
+	deadline := time.Now().Add(cacheLockTimeout)
+	for {
+		overlapped := new(windows.Overlapped)
+		err = windows.LockFileEx(handle, windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+			0, 1, 0, overlapped)
+		if err == nil {
+			break
+		}
+		if err != windows.ERROR_LOCK_VIOLATION || time.Now().After(deadline) {
+			file.Close()
+			if err == windows.ERROR_LOCK_VIOLATION {
+				return nil, ErrLockTimeout
+			}
+			return nil, fmt.Errorf("锁定缓存失败: %v", err)
+		}
+		time.Sleep(cacheLockRetryInterval)
+	}
+
+	return func() {
+		windows.UnlockFileEx(handle, 0, 1, 0, new(windows.Overlapped))
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"autostart/migrations"
+)
+
+// cacheVersion 当前缓存文件的结构版本
+// 给 CacheItem 增加字段时递增，并在 migrations 包中追加对应的迁移，见 migrations.All
+const cacheVersion = 10
+
+// ErrUnsupportedCacheVersion 缓存文件的版本无法识别或比本程序更新
+var ErrUnsupportedCacheVersion = errors.New("不支持的缓存文件版本")
+
+// migrateCache 将缓存依次迁移到当前版本
+func migrateCache(data *CacheData) error {
+	return migrateCacheTo(data, cacheVersion)
+}
+
+// migrateCacheTo 将缓存依次迁移到 targetVersion，不支持降级
+func migrateCacheTo(data *CacheData, targetVersion int) error {
+	if data.Version < 0 || data.Version > cacheVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedCacheVersion, data.Version)
+	}
+	if targetVersion < 0 || targetVersion > cacheVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedCacheVersion, targetVersion)
+	}
+	if data.Version > targetVersion {
+		return fmt.Errorf("缓存文件的版本 %d 已高于目标版本 %d", data.Version, targetVersion)
+	}
+	if data.Version == targetVersion {
+		return nil
+	}
+
+	// 迁移作用于缓存的 JSON 形式，迁移后再解码回 CacheData
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("编码缓存失败: %v", err)
+	}
+	doc := migrations.Document{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("解码缓存失败: %v", err)
+	}
+	for v := data.Version; v < targetVersion; v++ {
+		migrations.All[v](doc)
+	}
+	if raw, err = json.Marshal(doc); err != nil {
+		return fmt.Errorf("编码缓存失败: %v", err)
+	}
+	var migrated CacheData
+	if err := json.Unmarshal(raw, &migrated); err != nil {
+		return fmt.Errorf("解码迁移后的缓存失败: %v", err)
+	}
+	*data = migrated
+	return nil
+}
+
+// MigrateFile 读取任意版本的缓存文件 srcPath，迁移到 targetVersion 后写入 dstPath
+// 两个文件的格式都由扩展名决定，因此也可以同时转换格式；srcPath 本身不会被修改（除非与 dstPath 相同）
+func MigrateFile(srcPath, dstPath string, targetVersion int) error {
+	data, err := loadCacheAuto(srcPath)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %v", srcPath, err)
+	}
+	from := data.Version
+	if err := migrateCacheTo(data, targetVersion); err != nil {
+		return err
+	}
+
+	if dryRunf("将 %s 从版本 %d 迁移到版本 %d 并写入 %s", srcPath, from, targetVersion, dstPath) {
+		return nil
+	}
+	if err := saveCacheAuto(dstPath, data); err != nil {
+		return fmt.Errorf("写入 %s 失败: %v", dstPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"autostart/migrations"
+)
+
+// 加入版本号之前写入的缓存文件
+const v0CacheJSON = `{
+  "items": [
+    {"name": "App", "value": "\"C:\\App\\app.exe\" --min", "enabled": true, "created_at": "2023-05-01T08:00:00Z"},
+    {"name": "Old", "value": "old.exe", "enabled": false}
+  ],
+  "synced_at": "2023-05-02T08:00:00Z"
+}`
+
+func TestMigrateFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "old.json")
+	if err := os.WriteFile(src, []byte(v0CacheJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "new.json")
+	if err := MigrateFile(src, dst, cacheVersion); err != nil {
+		t.Fatalf("MigrateFile: %v", err)
+	}
+
+	data, err := loadCacheAuto(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Version != cacheVersion {
+		t.Errorf("Version = %d, want %d", data.Version, cacheVersion)
+	}
+	if len(data.Items) != 2 {
+		t.Fatalf("Items = %+v, want 2 项", data.Items)
+	}
+	app := data.Items[0]
+	if app.Name != "App" || app.Value != `"C:\App\app.exe" --min` || !app.Enabled || app.CreatedAt.IsZero() {
+		t.Errorf("App = %+v", app)
+	}
+	for _, item := range data.Items {
+		if item.Scope != ScopeCurrentUser || item.RunOnce {
+			t.Errorf("%s: Scope = %v, RunOnce = %v", item.Name, item.Scope, item.RunOnce)
+		}
+	}
+	if data.Items[1].Enabled {
+		t.Error("Old 应保持禁用")
+	}
+
+	// 源文件不变
+	raw, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != v0CacheJSON {
+		t.Error("源文件被修改")
+	}
+}
+
+func TestMigrateFileToTOML(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "old.json")
+	if err := os.WriteFile(src, []byte(v0CacheJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "new.toml")
+	if err := MigrateFile(src, dst, cacheVersion); err != nil {
+		t.Fatalf("MigrateFile: %v", err)
+	}
+	data, err := loadCacheAuto(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Version != cacheVersion || len(data.Items) != 2 || data.Items[0].Value != `"C:\App\app.exe" --min` {
+		t.Errorf("data = %+v", data)
+	}
+}
+
+func TestMigrateCacheTo(t *testing.T) {
+	if migrations.Latest() != cacheVersion {
+		t.Fatalf("migrations.Latest() = %d, want cacheVersion %d", migrations.Latest(), cacheVersion)
+	}
+
+	data := &CacheData{Items: []CacheItem{{Name: "App"}}}
+	if err := migrateCacheTo(data, 2); err != nil {
+		t.Fatal(err)
+	}
+	if data.Version != 2 {
+		t.Errorf("Version = %d, want 2", data.Version)
+	}
+
+	if err := migrateCacheTo(data, 1); err == nil {
+		t.Error("降级应返回错误")
+	}
+	if err := migrateCacheTo(data, cacheVersion+1); !errors.Is(err, ErrUnsupportedCacheVersion) {
+		t.Errorf("err = %v, want ErrUnsupportedCacheVersion", err)
+	}
+	if err := migrateCacheTo(&CacheData{Version: cacheVersion + 1}, cacheVersion); !errors.Is(err, ErrUnsupportedCacheVersion) {
+		t.Errorf("err = %v, want ErrUnsupportedCacheVersion", err)
+	}
+}
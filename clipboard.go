@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32               = windows.NewLazySystemDLL("user32.dll")
+	procOpenClipboard    = user32.NewProc("OpenClipboard")
+	procCloseClipboard   = user32.NewProc("CloseClipboard")
+	procGetClipboardData = user32.NewProc("GetClipboardData")
+
+	kernel32         = windows.NewLazySystemDLL("kernel32.dll")
+	procGlobalLock   = kernel32.NewProc("GlobalLock")
+	procGlobalUnlock = kernel32.NewProc("GlobalUnlock")
+)
+
+// 剪贴板中 UTF-16 文本的格式
+const cfUnicodeText = 13
+
+// getClipboardText 读取剪贴板中的文本
+func getClipboardText() (string, error) {
+	// 剪贴板由打开它的线程持有
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if r, _, err := procOpenClipboard.Call(0); r == 0 {
+		return "", fmt.Errorf("打开剪贴板失败: %v", err)
+	}
+	defer procCloseClipboard.Call()
+
+	handle, _, _ := procGetClipboardData.Call(cfUnicodeText)
+	if handle == 0 {
+		return "", fmt.Errorf("剪贴板中没有文本")
+	}
+
+	ptr, _, err := procGlobalLock.Call(handle)
+	if ptr == 0 {
+		return "", fmt.Errorf("读取剪贴板失败: %v", err)
+	}
+	defer procGlobalUnlock.Call(handle)
+
+	// GlobalLock 返回的是 Go 之外分配的内存，不受垃圾回收影响
+	return windows.UTF16PtrToString(*(**uint16)(unsafe.Pointer(&ptr))), nil
+}
+
+// clipboardPath 读取剪贴板中的文件路径
+// 资源管理器“复制为路径”会给路径加上引号，这里去掉首尾空白和引号
+func clipboardPath() (string, error) {
+	text, err := getClipboardText()
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if i := strings.IndexAny(text, "\r\n"); i >= 0 {
+		text = strings.TrimSpace(text[:i])
+	}
+	return strings.Trim(text, `"`), nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// ANSI 颜色代码
+const (
+	colorRed    = "31"
+	colorGreen  = "32"
+	colorYellow = "33"
+	colorCyan   = "36"
+)
+
+// ColorPrinter 输出带颜色的文本，终端不支持时输出纯文本
+type ColorPrinter interface {
+	Print(text, color string)
+	isColorSupported() bool
+}
+
+// consolePrinter 向标准输出打印，使用 VT100 转义序列着色
+type consolePrinter struct {
+	color bool
+}
+
+// 全局输出，main 中根据 --no-color 和终端能力初始化
+var printer ColorPrinter = &consolePrinter{}
+
+// newColorPrinter 创建 ColorPrinter，noColor 为 true 或终端不支持 VT100 时不着色
+func newColorPrinter(noColor bool) ColorPrinter {
+	if noColor {
+		return &consolePrinter{}
+	}
+	return &consolePrinter{color: enableVirtualTerminal()}
+}
+
+// enableVirtualTerminal 为标准输出开启 VT100 转义序列处理，返回是否成功
+// 输出被重定向到文件或管道时 GetConsoleMode 失败，此时不着色
+func enableVirtualTerminal() bool {
+	handle := windows.Handle(os.Stdout.Fd())
+
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
+
+func (p *consolePrinter) Print(text, color string) {
+	if p.color && color != "" {
+		fmt.Printf("\x1b[%sm%s\x1b[0m", color, text)
+		return
+	}
+	fmt.Print(text)
+}
+
+func (p *consolePrinter) isColorSupported() bool {
+	return p.color
+}
+
+// statusChip 显示启用/禁用状态，不着色时用方框字符代替方括号以便区分
+func statusChip(enabled bool) (string, string) {
+	label, color := "启用", colorGreen
+	if !enabled {
+		label, color = "禁用", colorRed
+	}
+	if printer.isColorSupported() {
+		return "[" + label + "]", color
+	}
+	return "┤" + label + "├", ""
+}
+
+// printError 以红色输出错误信息
+func printError(format string, args ...interface{}) {
+	fmt.Println()
+	printer.Print("错误: "+fmt.Sprintf(format, args...), colorRed)
+	fmt.Println()
+}
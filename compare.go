@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CacheDiffType 两个缓存文件之间的差异类型
+type CacheDiffType string
+
+const (
+	OnlyInFirst    CacheDiffType = "only_in_first"   // 只在第一个缓存中存在
+	OnlyInSecond   CacheDiffType = "only_in_second"  // 只在第二个缓存中存在
+	EnabledDiffers CacheDiffType = "enabled_differs" // 两边都存在但启用状态不同
+	ValueDiffers   CacheDiffType = "value_differs"   // 两边都存在但命令不同
+)
+
+// CacheFileDiff 两个缓存文件之间的一处差异，Item1、Item2 分别为两边的启动项
+type CacheFileDiff struct {
+	Name     string        `json:"name"`
+	DiffType CacheDiffType `json:"diff_type"`
+	Item1    *CacheItem    `json:"item1,omitempty"`
+	Item2    *CacheItem    `json:"item2,omitempty"`
+}
+
+// cacheDiffTypeLabel 差异类型的显示名称
+func cacheDiffTypeLabel(t CacheDiffType) string {
+	switch t {
+	case OnlyInFirst:
+		return "仅在本机"
+	case OnlyInSecond:
+		return "仅在对方"
+	case EnabledDiffers:
+		return "启用状态不同"
+	case ValueDiffers:
+		return "命令不同"
+	}
+	return string(t)
+}
+
+// CompareCacheFiles 比较两个缓存文件中的启动项，返回按名称排序的差异
+// 命令按规范化引号后的结果比较，忽略大小写；两个缓存来自不同的电脑或用户时在标准错误中提示
+func CompareCacheFiles(path1, path2 string) ([]CacheFileDiff, error) {
+	first, err := loadCompareCache(path1)
+	if err != nil {
+		return nil, err
+	}
+	second, err := loadCompareCache(path2)
+	if err != nil {
+		return nil, err
+	}
+	warnCacheOwnerMismatch(first, second)
+
+	items2 := make(map[string]CacheItem, len(second.Items))
+	for _, item := range second.Items {
+		items2[item.Name] = item
+	}
+
+	var diffs []CacheFileDiff
+	for _, item1 := range first.Items {
+		item1 := item1
+		item2, ok := items2[item1.Name]
+		if !ok {
+			diffs = append(diffs, CacheFileDiff{Name: item1.Name, DiffType: OnlyInFirst, Item1: &item1})
+			continue
+		}
+		delete(items2, item1.Name)
+
+		if item1.Enabled != item2.Enabled {
+			diffs = append(diffs, CacheFileDiff{Name: item1.Name, DiffType: EnabledDiffers, Item1: &item1, Item2: &item2})
+		}
+		if !strings.EqualFold(normalizeRegistryValue(item1.Value), normalizeRegistryValue(item2.Value)) {
+			diffs = append(diffs, CacheFileDiff{Name: item1.Name, DiffType: ValueDiffers, Item1: &item1, Item2: &item2})
+		}
+	}
+	for _, item2 := range items2 {
+		item2 := item2
+		diffs = append(diffs, CacheFileDiff{Name: item2.Name, DiffType: OnlyInSecond, Item2: &item2})
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs, nil
+}
+
+// loadCompareCache 加载用于比较的缓存文件，与 loadCacheFile 不同，文件不存在时返回错误
+func loadCompareCache(path string) (*CacheData, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("无法读取缓存文件 %s: %v", path, err)
+	}
+	data, err := loadCacheFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("加载缓存文件 %s 失败: %v", path, err)
+	}
+	return data, nil
+}
+
+// warnCacheOwnerMismatch 两个缓存由不同的电脑或用户写入时提示，程序路径的差异可能只是安装位置不同
+func warnCacheOwnerMismatch(first, second *CacheData) {
+	if first.Hostname == "" || second.Hostname == "" {
+		return
+	}
+	if strings.EqualFold(first.Hostname, second.Hostname) && strings.EqualFold(first.Username, second.Username) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "注意: 两个缓存分别由 %s 上的 %s 和 %s 上的 %s 写入，路径不同可能只是安装位置不同。\n",
+		first.Hostname, first.Username, second.Hostname, second.Username)
+}
+
+// runCompare 比较当前缓存与 otherPath，以文本、JSON 或每行一个差异的 NDJSON 输出差异
+func runCompare(otherPath, outputFormat string) error {
+	diffs, err := CompareCacheFiles(cacheFilePath, otherPath)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "ndjson" {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, diff := range diffs {
+			if err := encoder.Encode(diff); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if outputFormat == "json" {
+		if diffs == nil {
+			diffs = []CacheFileDiff{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diffs)
+	}
+
+	if len(diffs) == 0 {
+		fmt.Printf("本机的启动项与 %s 一致。\n", otherPath)
+		return nil
+	}
+
+	fmt.Printf("本机的启动项与 %s 有 %d 处差异：\n", otherPath, len(diffs))
+	for i, diff := range diffs {
+		fmt.Printf("%d. [%s] %s\n", i+1, cacheDiffTypeLabel(diff.DiffType), diff.Name)
+		switch diff.DiffType {
+		case OnlyInFirst:
+			fmt.Printf("   本机: %s（%s）\n", diff.Item1.Value, enabledLabel(diff.Item1.Enabled))
+		case OnlyInSecond:
+			fmt.Printf("   对方: %s（%s）\n", diff.Item2.Value, enabledLabel(diff.Item2.Enabled))
+		case EnabledDiffers:
+			fmt.Printf("   本机: %s，对方: %s\n", enabledLabel(diff.Item1.Enabled), enabledLabel(diff.Item2.Enabled))
+		case ValueDiffers:
+			fmt.Printf("   本机: %s\n", diff.Item1.Value)
+			fmt.Printf("   对方: %s\n", diff.Item2.Value)
+		}
+	}
+	return nil
+}
+
+// enabledLabel 启用状态的显示名称
+func enabledLabel(enabled bool) string {
+	if enabled {
+		return "已启用"
+	}
+	return "已禁用"
+}
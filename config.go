@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Config 保存在配置文件中的默认设置，命令行参数优先于配置文件
+type Config struct {
+	DefaultScope        string `json:"default_scope"`         // 查看注册表实时状态时的范围：user 或 machine
+	DefaultOutputFormat string `json:"default_output_format"` // 输出格式，目前只支持 text，为以后的输出格式预留
+	PageSize            int    `json:"page_size"`             // 列表每页显示的项数
+	AutoBackup          bool   `json:"auto_backup"`           // 保存缓存前是否先备份为 .bak
+	CacheDir            string `json:"cache_dir"`             // 缓存文件所在目录，优先于 PortableMode
+	ColorEnabled        bool   `json:"color_enabled"`         // 是否使用彩色输出
+	PortableMode        bool   `json:"portable_mode"`         // 便携模式：缓存文件保存在程序所在目录
+
+	RiskRules []RiskRule `json:"risk_rules,omitempty"` // 风险判断规则，为空时使用内置规则
+}
+
+// 当前使用的配置及其文件路径
+var (
+	config     = defaultConfig()
+	configPath string
+)
+
+// defaultConfig 返回没有配置文件时使用的默认设置
+func defaultConfig() Config {
+	return Config{
+		DefaultScope:        "user",
+		DefaultOutputFormat: "text",
+		PageSize:            defaultPageSize,
+		ColorEnabled:        true,
+	}
+}
+
+// appDataDir 获取本工具在 %APPDATA% 中的目录 %APPDATA%\AutostartManager
+func appDataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "AutostartManager")
+}
+
+// defaultConfigPath 获取默认的配置文件路径 %APPDATA%\AutostartManager\config.json
+func defaultConfigPath() string {
+	dir := appDataDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "config.json")
+}
+
+// resolveCacheDir 确定缓存文件所在的目录
+// 指定了 cacheDir 时使用 cacheDir；便携模式下使用程序所在目录；否则使用 %APPDATA%\AutostartManager
+func resolveCacheDir(portable bool, cacheDir string) string {
+	if cacheDir != "" {
+		return cacheDir
+	}
+
+	exePath, _ := os.Executable()
+	exeDir := filepath.Dir(exePath)
+	if portable {
+		return exeDir
+	}
+
+	// 旧版本总是把缓存放在程序所在目录，已有缓存时继续使用，避免丢失已禁用的启动项
+	if isExistingFile(filepath.Join(exeDir, "autostart.json")) {
+		return exeDir
+	}
+	if dir := appDataDir(); dir != "" {
+		return dir
+	}
+	return exeDir
+}
+
+// LoadConfig 加载配置文件，文件不存在时返回默认设置，文件中缺少的字段使用默认值
+func LoadConfig(path string) (Config, error) {
+	cfg := defaultConfig()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("读取配置文件失败: %v", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return defaultConfig(), fmt.Errorf("解析配置文件失败: %v", err)
+	}
+	return cfg, nil
+}
+
+// SaveConfig 保存配置文件，目录不存在时自动创建
+func SaveConfig(cfg Config, path string) error {
+	if dryRunf("保存配置文件 %s", path) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %v", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// configScope 返回配置中的默认注册表范围
+func configScope() RegistryScope {
+	if strings.EqualFold(config.DefaultScope, "machine") {
+		return ScopeMachineWide
+	}
+	return ScopeCurrentUser
+}
+
+// useCacheDir 将缓存文件放到 dir 中，并重新读取该目录中记录的配置方案
+func useCacheDir(dir string) {
+	cacheFilePath = filepath.Join(dir, "autostart.json")
+	cacheFormat = detectCacheFormat(dir)
+	currentProfile = loadCurrentProfile()
+	cacheFilePath = profileCachePath(currentProfile)
+}
+
+// backupCacheFile 将现有的缓存文件复制为 .bak，文件不存在时不做任何事
+func backupCacheFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return os.WriteFile(path+".bak", data, 0644)
+}
+
+// handleEditPreferences 处理编辑偏好设置
+func handleEditPreferences() {
+	for {
+		cacheDir := config.CacheDir
+		if cacheDir == "" {
+			cacheDir = "(默认)"
+		}
+
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println("编辑偏好设置")
+		fmt.Printf("配置文件: %s\n", configPath)
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Printf("1. 默认注册表范围: %s\n", config.DefaultScope)
+		fmt.Printf("2. 默认输出格式: %s\n", config.DefaultOutputFormat)
+		fmt.Printf("3. 每页显示项数: %d\n", config.PageSize)
+		fmt.Printf("4. 保存缓存前自动备份: %s\n", yesNoLabel(config.AutoBackup))
+		fmt.Printf("5. 缓存目录: %s\n", cacheDir)
+		fmt.Printf("6. 彩色输出: %s\n", yesNoLabel(config.ColorEnabled))
+		fmt.Printf("7. 便携模式（缓存保存在程序所在目录）: %s\n", yesNoLabel(config.PortableMode))
+		fmt.Println(strings.Repeat("=", 60))
+
+		choice := readInput("请选择要修改的设置（或输入 'b' 返回）: ")
+
+		cfg := config
+		switch choice {
+		case "1":
+			scope := strings.ToLower(readInput("请输入默认注册表范围 (user/machine): "))
+			if scope != "user" && scope != "machine" {
+				fmt.Println("无效的范围。")
+				continue
+			}
+			cfg.DefaultScope = scope
+		case "2":
+			format := strings.ToLower(readInput("请输入默认输出格式 (text): "))
+			if format != "text" {
+				fmt.Println("目前只支持 text。")
+				continue
+			}
+			cfg.DefaultOutputFormat = format
+		case "3":
+			n, err := strconv.Atoi(readInput("请输入每页显示的项数: "))
+			if err != nil || n < 1 {
+				fmt.Println("无效的数字。")
+				continue
+			}
+			cfg.PageSize = n
+		case "4":
+			cfg.AutoBackup = !cfg.AutoBackup
+		case "5":
+			dir := readInput("请输入缓存目录（留空使用默认位置）: ")
+			if dir != "" && !isExistingDir(dir) {
+				fmt.Println("目录不存在。")
+				continue
+			}
+			cfg.CacheDir = dir
+		case "6":
+			cfg.ColorEnabled = !cfg.ColorEnabled
+		case "7":
+			cfg.PortableMode = !cfg.PortableMode
+		case "b", "B":
+			return
+		default:
+			fmt.Println("无效的选择，请重新输入。")
+			continue
+		}
+
+		if err := SaveConfig(cfg, configPath); err != nil {
+			printError("保存设置失败 - %v", err)
+			continue
+		}
+
+		// 除缓存位置外的设置立即生效
+		if cfg.PageSize != config.PageSize {
+			pageSize = cfg.PageSize
+		}
+		if cfg.ColorEnabled != config.ColorEnabled {
+			printer = newColorPrinter(!cfg.ColorEnabled)
+		}
+		if cfg.CacheDir != config.CacheDir || cfg.PortableMode != config.PortableMode {
+			fmt.Println("缓存位置将在下次启动时生效。")
+		}
+		config = cfg
+		fmt.Println("设置已保存！")
+	}
+}
+
+// yesNoLabel 布尔设置的显示名称
+func yesNoLabel(b bool) string {
+	if b {
+		return "是"
+	}
+	return "否"
+}
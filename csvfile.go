@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSV 文件的列名
+var csvHeader = []string{"Name", "Value", "Enabled", "Tags", "Notes", "CreatedAt"}
+
+// CSV 中多个标签之间的分隔符
+const csvTagSeparator = ";"
+
+// ExportToCSV 将启动项导出为 CSV（RFC 4180）
+func ExportToCSV(w io.Writer, items []CacheItem) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		createdAt := ""
+		if !item.CreatedAt.IsZero() {
+			createdAt = item.CreatedAt.Format(time.RFC3339)
+		}
+
+		record := []string{
+			item.Name,
+			item.Value,
+			strconv.FormatBool(item.Enabled),
+			strings.Join(item.Tags, csvTagSeparator),
+			item.Notes,
+			createdAt,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportCSVFile 将启动项导出到 CSV 文件
+func exportCSVFile(path string, items []CacheItem) error {
+	if dryRunf("导出 %d 项到 %s", len(items), path) {
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return ExportToCSV(file, items)
+}
+
+// ImportFromCSV 从 CSV 中读取启动项
+// 第一行必须是列名，Name 和 Value 为必填列，其余列可省略
+func ImportFromCSV(r io.Reader) ([]CacheItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV 文件为空")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取列名失败: %v", err)
+	}
+
+	// 按列名定位各列，允许列的顺序不同
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, required := range []string{"Name", "Value"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("缺少必填列: %s", required)
+		}
+	}
+
+	var items []CacheItem
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 CSV 失败: %v", err)
+		}
+
+		row, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		item := CacheItem{
+			Name:    field("Name"),
+			Value:   field("Value"),
+			Enabled: true,
+			Notes:   field("Notes"),
+		}
+
+		if item.Name == "" {
+			return nil, fmt.Errorf("第 %d 行: Name 不能为空", row)
+		}
+		if item.Value == "" {
+			return nil, fmt.Errorf("第 %d 行: Value 不能为空", row)
+		}
+
+		if enabled := field("Enabled"); enabled != "" {
+			item.Enabled, err = strconv.ParseBool(enabled)
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: Enabled 无效: %s", row, enabled)
+			}
+		}
+
+		if tags := field("Tags"); tags != "" {
+			for _, tag := range strings.Split(tags, csvTagSeparator) {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					item.Tags = append(item.Tags, tag)
+				}
+			}
+		}
+
+		if createdAt := field("CreatedAt"); createdAt != "" {
+			item.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: CreatedAt 无效: %s", row, createdAt)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 启动脚本的文件名后缀：设置了延迟的项使用 _delay.cmd，只设置了工作目录的项使用 _workdir.cmd
+const (
+	delayWrapperSuffix   = "_delay.cmd"
+	workDirWrapperSuffix = "_workdir.cmd"
+)
+
+// hasStartWrapper 检查启动项是否需要通过生成的启动脚本启动
+func hasStartWrapper(item CacheItem) bool {
+	return item.DelaySeconds > 0 || item.WorkingDir != ""
+}
+
+// startWrapperSuffix 获取启动项的启动脚本的文件名后缀
+func startWrapperSuffix(item CacheItem) string {
+	if item.DelaySeconds > 0 {
+		return delayWrapperSuffix
+	}
+	return workDirWrapperSuffix
+}
+
+// startWrapperCommand 通过启动脚本启动时写入注册表的命令，即生成的批处理文件
+func startWrapperCommand(item CacheItem) string {
+	return fmt.Sprintf(`"%s"`, wrapperPath(item.Name, startWrapperSuffix(item)))
+}
+
+// writeStartWrapper 生成启动脚本：切换到工作目录，等待延迟秒数后再执行 Value
+func writeStartWrapper(item CacheItem) error {
+	var script strings.Builder
+	script.WriteString("@echo off\r\n")
+	if item.WorkingDir != "" {
+		fmt.Fprintf(&script, "cd /d \"%s\"\r\n", strings.ReplaceAll(item.WorkingDir, "%", "%%"))
+	}
+	if item.DelaySeconds > 0 {
+		fmt.Fprintf(&script, "timeout /t %d /nobreak >nul\r\n", item.DelaySeconds)
+	}
+	// 批处理中的 % 需要写成两个，否则会被当作变量展开
+	fmt.Fprintf(&script, "start \"\" %s\r\n", strings.ReplaceAll(item.Value, "%", "%%"))
+
+	path := wrapperPath(item.Name, startWrapperSuffix(item))
+	if dryRunf("生成文件 %s", path) {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(script.String()), 0644); err != nil {
+		return fmt.Errorf("生成启动脚本失败: %v", err)
+	}
+	return nil
+}
+
+// removeStartWrappers 删除为启动项生成的启动脚本，文件不存在时忽略
+func removeStartWrappers(appName string) error {
+	for _, suffix := range []string{delayWrapperSuffix, workDirWrapperSuffix} {
+		path := wrapperPath(appName, suffix)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if dryRunf("删除文件 %s", path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除启动脚本失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// registerStartupItem 将缓存中的启动项写入注册表
+// 设置了延迟或工作目录的项重新生成启动脚本并注册该脚本，RunOnce 项写回 RunOnce 键，
+// 移到所有用户的项写回 HKLM 的 Run 键，其余直接注册 Value
+func registerStartupItem(item CacheItem) error {
+	if item.RunOnce {
+		return AddToRunOnce(item.Value, item.Name, item.Scope)
+	}
+	if item.Scope == ScopeMachineWide {
+		return setRunValue(ScopeMachineWide, item.Name, item.Value)
+	}
+	if !hasStartWrapper(item) {
+		return AddCommandToStartup(item.Value, item.Name)
+	}
+
+	if err := writeStartWrapper(item); err != nil {
+		return err
+	}
+	return AddCommandToStartup(startWrapperCommand(item), item.Name)
+}
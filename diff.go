@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DiffType 缓存与注册表之间的差异类型
+type DiffType string
+
+const (
+	OnlyInRegistry DiffType = "only_in_registry" // 注册表中存在，缓存中不存在或为禁用
+	OnlyInCache    DiffType = "only_in_cache"    // 缓存中为启用，注册表中不存在
+	ValueMismatch  DiffType = "value_mismatch"   // 两边都存在但命令不同
+)
+
+// SyncDirection 应用差异时的同步方向
+type SyncDirection int
+
+const (
+	SyncToCache    SyncDirection = iota // 以注册表为准更新缓存
+	SyncToRegistry                      // 以缓存为准更新注册表
+)
+
+// DiffItem 缓存与注册表之间的一处差异
+type DiffItem struct {
+	Name          string
+	DiffType      DiffType
+	CacheValue    string
+	RegistryValue string
+	Direction     SyncDirection // 由 Apply 使用
+}
+
+// registryValueFor 获取缓存项在注册表中应有的值
+func registryValueFor(item CacheItem) string {
+	if hasStartWrapper(item) {
+		return startWrapperCommand(item)
+	}
+	return item.Value
+}
+
+// diffTypeLabel 差异类型的显示名称
+func diffTypeLabel(t DiffType) string {
+	switch t {
+	case OnlyInRegistry:
+		return "仅在注册表"
+	case OnlyInCache:
+		return "仅在缓存"
+	case ValueMismatch:
+		return "命令不同"
+	}
+	return string(t)
+}
+
+// DiffCacheAndRegistry 比较缓存与注册表 Run 键，返回按名称排序的差异
+func DiffCacheAndRegistry() ([]DiffItem, error) {
+	cache, err := loadCache()
+	if err != nil {
+		return nil, fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	registryItems, err := startupRegistry.ReadValues(runKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开注册表失败: %v", err)
+	}
+
+	var diffs []DiffItem
+	for name, value := range registryItems {
+		idx, item := findItemByName(cache, name)
+		switch {
+		case idx < 0:
+			diffs = append(diffs, DiffItem{Name: name, DiffType: OnlyInRegistry, RegistryValue: value})
+		case !item.Enabled:
+			diffs = append(diffs, DiffItem{Name: name, DiffType: OnlyInRegistry, CacheValue: item.Value, RegistryValue: value})
+		case registryValueFor(*item) != value:
+			diffs = append(diffs, DiffItem{Name: name, DiffType: ValueMismatch, CacheValue: item.Value, RegistryValue: value})
+		}
+	}
+	for _, item := range cache.Items {
+		if _, exists := registryItems[item.Name]; item.Enabled && !exists && !item.RunOnce {
+			diffs = append(diffs, DiffItem{Name: item.Name, DiffType: OnlyInCache, CacheValue: item.Value})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs, nil
+}
+
+// Apply 按每项的 Direction 同步差异
+// 以缓存为准移除仅在注册表中的项时，该项以禁用状态保留在缓存中，以便之后重新启用
+func Apply(items []DiffItem) error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	for _, diff := range items {
+		idx, _ := findItemByName(cache, diff.Name)
+
+		if diff.Direction == SyncToCache {
+			switch diff.DiffType {
+			case OnlyInRegistry, ValueMismatch:
+				item := addOrUpdateItem(cache, diff.Name, diff.RegistryValue, true)
+				item.DelaySeconds = 0
+				item.WorkingDir = ""
+			case OnlyInCache:
+				if idx >= 0 {
+					cache.Items[idx].Enabled = false
+				}
+			}
+			continue
+		}
+
+		switch diff.DiffType {
+		case OnlyInRegistry:
+			if err := RemoveFromStartup(diff.Name); err != nil {
+				return fmt.Errorf("%s: %v", diff.Name, err)
+			}
+			if idx < 0 {
+				addOrUpdateItem(cache, diff.Name, diff.RegistryValue, false)
+			}
+		case OnlyInCache, ValueMismatch:
+			if idx < 0 {
+				return fmt.Errorf("%s: 缓存中不存在", diff.Name)
+			}
+			if err := registerStartupItem(cache.Items[idx]); err != nil {
+				return fmt.Errorf("%s: %v", diff.Name, err)
+			}
+		}
+	}
+
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	return nil
+}
+
+// diffListItems 将差异转换为列表项
+func diffListItems(diffs []DiffItem) []ListItem {
+	items := make([]ListItem, 0, len(diffs))
+	for _, diff := range diffs {
+		value := ""
+		switch diff.DiffType {
+		case OnlyInRegistry:
+			value = "注册表: " + diff.RegistryValue
+		case OnlyInCache:
+			value = "缓存: " + diff.CacheValue
+		case ValueMismatch:
+			value = "缓存: " + diff.CacheValue + "\n   注册表: " + diff.RegistryValue
+		}
+		items = append(items, ListItem{
+			Name:  fmt.Sprintf("[%s] %s", diffTypeLabel(diff.DiffType), diff.Name),
+			Value: value,
+		})
+	}
+	return items
+}
+
+// chooseSyncDirection 询问同步方向
+func chooseSyncDirection() (SyncDirection, bool) {
+	fmt.Println("1. 以注册表为准更新缓存")
+	fmt.Println("2. 以缓存为准更新注册表")
+	switch readInput("请选择同步方向（或输入 'b' 返回）: ") {
+	case "1":
+		return SyncToCache, true
+	case "2":
+		return SyncToRegistry, true
+	}
+	return 0, false
+}
+
+// handleShowDiff 显示缓存与注册表的差异，并按用户选择的方向同步
+func handleShowDiff() {
+	for {
+		diffs, err := DiffCacheAndRegistry()
+		if err != nil {
+			fmt.Printf("比较失败: %v\n", err)
+			return
+		}
+		if len(diffs) == 0 {
+			fmt.Println("\n缓存与注册表一致。")
+			return
+		}
+
+		idx, ok := showListWithBack(diffListItems(diffs), "缓存与注册表的差异", pageSize)
+		if !ok {
+			return
+		}
+
+		direction, ok := chooseSyncDirection()
+		if !ok {
+			continue
+		}
+		diff := diffs[idx]
+		diff.Direction = direction
+		if err := Apply([]DiffItem{diff}); err != nil {
+			printError("同步失败 - %v", err)
+			continue
+		}
+		fmt.Printf("已同步 %s！\n", diff.Name)
+	}
+}
+
+// reviewStartupDiff 交互模式启动时显示缓存与注册表的差异，由用户决定是否同步
+// 没有差异时直接同步（导入其他工具的禁用项）
+func reviewStartupDiff() {
+	diffs, err := DiffCacheAndRegistry()
+	if err != nil || len(diffs) == 0 {
+		syncCacheFromRegistry()
+		return
+	}
+
+	fmt.Printf("\n缓存与注册表有 %d 处差异：\n", len(diffs))
+	for _, diff := range diffs {
+		fmt.Printf("  [%s] %s\n", diffTypeLabel(diff.DiffType), diff.Name)
+	}
+	if confirmYes("是否以注册表为准更新缓存？(y/n，选择 n 可稍后在菜单中逐项处理): ") {
+		syncCacheFromRegistry()
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 下载程序的超时时间
+const downloadTimeout = 10 * time.Minute
+
+// defaultDownloadDir 获取默认的下载目录 %LOCALAPPDATA%\AutostartManager\downloads
+func defaultDownloadDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "AutostartManager", "downloads")
+}
+
+// isLocalhostURL 检查 URL 是否指向本机，本机地址可以不校验 SHA-256
+func isLocalhostURL(u *url.URL) bool {
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isValidSHA256 检查是否为 64 位十六进制的 SHA-256
+func isValidSHA256(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// DownloadFile 将 rawURL 下载到 dir 中，文件名取自 URL 路径的最后一段，返回下载的文件路径
+// wantHash 不为空时校验文件的 SHA-256，不一致时删除已下载的内容；非本机地址必须指定 wantHash
+func DownloadFile(rawURL, dir, wantHash string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("无效的 URL: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("只支持 http 和 https 地址: %s", rawURL)
+	}
+	if wantHash == "" && !isLocalhostURL(u) {
+		return "", fmt.Errorf("从非本机地址下载时必须用 --sha256 指定文件的 SHA-256")
+	}
+	if wantHash != "" && !isValidSHA256(wantHash) {
+		return "", fmt.Errorf("无效的 SHA-256: %s", wantHash)
+	}
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return "", fmt.Errorf("无法从 URL 中确定文件名: %s", rawURL)
+	}
+	dst := filepath.Join(dir, name)
+
+	if dryRunf("下载 %s 到 %s", rawURL, dst) {
+		return dst, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建下载目录失败: %v", err)
+	}
+
+	client := &http.Client{Timeout: downloadTimeout}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("下载失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("下载失败: %s", resp.Status)
+	}
+
+	// 先写入临时文件，校验通过后再改名，校验失败时不留下文件
+	tmp, err := os.CreateTemp(dir, name+".*.part")
+	if err != nil {
+		return "", fmt.Errorf("创建临时文件失败: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("下载失败: %v", err)
+	}
+
+	if got := hex.EncodeToString(h.Sum(nil)); wantHash != "" && !strings.EqualFold(got, wantHash) {
+		return "", fmt.Errorf("SHA-256 不一致: 下载的文件为 %s，期望 %s", got, strings.ToLower(wantHash))
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", fmt.Errorf("保存 %s 失败: %v", dst, err)
+	}
+	return dst, nil
+}
+
+// runAddFromURL 下载程序、校验 SHA-256 和数字签名后添加到自启动
+// dir 为空时下载到 defaultDownloadDir；签名无效时删除下载的文件，没有签名时需确认（force 为 true 时跳过）
+func runAddFromURL(rawURL, wantHash, dir, name string, force bool) error {
+	if dir == "" {
+		dir = defaultDownloadDir()
+	}
+	exePath, err := DownloadFile(rawURL, dir, wantHash)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return runAddProgram(exePath, name, "", "", force)
+	}
+	fmt.Printf("已下载到 %s\n", exePath)
+
+	switch status := VerifySignature(exePath); status {
+	case SignatureValid:
+		publisher, _ := GetFilePublisher(exePath)
+		fmt.Printf("数字签名: %s %s\n", status, publisher)
+	case SignatureInvalid:
+		os.Remove(exePath)
+		return fmt.Errorf("%s 的数字签名无效，已删除下载的文件", filepath.Base(exePath))
+	default:
+		fmt.Printf("警告: %s 没有可验证的数字签名（%s）\n", filepath.Base(exePath), status)
+		if !force && !confirmYes("仍然添加到自启动？(y/n): ") {
+			fmt.Printf("已取消，下载的文件保留在 %s\n", exePath)
+			return nil
+		}
+	}
+	return runAddProgram(exePath, name, "", "", force)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsLocalhostURL(t *testing.T) {
+	tests := []struct {
+		rawURL string
+		want   bool
+	}{
+		{"http://localhost:8080/app.exe", true},
+		{"http://LOCALHOST/app.exe", true},
+		{"http://127.0.0.1/app.exe", true},
+		{"http://127.1.2.3/app.exe", true},
+		{"http://[::1]:8080/app.exe", true},
+		{"https://example.com/app.exe", false},
+		{"https://localhost.example.com/app.exe", false},
+		{"http://192.168.1.10/app.exe", false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := isLocalhostURL(u); got != tt.want {
+			t.Errorf("isLocalhostURL(%s) = %v, want %v", tt.rawURL, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadFile(t *testing.T) {
+	setupTestEnv(t)
+	content := []byte("MZ test program")
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tools/app.exe" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	path, err := DownloadFile(server.URL+"/tools/app.exe", dir, strings.ToUpper(hash))
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	if want := filepath.Join(dir, "app.exe"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != string(content) {
+		t.Errorf("下载的内容 = %q (%v), want %q", got, err, content)
+	}
+
+	// 本机地址可以不指定 SHA-256
+	if _, err := DownloadFile(server.URL+"/tools/app.exe", dir, ""); err != nil {
+		t.Errorf("本机地址不指定 SHA-256: %v", err)
+	}
+
+	// SHA-256 不一致时不留下文件
+	other := t.TempDir()
+	if _, err := DownloadFile(server.URL+"/tools/app.exe", other, strings.Repeat("0", 64)); err == nil {
+		t.Error("SHA-256 不一致时应返回错误")
+	}
+	if entries, _ := os.ReadDir(other); len(entries) != 0 {
+		t.Errorf("校验失败后目录中还有文件: %v", entries)
+	}
+
+	for _, tt := range []struct{ rawURL, hash string }{
+		{server.URL + "/missing.exe", ""},
+		{"https://example.com/app.exe", ""},
+		{"https://example.com/app.exe", "1234"},
+		{"ftp://example.com/app.exe", hash},
+		{server.URL + "/", ""},
+	} {
+		if _, err := DownloadFile(tt.rawURL, other, tt.hash); err == nil {
+			t.Errorf("DownloadFile(%q, %q) 应返回错误", tt.rawURL, tt.hash)
+		}
+	}
+}
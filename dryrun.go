@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// 试运行模式（--dry-run）：只输出将要执行的修改，不写入注册表、缓存和其他文件
+var dryRun bool
+
+// 试运行输出的目标，每条修改一行，以 "DRY RUN: " 开头
+var dryRunOutput io.Writer = os.Stdout
+
+// dryRunf 试运行时输出将要执行的修改并返回 true，调用方应跳过实际修改；
+// 非试运行时不输出任何内容，返回 false
+func dryRunf(format string, args ...interface{}) bool {
+	if !dryRun {
+		return false
+	}
+	fmt.Fprintf(dryRunOutput, "DRY RUN: "+format+"\n", args...)
+	return true
+}
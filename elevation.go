@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// IsRunningAsAdmin 检查当前进程是否以管理员权限（已提升的令牌）运行
+func IsRunningAsAdmin() bool {
+	var token windows.Token
+	if err := windows.OpenProcessToken(windows.CurrentProcess(), windows.TOKEN_QUERY, &token); err != nil {
+		return false
+	}
+	defer token.Close()
+
+	var elevation uint32
+	var outLen uint32
+	err := windows.GetTokenInformation(token, windows.TokenElevation,
+		(*byte)(unsafe.Pointer(&elevation)), uint32(unsafe.Sizeof(elevation)), &outLen)
+	if err != nil {
+		return false
+	}
+	return elevation != 0
+}
+
+// RelaunchAsAdmin 以管理员身份重新启动本程序，沿用当前的命令行参数和工作目录
+func RelaunchAsAdmin() error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("获取程序路径失败: %v", err)
+	}
+	cwd, _ := os.Getwd()
+
+	args := make([]string, 0, len(os.Args)-1)
+	for _, arg := range os.Args[1:] {
+		args = append(args, syscall.EscapeArg(arg))
+	}
+
+	verb, _ := windows.UTF16PtrFromString("runas")
+	file, _ := windows.UTF16PtrFromString(exePath)
+	params, _ := windows.UTF16PtrFromString(strings.Join(args, " "))
+	dir, _ := windows.UTF16PtrFromString(cwd)
+
+	if err := windows.ShellExecute(0, verb, file, params, dir, windows.SW_NORMAL); err != nil {
+		return fmt.Errorf("以管理员身份启动失败: %v", err)
+	}
+	return nil
+}
+
+// ensureAdmin 在需要管理员权限的操作之前调用
+// 已是管理员时返回 true；否则询问是否以管理员身份重新启动，确认后启动新进程并退出当前进程
+func ensureAdmin() bool {
+	if IsRunningAsAdmin() {
+		return true
+	}
+
+	if !confirmYes("此操作需要管理员权限，是否以管理员身份重新启动？(y/n): ") {
+		return false
+	}
+	// 先释放实例锁，以免新启动的实例认为已有实例在运行
+	hadLock := instanceLockFile != nil
+	releaseInstanceLock()
+	if err := RelaunchAsAdmin(); err != nil {
+		printError("%v", err)
+		if hadLock {
+			acquireInstanceLock()
+		}
+		return false
+	}
+	os.Exit(0)
+	return false
+}
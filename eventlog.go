@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// 写入应用程序日志时使用的事件源名称
+const eventSource = "AutostartManager"
+
+// 审计事件 ID
+const (
+	EventAdd     uint32 = 1000
+	EventRemove  uint32 = 1001
+	EventEnable  uint32 = 1002
+	EventDisable uint32 = 1003
+)
+
+// 注册表中应用程序日志事件源的位置
+const eventSourceKeyPath = `SYSTEM\CurrentControlSet\Services\EventLog\Application\`
+
+// 为 true 时不写入事件日志（--no-eventlog）
+var noEventLog bool
+
+var (
+	eventSourceOnce  sync.Once
+	eventSourceReady bool
+)
+
+// ensureEventSource 检查事件源是否已注册，未注册时尝试注册
+// 注册需要管理员权限，没有权限时返回 false，调用方跳过记录
+func ensureEventSource(source string) bool {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, eventSourceKeyPath+source, registry.QUERY_VALUE)
+	if err == nil {
+		key.Close()
+		return true
+	}
+	if !IsRunningAsAdmin() {
+		return false
+	}
+	err = eventlog.InstallAsEventCreate(source, eventlog.Info|eventlog.Warning|eventlog.Error)
+	return err == nil
+}
+
+// LogToEventLog 向应用程序日志写入一条事件
+// etype 为 windows.EVENTLOG_INFORMATION_TYPE、EVENTLOG_WARNING_TYPE 或 EVENTLOG_ERROR_TYPE
+func LogToEventLog(source, message string, eid uint32, etype uint16) error {
+	log, err := eventlog.Open(source)
+	if err != nil {
+		return fmt.Errorf("打开事件日志失败: %v", err)
+	}
+	defer log.Close()
+
+	switch etype {
+	case windows.EVENTLOG_WARNING_TYPE:
+		err = log.Warning(eid, message)
+	case windows.EVENTLOG_ERROR_TYPE:
+		err = log.Error(eid, message)
+	default:
+		err = log.Info(eid, message)
+	}
+	if err != nil {
+		return fmt.Errorf("写入事件日志失败: %v", err)
+	}
+	return nil
+}
+
+// auditEvent 记录一次启动项修改，包括启动项名称、当前用户和时间
+// 事件源未注册且无法注册时静默跳过，记录失败不影响操作本身
+func auditEvent(eid uint32, action, name string) {
+	if noEventLog || dryRun {
+		return
+	}
+	eventSourceOnce.Do(func() {
+		eventSourceReady = ensureEventSource(eventSource)
+	})
+	if !eventSourceReady {
+		return
+	}
+
+	username := currentUsername()
+	if username == "" {
+		username = "未知"
+	}
+	message := fmt.Sprintf("%s启动项: %s\r\n用户: %s\r\n时间: %s",
+		action, name, username, time.Now().Format("2006-01-02 15:04:05"))
+	LogToEventLog(eventSource, message, eid, windows.EVENTLOG_INFORMATION_TYPE)
+}
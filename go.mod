@@ -3,3 +3,5 @@ module autostart
 go 1.21
 
 require golang.org/x/sys v0.15.0
+
+require github.com/BurntSushi/toml v1.6.0
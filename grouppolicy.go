@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// ErrReadOnly 启动项由组策略下发，本工具不能修改
+var ErrReadOnly = errors.New("启动项由组策略管理，不能修改")
+
+const (
+	// 组策略“登录时运行这些程序”写入的位置，值名一般为 1、2、3……
+	policyRunKeyPath = `Software\Microsoft\Windows\CurrentVersion\Policies\Explorer\Run`
+	// 组策略脚本的位置，其下为 <GPO 序号>\<脚本序号>，每个脚本有 Script 和 Parameters 两个值
+	policyScriptsKeyPath = `Software\Policies\Microsoft\Windows\System\Scripts`
+)
+
+// ListGroupPolicyStartupEntries 读取组策略下发的启动项（HKLM 和 HKCU）
+// 包括“登录时运行这些程序”以及计算机启动脚本和用户登录脚本
+func ListGroupPolicyStartupEntries() ([]CacheItem, error) {
+	var items []CacheItem
+	for _, scope := range []RegistryScope{ScopeCurrentUser, ScopeMachineWide} {
+		runItems, err := readPolicyRunKey(scope)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, runItems...)
+
+		// 计算机策略的脚本在开机时运行，用户策略的脚本在登录时运行
+		scriptType := "Logon"
+		if scope == ScopeMachineWide {
+			scriptType = "Startup"
+		}
+		scriptItems, err := readPolicyScripts(scope, policyScriptsKeyPath+`\`+scriptType)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, scriptItems...)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+// readPolicyRunKey 读取 scope 下组策略的 Run 键
+func readPolicyRunKey(scope RegistryScope) ([]CacheItem, error) {
+	key, err := registry.OpenKey(scope.rootKey(), policyRunKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		if err == registry.ErrNotExist {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("打开注册表失败: %v", err)
+	}
+	defer key.Close()
+
+	var items []CacheItem
+	for name, value := range readKeyValues(key) {
+		items = append(items, CacheItem{Name: name, Value: value, Enabled: true, Scope: scope})
+	}
+	return items, nil
+}
+
+// readPolicyScripts 读取 scope 下 path 中的组策略脚本，以脚本文件名作为名称
+func readPolicyScripts(scope RegistryScope, path string) ([]CacheItem, error) {
+	gpos, err := registry.OpenKey(scope.rootKey(), path, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		if err == registry.ErrNotExist {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("打开注册表失败: %v", err)
+	}
+	defer gpos.Close()
+
+	gpoNames, err := gpos.ReadSubKeyNames(0)
+	if err != nil {
+		return nil, fmt.Errorf("读取注册表失败: %v", err)
+	}
+
+	var items []CacheItem
+	for _, gpo := range gpoNames {
+		scripts, err := registry.OpenKey(scope.rootKey(), path+`\`+gpo, registry.ENUMERATE_SUB_KEYS)
+		if err != nil {
+			continue
+		}
+		scriptNames, _ := scripts.ReadSubKeyNames(0)
+		scripts.Close()
+
+		for _, index := range scriptNames {
+			key, err := registry.OpenKey(scope.rootKey(), path+`\`+gpo+`\`+index, registry.QUERY_VALUE)
+			if err != nil {
+				continue
+			}
+			script, _, err := key.GetStringValue("Script")
+			params, _, _ := key.GetStringValue("Parameters")
+			key.Close()
+			if err != nil || script == "" {
+				continue
+			}
+
+			items = append(items, CacheItem{
+				Name:    filepath.Base(script),
+				Value:   buildCommand(script, params),
+				Enabled: true,
+				Scope:   scope,
+			})
+		}
+	}
+	return items, nil
+}
+
+// isGroupPolicyEntry 检查名称是否属于组策略下发的启动项
+func isGroupPolicyEntry(name string) bool {
+	items, err := ListGroupPolicyStartupEntries()
+	if err != nil {
+		return false
+	}
+	for _, item := range items {
+		if item.Name == name {
+			return true
+		}
+	}
+	return false
+}
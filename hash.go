@@ -0,0 +1,158 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// HashMismatch 记录的文件哈希与当前文件不一致的启动项
+type HashMismatch struct {
+	Name        string
+	Path        string
+	StoredHash  string
+	CurrentHash string // 文件无法读取时为空
+}
+
+// computeFileHash 计算文件内容的 SHA-256，返回十六进制字符串
+func computeFileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// entryFilePath 获取启动项实际启动的文件路径
+// 批处理和 PowerShell 脚本通过解释器启动，返回参数中的脚本路径
+func entryFilePath(item CacheItem) (string, error) {
+	exePath, args, err := parseCommandPath(unwrapWindowState(item.Value, item.Name, item.WindowState))
+	if err != nil {
+		return "", err
+	}
+
+	if item.Type == fileTypeBatch || item.Type == fileTypePowerShell {
+		start := strings.Index(args, `"`)
+		if start >= 0 {
+			if end := strings.Index(args[start+1:], `"`); end >= 0 {
+				return args[start+1 : start+1+end], nil
+			}
+		}
+	}
+	return resolveExecutable(exePath)
+}
+
+// entryModTime 获取启动项程序文件当前的修改时间
+func entryModTime(item CacheItem) (time.Time, bool) {
+	path, err := entryFilePath(item)
+	if err != nil {
+		return time.Time{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// refreshFileModTime 记录程序文件当前的修改时间，文件无法读取时保留原来的记录
+// 修改启动命令、文件类型或窗口状态后都需要重新记录
+func refreshFileModTime(item *CacheItem) {
+	if modTime, ok := entryModTime(*item); ok {
+		item.FileModTime = modTime
+	}
+}
+
+// modTimeChanged 检查程序文件的修改时间是否与记录的不同，返回当前的修改时间
+// 未记录修改时间或文件无法读取时视为未修改
+func modTimeChanged(item CacheItem) (time.Time, bool) {
+	current, ok := entryModTime(item)
+	if !ok || item.FileModTime.IsZero() {
+		return current, false
+	}
+	return current, !current.Equal(item.FileModTime)
+}
+
+// FindModifiedEntries 返回程序文件的修改时间与记录不一致的启动项
+func FindModifiedEntries() ([]CacheItem, error) {
+	cache, err := loadCache()
+	if err != nil {
+		return nil, err
+	}
+
+	var modified []CacheItem
+	for _, item := range cache.Items {
+		if _, changed := modTimeChanged(item); changed {
+			modified = append(modified, item)
+		}
+	}
+	return modified, nil
+}
+
+// VerifyEntryHashes 将缓存中记录的文件哈希与当前文件比较，返回不一致的启动项
+// 未记录哈希的启动项不参与校验
+func VerifyEntryHashes() ([]HashMismatch, error) {
+	cache, err := loadCache()
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []HashMismatch
+	for _, item := range cache.Items {
+		if item.FileHash == "" {
+			continue
+		}
+
+		path, err := entryFilePath(item)
+		if err != nil {
+			// 文件不存在由其他校验项报告
+			continue
+		}
+
+		current, _ := computeFileHash(path)
+		if !strings.EqualFold(current, item.FileHash) {
+			mismatches = append(mismatches, HashMismatch{
+				Name:        item.Name,
+				Path:        path,
+				StoredHash:  item.FileHash,
+				CurrentHash: current,
+			})
+		}
+	}
+	return mismatches, nil
+}
+
+// updateEntryHash 将启动项记录的哈希更新为文件当前的哈希
+func updateEntryHash(name string) error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	idx, _ := findItemByName(cache, name)
+	if idx < 0 {
+		return fmt.Errorf("启动项不存在")
+	}
+
+	path, err := entryFilePath(cache.Items[idx])
+	if err != nil {
+		return err
+	}
+	hash, err := computeFileHash(path)
+	if err != nil {
+		return fmt.Errorf("计算文件哈希失败: %v", err)
+	}
+
+	cache.Items[idx].FileHash = hash
+	return saveCache(cache)
+}
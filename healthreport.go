@@ -0,0 +1,307 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 健康报告中最多列出的历史记录数
+const reportHistoryLimit = 20
+
+// 健康报告中签名状态的显示顺序和颜色
+var reportSignatureColors = []struct {
+	Status SignatureStatus
+	Color  string
+}{
+	{SignatureValid, "#28a745"},
+	{SignatureNone, "#ffc107"},
+	{SignatureInvalid, "#dc3545"},
+	{SignatureUnknown, "#6c757d"},
+}
+
+// reportItem 健康报告中的一个启动项
+type reportItem struct {
+	Item      CacheItem
+	Orphaned  bool
+	Signature SignatureStatus
+	Risk      RiskLevel
+	Publisher string
+}
+
+// reportHistory 健康报告中的一条修改记录
+type reportHistory struct {
+	Name   string
+	Record HistoryRecord
+}
+
+// healthReport 生成健康报告所需的数据
+type healthReport struct {
+	Hostname    string
+	GeneratedAt time.Time
+	Items       []reportItem
+	Issues      []ValidationResult
+	History     []reportHistory // 最新的在前
+}
+
+// pieSlice 饼图中的一块
+type pieSlice struct {
+	Label string
+	Value int
+	Color string
+}
+
+// collectHealthReport 收集启动项的签名、风险、校验问题和最近的修改记录
+func collectHealthReport(items []CacheItem) (*healthReport, error) {
+	issues, err := ValidateStartupEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	report := &healthReport{Hostname: hostname, GeneratedAt: time.Now(), Issues: issues}
+	for _, item := range sortByPriority(items) {
+		ri := reportItem{Item: item, Orphaned: isOrphaned(item), Signature: SignatureUnknown, Risk: entryRisk(item)}
+		if path, err := entryFilePath(item); err == nil {
+			ri.Signature = VerifySignature(path)
+			ri.Publisher, _ = GetFilePublisher(path)
+		}
+		report.Items = append(report.Items, ri)
+
+		for _, record := range item.History {
+			report.History = append(report.History, reportHistory{Name: item.Name, Record: record})
+		}
+	}
+
+	sort.SliceStable(report.History, func(i, j int) bool {
+		return report.History[i].Record.Timestamp.After(report.History[j].Record.Timestamp)
+	})
+	if len(report.History) > reportHistoryLimit {
+		report.History = report.History[:reportHistoryLimit]
+	}
+	return report, nil
+}
+
+// pieChartSVG 生成内联 SVG 饼图和图例，数量为 0 的块不显示
+func pieChartSVG(slices []pieSlice) string {
+	total := 0
+	for _, s := range slices {
+		total += s.Value
+	}
+
+	const cx, cy, r = 80.0, 80.0, 70.0
+	var sb strings.Builder
+	sb.WriteString("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"360\" height=\"160\" viewBox=\"0 0 360 160\" role=\"img\">\r\n")
+	if total == 0 {
+		sb.WriteString(fmt.Sprintf("<circle cx=\"%g\" cy=\"%g\" r=\"%g\" fill=\"#e9ecef\"/>\r\n", cx, cy, r))
+	}
+
+	angle := -math.Pi / 2 // 从正上方开始顺时针绘制
+	legendY := 20
+	for _, s := range slices {
+		if s.Value == 0 {
+			continue
+		}
+		label := html.EscapeString(fmt.Sprintf("%s: %d", s.Label, s.Value))
+		if s.Value == total {
+			// 只有一块时画整圆，路径的起点和终点重合会画不出来
+			sb.WriteString(fmt.Sprintf("<circle cx=\"%g\" cy=\"%g\" r=\"%g\" fill=\"%s\"><title>%s</title></circle>\r\n", cx, cy, r, s.Color, label))
+		} else {
+			sweep := 2 * math.Pi * float64(s.Value) / float64(total)
+			x1, y1 := cx+r*math.Cos(angle), cy+r*math.Sin(angle)
+			x2, y2 := cx+r*math.Cos(angle+sweep), cy+r*math.Sin(angle+sweep)
+			largeArc := 0
+			if sweep > math.Pi {
+				largeArc = 1
+			}
+			sb.WriteString(fmt.Sprintf("<path d=\"M%g,%g L%.2f,%.2f A%g,%g 0 %d,1 %.2f,%.2f Z\" fill=\"%s\"><title>%s</title></path>\r\n",
+				cx, cy, x1, y1, r, r, largeArc, x2, y2, s.Color, label))
+			angle += sweep
+		}
+
+		sb.WriteString(fmt.Sprintf("<rect x=\"180\" y=\"%d\" width=\"12\" height=\"12\" fill=\"%s\"/>", legendY, s.Color))
+		sb.WriteString(fmt.Sprintf("<text x=\"198\" y=\"%d\" font-size=\"13\">%s</text>\r\n", legendY+11, label))
+		legendY += 22
+	}
+	sb.WriteString("</svg>\r\n")
+	return sb.String()
+}
+
+// reportBadge 生成带背景色的标签
+func reportBadge(text, color string) string {
+	return "<span style=\"display:inline-block;padding:1px 6px;margin:1px 2px 1px 0;border-radius:8px;background:" + color +
+		";color:#fff;font-size:12px;white-space:nowrap\">" + html.EscapeString(text) + "</span>"
+}
+
+// signatureColor 签名状态在报告中的颜色
+func signatureColor(status SignatureStatus) string {
+	for _, c := range reportSignatureColors {
+		if c.Status == status {
+			return c.Color
+		}
+	}
+	return "#6c757d"
+}
+
+// riskColor 风险等级在报告中的颜色
+func riskColor(level RiskLevel) string {
+	switch level {
+	case RiskHigh:
+		return "#dc3545"
+	case RiskMedium:
+		return "#fd7e14"
+	}
+	return "#28a745"
+}
+
+// renderHealthReport 将健康报告输出为 HTML，与 ExportToHTML 一样只使用内联样式，不依赖外部资源
+func renderHealthReport(w io.Writer, report *healthReport) error {
+	enabled, disabled, orphaned := 0, 0, 0
+	signatures := make(map[SignatureStatus]int)
+	for _, ri := range report.Items {
+		if ri.Item.Enabled {
+			enabled++
+		} else {
+			disabled++
+		}
+		if ri.Orphaned {
+			orphaned++
+		}
+		signatures[ri.Signature]++
+	}
+
+	const cellStyle = "padding:6px 8px;border:1px solid #ccc;vertical-align:top;word-break:break-all"
+	const headStyle = "text-align:left;padding:6px 8px;border:1px solid #ccc;background:#f0f0f0"
+	const h2Style = "font-size:16px;margin:24px 0 8px"
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\r\n<html lang=\"zh-CN\">\r\n<head>\r\n")
+	sb.WriteString("<meta charset=\"utf-8\">\r\n")
+	sb.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\r\n")
+	sb.WriteString("<title>自启动项健康报告 - " + html.EscapeString(report.Hostname) + "</title>\r\n")
+	sb.WriteString("</head>\r\n")
+	sb.WriteString("<body style=\"margin:16px;font-family:'Segoe UI','Microsoft YaHei',sans-serif;font-size:14px;color:#222\">\r\n")
+	sb.WriteString("<h1 style=\"font-size:20px;margin:0 0 8px\">自启动项健康报告</h1>\r\n")
+	sb.WriteString(fmt.Sprintf("<p style=\"margin:0 0 16px;color:#555\">计算机名: %s<br>生成时间: %s</p>\r\n",
+		html.EscapeString(report.Hostname), report.GeneratedAt.Format("2006-01-02 15:04:05")))
+
+	// 摘要
+	sb.WriteString("<h2 style=\"" + h2Style + "\">摘要</h2>\r\n<p style=\"margin:0\">")
+	sb.WriteString(fmt.Sprintf("共 %d 项：%s %s %s %s", len(report.Items),
+		reportBadge(fmt.Sprintf("启用 %d", enabled), "#28a745"),
+		reportBadge(fmt.Sprintf("禁用 %d", disabled), "#6c757d"),
+		reportBadge(fmt.Sprintf("失效 %d", orphaned), "#fd7e14"),
+		reportBadge(fmt.Sprintf("问题 %d", len(report.Issues)), "#dc3545")))
+	sb.WriteString("</p>\r\n")
+
+	// 签名状态
+	sb.WriteString("<h2 style=\"" + h2Style + "\">数字签名</h2>\r\n")
+	slices := make([]pieSlice, 0, len(reportSignatureColors))
+	for _, c := range reportSignatureColors {
+		slices = append(slices, pieSlice{Label: c.Status.String(), Value: signatures[c.Status], Color: c.Color})
+	}
+	sb.WriteString(pieChartSVG(slices))
+
+	// 校验问题
+	sb.WriteString("<h2 style=\"" + h2Style + "\">校验问题</h2>\r\n")
+	if len(report.Issues) == 0 {
+		sb.WriteString("<p style=\"margin:0\">没有发现问题。</p>\r\n")
+	} else {
+		sb.WriteString("<ul style=\"margin:0;padding-left:20px\">\r\n")
+		for _, issue := range report.Issues {
+			level, color := "警告", "#fd7e14"
+			if issue.Severity == SeverityError {
+				level, color = "错误", "#dc3545"
+			}
+			sb.WriteString("<li>" + reportBadge(level, color) + html.EscapeString(issue.Name+": "+issue.Issue) + "</li>\r\n")
+		}
+		sb.WriteString("</ul>\r\n")
+	}
+
+	// 启动项
+	sb.WriteString("<h2 style=\"" + h2Style + "\">启动项</h2>\r\n")
+	sb.WriteString("<div style=\"overflow-x:auto\">\r\n")
+	sb.WriteString("<table style=\"border-collapse:collapse;width:100%;min-width:720px\">\r\n<tr>")
+	for _, column := range []string{"名称", "命令", "状态", "发布者", "标签", "添加时间"} {
+		sb.WriteString("<th style=\"" + headStyle + "\">" + column + "</th>")
+	}
+	sb.WriteString("</tr>\r\n")
+	for _, ri := range report.Items {
+		badges := reportBadge("启用", "#28a745")
+		if !ri.Item.Enabled {
+			badges = reportBadge("禁用", "#6c757d")
+		}
+		if ri.Orphaned {
+			badges += reportBadge("失效", "#fd7e14")
+		}
+		badges += reportBadge(ri.Signature.String(), signatureColor(ri.Signature))
+		badges += reportBadge(ri.Risk.String(), riskColor(ri.Risk))
+
+		createdAt := ""
+		if !ri.Item.CreatedAt.IsZero() {
+			createdAt = ri.Item.CreatedAt.Format("2006-01-02 15:04")
+		}
+
+		sb.WriteString("<tr>")
+		sb.WriteString("<td style=\"" + cellStyle + "\">" + html.EscapeString(ri.Item.Name) + "</td>")
+		sb.WriteString("<td style=\"" + cellStyle + "\">" + html.EscapeString(ri.Item.Value) + "</td>")
+		sb.WriteString("<td style=\"" + cellStyle + "\">" + badges + "</td>")
+		for _, cell := range []string{ri.Publisher, strings.Join(ri.Item.Tags, ", "), createdAt} {
+			sb.WriteString("<td style=\"" + cellStyle + "\">" + html.EscapeString(cell) + "</td>")
+		}
+		sb.WriteString("</tr>\r\n")
+	}
+	sb.WriteString("</table>\r\n</div>\r\n")
+
+	// 最近的修改
+	sb.WriteString("<h2 style=\"" + h2Style + "\">最近的修改</h2>\r\n")
+	if len(report.History) == 0 {
+		sb.WriteString("<p style=\"margin:0\">没有修改记录。</p>\r\n")
+	} else {
+		sb.WriteString("<ul style=\"margin:0;padding-left:20px\">\r\n")
+		for _, h := range report.History {
+			line := fmt.Sprintf("[%s] %s %s", h.Record.Timestamp.Format("2006-01-02 15:04:05"), historyActionLabel(h.Record.Action), h.Name)
+			if h.Record.User != "" {
+				line += "（" + h.Record.User + "）"
+			}
+			sb.WriteString("<li>" + html.EscapeString(line) + "</li>\r\n")
+		}
+		sb.WriteString("</ul>\r\n")
+	}
+
+	sb.WriteString("</body>\r\n</html>\r\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// GenerateReport 生成启动项健康报告并写入 .html 文件，不支持 PDF
+func GenerateReport(path string) error {
+	if strings.EqualFold(filepath.Ext(path), ".pdf") {
+		return fmt.Errorf("不支持生成 PDF，请生成 .html 报告后在浏览器中打印为 PDF")
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	report, err := collectHealthReport(cache.Items)
+	if err != nil {
+		return err
+	}
+
+	if dryRunf("生成健康报告 %s（%d 项）", path, len(report.Items)) {
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return renderHealthReport(file, report)
+}
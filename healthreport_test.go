@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPieChartSVG(t *testing.T) {
+	svg := pieChartSVG([]pieSlice{
+		{Label: "已签名", Value: 3, Color: "#28a745"},
+		{Label: "未签名", Value: 1, Color: "#ffc107"},
+		{Label: "签名无效", Value: 0, Color: "#dc3545"},
+	})
+	if got := strings.Count(svg, "<path "); got != 2 {
+		t.Errorf("有 %d 块扇形，want 2: %s", got, svg)
+	}
+	if !strings.Contains(svg, "0 1,1") {
+		t.Errorf("占 3/4 的扇形应使用大弧: %s", svg)
+	}
+	if strings.Contains(svg, "签名无效") {
+		t.Errorf("数量为 0 的块不应出现在图例中: %s", svg)
+	}
+
+	// 只有一块时画整圆
+	svg = pieChartSVG([]pieSlice{{Label: "已签名", Value: 2, Color: "#28a745"}})
+	if strings.Contains(svg, "<path ") || !strings.Contains(svg, `fill="#28a745"><title>`) {
+		t.Errorf("只有一块时应画整圆: %s", svg)
+	}
+}
+
+func TestRenderHealthReport(t *testing.T) {
+	report := &healthReport{
+		Hostname:    "PC<1>",
+		GeneratedAt: time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local),
+		Items: []reportItem{
+			{Item: CacheItem{Name: "App", Value: `"C:\app.exe"`, Enabled: true}, Signature: SignatureValid},
+			{Item: CacheItem{Name: "Old", Value: `"C:\old.exe"`}, Orphaned: true, Signature: SignatureUnknown, Risk: RiskHigh},
+		},
+		Issues: []ValidationResult{{Name: "Old", Issue: "程序文件不存在", Severity: SeverityError}},
+		History: []reportHistory{
+			{Name: "App", Record: HistoryRecord{Timestamp: time.Date(2026, 10, 13, 8, 0, 0, 0, time.Local), Action: HistoryAdded}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderHealthReport(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"PC&lt;1&gt;", "共 2 项", "启用 1", "禁用 1", "失效 1", "问题 1", "<svg", "已签名: 1", "未知: 1",
+		"Old: 程序文件不存在", "高风险", "[2026-10-13 08:00:00] 添加 App"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("报告中没有 %q", want)
+		}
+	}
+}
+
+func TestGenerateReportRejectsPDF(t *testing.T) {
+	setupTestEnv(t)
+	if err := GenerateReport(filepath.Join(t.TempDir(), "report.pdf")); err == nil {
+		t.Error("生成 PDF 应返回错误")
+	}
+}
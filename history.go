@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os/user"
+	"strings"
+	"time"
+)
+
+// 历史记录中的操作
+const (
+	HistoryAdded    = "added"
+	HistoryRemoved  = "removed"
+	HistoryEnabled  = "enabled"
+	HistoryDisabled = "disabled"
+	HistoryEdited   = "edited"
+	HistoryRenamed  = "renamed"
+	HistoryMoved    = "moved" // 在当前用户和所有用户（HKLM）之间移动，新旧值为注册表根键
+
+	HistoryRunOnceFired = "runonce_fired" // RunOnce 项已运行并被系统删除
+)
+
+// 每个启动项最多保留的历史记录数，超出时丢弃最早的记录
+const maxHistoryRecords = 50
+
+// HistoryRecord 启动项的一次修改记录
+type HistoryRecord struct {
+	Timestamp time.Time `json:"timestamp" toml:"timestamp"`
+	Action    string    `json:"action" toml:"action"`
+	OldValue  string    `json:"old_value,omitempty" toml:"old_value,omitempty"`
+	NewValue  string    `json:"new_value,omitempty" toml:"new_value,omitempty"`
+	User      string    `json:"user,omitempty" toml:"user,omitempty"`
+}
+
+// currentUsername 获取当前用户名（DOMAIN\user），获取失败时返回空字符串
+func currentUsername() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// recordHistory 在启动项的历史中追加一条记录
+func recordHistory(item *CacheItem, action, oldValue, newValue string) {
+	item.History = append(item.History, HistoryRecord{
+		Timestamp: time.Now(),
+		Action:    action,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		User:      currentUsername(),
+	})
+	if len(item.History) > maxHistoryRecords {
+		item.History = item.History[len(item.History)-maxHistoryRecords:]
+	}
+}
+
+// historyActionLabel 历史记录中操作的显示名称
+func historyActionLabel(action string) string {
+	switch action {
+	case HistoryAdded:
+		return "添加"
+	case HistoryRemoved:
+		return "移除"
+	case HistoryEnabled:
+		return "启用"
+	case HistoryDisabled:
+		return "禁用"
+	case HistoryEdited:
+		return "修改"
+	case HistoryRenamed:
+		return "重命名"
+	case HistoryMoved:
+		return "移动"
+	case HistoryRunOnceFired:
+		return "RunOnce 已运行"
+	}
+	return action
+}
+
+// handleShowHistory 处理查看启动项的修改历史
+func handleShowHistory() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	items := make([]ListItem, 0, len(cache.Items))
+	for _, item := range cache.Items {
+		items = append(items, ListItem{
+			Name:  fmt.Sprintf("%s（%d 条记录）", item.Name, len(item.History)),
+			Value: item.Value,
+		})
+	}
+
+	idx, ok := showListWithBack(items, "选择要查看历史的启动项", pageSize)
+	if !ok {
+		return
+	}
+
+	selected := cache.Items[idx]
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Printf("%s 的修改历史：\n", selected.Name)
+	fmt.Println(strings.Repeat("=", 60))
+
+	if len(selected.History) == 0 {
+		fmt.Println("没有历史记录。")
+		return
+	}
+
+	// 最新的记录显示在最前面
+	for i := len(selected.History) - 1; i >= 0; i-- {
+		record := selected.History[i]
+		fmt.Printf("[%s] %s", record.Timestamp.Format("2006-01-02 15:04:05"), historyActionLabel(record.Action))
+		if record.User != "" {
+			fmt.Printf("（%s）", record.User)
+		}
+		fmt.Println()
+		if record.OldValue != "" {
+			fmt.Printf("   原: %s\n", record.OldValue)
+		}
+		if record.NewValue != "" {
+			fmt.Printf("   新: %s\n", record.NewValue)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// 为 true 时不检查缓存文件是否来自其他电脑或其他用户（--ignore-host-check）
+var ignoreHostCheck bool
+
+// 每次运行只检查一次缓存文件的来源
+var hostCheckOnce sync.Once
+
+// currentHostname 获取本机名称，获取失败时返回空字符串
+func currentHostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// stampCacheOwner 在缓存中记录写入缓存的电脑和用户
+func stampCacheOwner(data *CacheData) {
+	data.Hostname = currentHostname()
+	data.Username = currentUsername()
+}
+
+// checkCacheOwner 检查缓存是否由本机的当前用户写入
+// 不一致时显示警告并询问是否继续，用户拒绝时退出程序；旧版本写入的缓存没有记录来源，不检查
+func checkCacheOwner(data *CacheData) {
+	if ignoreHostCheck || (data.Hostname == "" && data.Username == "") {
+		return
+	}
+
+	hostname, username := currentHostname(), currentUsername()
+	sameHost := data.Hostname == "" || hostname == "" || strings.EqualFold(data.Hostname, hostname)
+	sameUser := data.Username == "" || username == "" || strings.EqualFold(data.Username, username)
+	if sameHost && sameUser {
+		return
+	}
+
+	hostCheckOnce.Do(func() {
+		fmt.Println()
+		printer.Print(fmt.Sprintf("警告: 此缓存由 %s 上的 %s 创建，其中的路径在本机上可能无效。\n", data.Hostname, data.Username), colorYellow)
+		fmt.Println("如果是有意复制到本机的，可以使用 --ignore-host-check 跳过此检查。")
+		if !confirmYes("是否继续？(y/n): ") {
+			os.Exit(1)
+		}
+	})
+}
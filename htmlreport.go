@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// HTML 报告中各状态行的背景色
+const (
+	htmlColorEnabled  = "#d4edda"
+	htmlColorDisabled = "#f8d7da"
+	htmlColorOrphaned = "#ffe0b2"
+)
+
+// HTML 报告中显示的文件哈希长度
+const htmlHashLength = 12
+
+// ExportToHTML 将启动项导出为 HTML 报告
+// 只使用内联样式，不依赖外部资源，可作为邮件附件离线查看
+func ExportToHTML(w io.Writer, items []CacheItem) error {
+	hostname, _ := os.Hostname()
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\r\n<html lang=\"zh-CN\">\r\n<head>\r\n")
+	sb.WriteString("<meta charset=\"utf-8\">\r\n")
+	sb.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\r\n")
+	sb.WriteString("<title>自启动项报告 - " + html.EscapeString(hostname) + "</title>\r\n")
+	sb.WriteString("</head>\r\n")
+	sb.WriteString("<body style=\"margin:16px;font-family:'Segoe UI','Microsoft YaHei',sans-serif;font-size:14px;color:#222\">\r\n")
+	sb.WriteString("<h1 style=\"font-size:20px;margin:0 0 8px\">自启动项报告</h1>\r\n")
+	sb.WriteString(fmt.Sprintf("<p style=\"margin:0 0 16px;color:#555\">计算机名: %s<br>导出时间: %s<br>共 %d 项</p>\r\n",
+		html.EscapeString(hostname), time.Now().Format("2006-01-02 15:04:05"), len(items)))
+
+	// 外层容器允许窄屏上横向滚动
+	sb.WriteString("<div style=\"overflow-x:auto\">\r\n")
+	sb.WriteString("<table style=\"border-collapse:collapse;width:100%;min-width:720px\">\r\n<tr>")
+	for _, column := range []string{"名称", "命令", "状态", "标签", "备注", "版本", "发布者", "SHA-256", "添加时间"} {
+		sb.WriteString("<th style=\"text-align:left;padding:6px 8px;border:1px solid #ccc;background:#f0f0f0\">" + column + "</th>")
+	}
+	sb.WriteString("</tr>\r\n")
+
+	for _, item := range items {
+		status, background := "启用", htmlColorEnabled
+		if !item.Enabled {
+			status, background = "禁用", htmlColorDisabled
+		}
+		if isOrphaned(item) {
+			status, background = status+"（失效）", htmlColorOrphaned
+		}
+
+		version, publisher := item.FileVersion, ""
+		if path, err := entryFilePath(item); err == nil {
+			if version == "" {
+				version, _ = GetFileVersion(path)
+			}
+			publisher, _ = GetFilePublisher(path)
+		}
+
+		hash := item.FileHash
+		if len(hash) > htmlHashLength {
+			hash = hash[:htmlHashLength] + "…"
+		}
+
+		createdAt := ""
+		if !item.CreatedAt.IsZero() {
+			createdAt = item.CreatedAt.Format("2006-01-02 15:04")
+		}
+
+		sb.WriteString("<tr style=\"background:" + background + "\">")
+		for _, cell := range []string{item.Name, item.Value, status, strings.Join(item.Tags, ", "),
+			item.Notes, version, publisher, hash, createdAt} {
+			sb.WriteString("<td style=\"padding:6px 8px;border:1px solid #ccc;vertical-align:top;word-break:break-all\">" +
+				html.EscapeString(cell) + "</td>")
+		}
+		sb.WriteString("</tr>\r\n")
+	}
+
+	sb.WriteString("</table>\r\n</div>\r\n</body>\r\n</html>\r\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// exportHTMLFile 将启动项导出为 .html 文件
+func exportHTMLFile(path string, items []CacheItem) error {
+	if dryRunf("导出 %d 项到 %s", len(items), path) {
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return ExportToHTML(file, items)
+}
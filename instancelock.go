@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// 实例锁文件名，与缓存文件位于同一目录，内容为持有锁的进程 PID
+const instanceLockFileName = "autostart.pid"
+
+// ErrAnotherInstance 另一个交互式实例正在运行
+var ErrAnotherInstance = errors.New("另一个实例正在运行")
+
+// 当前持有的实例锁文件
+var instanceLockFile *os.File
+
+// instanceLockPath 获取实例锁文件的路径
+func instanceLockPath() string {
+	return filepath.Join(filepath.Dir(cacheFilePath), instanceLockFileName)
+}
+
+// lockRegion 实例锁锁定的字节范围位于文件内容之后，其他实例仍可读取文件中的 PID
+func lockRegion() *windows.Overlapped {
+	return &windows.Overlapped{OffsetHigh: 1}
+}
+
+// acquireInstanceLock 对实例锁文件加排他锁并写入当前进程的 PID
+// 另一个实例持有锁时返回 ErrAnotherInstance，错误信息中包含其 PID；返回的函数释放锁
+func acquireInstanceLock() (func(), error) {
+	// 试运行不创建锁文件
+	if dryRun {
+		return func() {}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheFilePath), 0755); err != nil {
+		return nil, fmt.Errorf("创建缓存目录失败: %v", err)
+	}
+
+	path := instanceLockPath()
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开实例锁文件失败: %v", err)
+	}
+
+	err = windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0, 1, 0, lockRegion())
+	if err != nil {
+		file.Close()
+		if err == windows.ERROR_LOCK_VIOLATION {
+			pid := "未知"
+			if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) != "" {
+				pid = strings.TrimSpace(string(data))
+			}
+			return nil, fmt.Errorf("%w（PID %s）", ErrAnotherInstance, pid)
+		}
+		return nil, fmt.Errorf("锁定实例锁文件失败: %v", err)
+	}
+
+	// 上次异常退出时留下的文件可能有旧的 PID
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+
+	instanceLockFile = file
+	return releaseInstanceLock, nil
+}
+
+// releaseInstanceLock 释放实例锁并删除锁文件
+func releaseInstanceLock() {
+	if instanceLockFile == nil {
+		return
+	}
+	windows.UnlockFileEx(windows.Handle(instanceLockFile.Fd()), 0, 1, 0, lockRegion())
+	instanceLockFile.Close()
+	os.Remove(instanceLockFile.Name())
+	instanceLockFile = nil
+}
@@ -0,0 +1,222 @@
+//go:build integration
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// 集成测试使用的临时注册表键，测试期间的 Run 键重定向到这里
+const testRunKeyPath = `Software\AutostartManagerTest`
+
+// scratchRegistry 读写 HKCU 下真实的注册表，但所有路径都重定向到 testRunKeyPath 下
+type scratchRegistry struct{}
+
+// redirect 将 Run 键映射为 testRunKeyPath，其他键映射为它下面的同名子键
+func (scratchRegistry) redirect(path string) string {
+	if path == runKeyPath {
+		return testRunKeyPath
+	}
+	return testRunKeyPath + `\` + path
+}
+
+func (r scratchRegistry) ReadValues(path string) (map[string]string, error) {
+	return currentUserRegistry{}.ReadValues(r.redirect(path))
+}
+
+func (r scratchRegistry) GetValue(path, name string) (string, error) {
+	return currentUserRegistry{}.GetValue(r.redirect(path), name)
+}
+
+func (r scratchRegistry) SetValue(path, name, value string) error {
+	return currentUserRegistry{}.SetValue(r.redirect(path), name, value)
+}
+
+func (r scratchRegistry) DeleteValue(path, name string) error {
+	return currentUserRegistry{}.DeleteValue(r.redirect(path), name)
+}
+
+func TestMain(m *testing.M) {
+	if err := resetTestKey(); err != nil {
+		fmt.Fprintf(os.Stderr, "创建测试注册表键失败: %v\n", err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	if err := deleteKeyTree(registry.CURRENT_USER, testRunKeyPath); err != nil {
+		fmt.Fprintf(os.Stderr, "删除测试注册表键失败: %v\n", err)
+		if code == 0 {
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
+// resetTestKey 清空并重新创建测试注册表键
+func resetTestKey() error {
+	if err := deleteKeyTree(registry.CURRENT_USER, testRunKeyPath); err != nil {
+		return err
+	}
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, testRunKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	return key.Close()
+}
+
+// deleteKeyTree 删除注册表键及其所有子键，键不存在时不报错
+func deleteKeyTree(root registry.Key, path string) error {
+	key, err := registry.OpenKey(root, path, registry.ENUMERATE_SUB_KEYS)
+	if err == registry.ErrNotExist {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	subKeys, err := key.ReadSubKeyNames(0)
+	key.Close()
+	if err != nil {
+		return err
+	}
+	for _, sub := range subKeys {
+		if err := deleteKeyTree(root, path+`\`+sub); err != nil {
+			return err
+		}
+	}
+	return registry.DeleteKey(root, path)
+}
+
+// setupIntegrationEnv 在 setupTestEnv 的基础上改用测试注册表键，测试结束后清空该键
+func setupIntegrationEnv(t *testing.T) {
+	t.Helper()
+	setupTestEnv(t)
+	startupRegistry = scratchRegistry{}
+	t.Cleanup(func() {
+		if err := resetTestKey(); err != nil {
+			t.Errorf("清空测试注册表键失败: %v", err)
+		}
+	})
+}
+
+// rawRunValue 绕过 startupRegistry 直接读取测试键中的值
+func rawRunValue(t *testing.T, name string) (string, bool) {
+	t.Helper()
+	key, err := registry.OpenKey(registry.CURRENT_USER, testRunKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue(name)
+	if err == registry.ErrNotExist {
+		return "", false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value, true
+}
+
+func TestAddToStartup_roundtrip(t *testing.T) {
+	setupIntegrationEnv(t)
+	exe := writeTestFile(t, t.TempDir(), "app.exe")
+	const name = "AutostartIntegrationApp"
+
+	if err := AddToStartup(exe, name); err != nil {
+		t.Fatalf("AddToStartup: %v", err)
+	}
+	if value, ok := rawRunValue(t, name); !ok || value != buildCommand(exe, "") {
+		t.Fatalf("测试键中的值 = %q (存在 %v)，want %q", value, ok, buildCommand(exe, ""))
+	}
+
+	items, err := ListStartupEntries(ScopeCurrentUser)
+	if err != nil {
+		t.Fatalf("ListStartupEntries: %v", err)
+	}
+	found := false
+	for _, item := range items {
+		if item.Name == name {
+			found = true
+			if item.Value != buildCommand(exe, "") {
+				t.Errorf("Value = %q, want %q", item.Value, buildCommand(exe, ""))
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("ListStartupEntries 中没有 %s: %+v", name, items)
+	}
+
+	if err := RemoveFromStartup(name); err != nil {
+		t.Fatalf("RemoveFromStartup: %v", err)
+	}
+	if _, ok := rawRunValue(t, name); ok {
+		t.Error("移除后测试键中仍有该值")
+	}
+	items, err = ListStartupEntries(ScopeCurrentUser)
+	if err != nil {
+		t.Fatalf("ListStartupEntries: %v", err)
+	}
+	for _, item := range items {
+		if item.Name == name {
+			t.Errorf("移除后 ListStartupEntries 中仍有 %s", name)
+		}
+	}
+	if err := RemoveFromStartup(name); err == nil {
+		t.Error("再次移除不存在的启动项应返回错误")
+	}
+}
+
+func TestSyncCacheFromRegistry_consistency(t *testing.T) {
+	setupIntegrationEnv(t)
+
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, testRunKeyPath, registry.SET_VALUE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registryValues := map[string]string{
+		"IntegrationA": `"C:\A\a.exe"`,
+		"IntegrationB": `"C:\B\b.exe" --min`,
+	}
+	for name, value := range registryValues {
+		if err := key.SetStringValue(name, value); err != nil {
+			key.Close()
+			t.Fatal(err)
+		}
+	}
+	key.Close()
+
+	if err := saveCache(&CacheData{Items: []CacheItem{
+		{Name: "IntegrationB", Value: "old", Enabled: false},
+		{Name: "IntegrationC", Value: `"C:\C\c.exe"`, Enabled: true},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syncCacheFromRegistry(); err != nil {
+		t.Fatalf("syncCacheFromRegistry: %v", err)
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.Items) != 3 {
+		t.Fatalf("Items = %+v, want 3 项", cache.Items)
+	}
+	for name, value := range registryValues {
+		_, item := findItemByName(cache, name)
+		if item == nil {
+			t.Errorf("缓存中没有 %s", name)
+			continue
+		}
+		if item.Value != value || !item.Enabled {
+			t.Errorf("%s = {Value: %q, Enabled: %v}, want {Value: %q, Enabled: true}", name, item.Value, item.Enabled, value)
+		}
+	}
+	if _, item := findItemByName(cache, "IntegrationC"); item == nil || item.Enabled {
+		t.Errorf("IntegrationC 应保留在缓存中并标记为禁用: %+v", item)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// --format 中 Go 模板的前缀，如 --format=go-template={{range .}}{{.Name}}{{"\n"}}{{end}}
+const goTemplatePrefix = "go-template="
+
+// writeMachineReadable 每个启动项依次输出 name、value、enabled 三个字段，每个字段（包括最后一个）以 NUL 结尾，
+// 可直接用 xargs -0 -n 3 等工具处理。字段中不转义任何字符；注册表中的字符串值不会包含 NUL，
+// 万一缓存中的值含有 NUL 时将其删除，保证 NUL 只作为字段的结尾
+func writeMachineReadable(w io.Writer, items []CacheItem) error {
+	bw := bufio.NewWriter(w)
+	for _, item := range items {
+		for _, field := range []string{item.Name, item.Value, strconv.FormatBool(item.Enabled)} {
+			bw.WriteString(strings.ReplaceAll(field, "\x00", ""))
+			bw.WriteByte(0)
+		}
+	}
+	return bw.Flush()
+}
+
+// writeNDJSON 每个启动项输出一行紧凑的 JSON 对象，便于脚本逐行处理
+func writeNDJSON(w io.Writer, items []CacheItem) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	for _, item := range items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// parseListTemplate 解析 --format=go-template=<模板> 或 --template-file 指定的模板，两者都为空时返回 nil
+// 模板的数据为按显示顺序排列的 []CacheItem
+func parseListTemplate(format, templateFile string) (*template.Template, error) {
+	var text string
+	switch {
+	case format != "" && templateFile != "":
+		return nil, fmt.Errorf("--format 和 --template-file 不能同时使用")
+	case templateFile != "":
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取模板文件失败: %v", err)
+		}
+		text = string(data)
+	case strings.HasPrefix(format, goTemplatePrefix):
+		text = strings.TrimPrefix(format, goTemplatePrefix)
+	case format != "":
+		return nil, fmt.Errorf("不支持的格式 %s（可选 go-template=<模板>）", format)
+	default:
+		return nil, nil
+	}
+
+	tmpl, err := template.New("list").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败: %v", err)
+	}
+	return tmpl, nil
+}
+
+// runList 按显示顺序输出缓存中的所有启动项
+// tmpl 不为 nil 时用它输出；machineReadable 为 true 时忽略 outputFormat，使用 writeMachineReadable 的格式
+func runList(outputFormat string, machineReadable bool, tmpl *template.Template) error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	items := sortByPriority(cache.Items)
+
+	switch {
+	case tmpl != nil:
+		if items == nil {
+			items = []CacheItem{}
+		}
+		if err := tmpl.Execute(os.Stdout, items); err != nil {
+			return fmt.Errorf("执行模板失败: %v", err)
+		}
+		return nil
+	case machineReadable:
+		return writeMachineReadable(os.Stdout, items)
+	case outputFormat == "ndjson":
+		return writeNDJSON(os.Stdout, items)
+	case outputFormat == "json":
+		if items == nil {
+			items = []CacheItem{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+	}
+
+	for i, item := range items {
+		status := "[启用]"
+		if !item.Enabled {
+			status = "[禁用]"
+		}
+		fmt.Printf("%d. %s %s\n   %s\n", i+1, status, item.Name, item.Value)
+	}
+	return nil
+}
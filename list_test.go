@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteMachineReadable(t *testing.T) {
+	items := []CacheItem{
+		{Name: "App", Value: `"C:\Program Files\App\app.exe" --min`, Enabled: true},
+		{Name: "多行\n名称", Value: "a\x00b\r\nc", Enabled: false},
+		{Name: "Empty", Value: "", Enabled: true},
+	}
+
+	var buf bytes.Buffer
+	if err := writeMachineReadable(&buf, items); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if !strings.HasSuffix(out, "\x00") {
+		t.Fatalf("最后一个字段也应以 NUL 结尾: %q", out)
+	}
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	want := []string{
+		"App", `"C:\Program Files\App\app.exe" --min`, "true",
+		"多行\n名称", "ab\r\nc", "false",
+		"Empty", "", "true",
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("输出 %d 个字段，want %d 个: %q", len(fields), len(want), out)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("第 %d 个字段 = %q, want %q", i+1, fields[i], want[i])
+		}
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	items := []CacheItem{
+		{Name: "App", Value: `"C:\Program Files\App\app.exe" --min`, Enabled: true},
+		{Name: "多行\n名称", Value: "a\r\nb", Enabled: false},
+	}
+
+	var buf bytes.Buffer
+	if err := writeNDJSON(&buf, items); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(items) {
+		t.Fatalf("输出 %d 行，want %d 行: %q", len(lines), len(items), buf.String())
+	}
+	for i, line := range lines {
+		var got CacheItem
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("第 %d 行不是 JSON 对象: %v", i+1, err)
+		}
+		if got.Name != items[i].Name || got.Value != items[i].Value || got.Enabled != items[i].Enabled {
+			t.Errorf("第 %d 行 = %+v, want %+v", i+1, got, items[i])
+		}
+	}
+}
+
+func TestParseListTemplate(t *testing.T) {
+	items := []CacheItem{
+		{Name: "App", Value: `C:\app.exe`, Enabled: true},
+		{Name: "Tool", Value: `C:\tool.exe`, Enabled: false},
+	}
+	const text = `{{range .}}{{.Name}}: {{.Enabled}}{{"\n"}}{{end}}`
+	const want = "App: true\nTool: false\n"
+
+	file := filepath.Join(t.TempDir(), "list.tmpl")
+	if err := os.WriteFile(file, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name, format, file string
+	}{
+		{"--format", goTemplatePrefix + text, ""},
+		{"--template-file", "", file},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseListTemplate(tt.format, tt.file)
+			if err != nil {
+				t.Fatalf("parseListTemplate: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, items); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != want {
+				t.Errorf("输出 = %q, want %q", buf.String(), want)
+			}
+		})
+	}
+
+	if tmpl, err := parseListTemplate("", ""); tmpl != nil || err != nil {
+		t.Errorf("未指定模板时 = %v, %v, want nil, nil", tmpl, err)
+	}
+	for _, tt := range []struct{ format, file string }{
+		{goTemplatePrefix + text, file},
+		{"json", ""},
+		{goTemplatePrefix + "{{range .}}", ""},
+		{"", filepath.Join(t.TempDir(), "missing.tmpl")},
+	} {
+		if _, err := parseListTemplate(tt.format, tt.file); err == nil {
+			t.Errorf("parseListTemplate(%q, %q) 应返回错误", tt.format, tt.file)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// RegistryScope 启动项所在的注册表根键
+type RegistryScope int
+
+const (
+	ScopeCurrentUser RegistryScope = iota // HKEY_CURRENT_USER，仅对当前用户生效
+	ScopeMachineWide                      // HKEY_LOCAL_MACHINE，对所有用户生效
+)
+
+// rootKey 返回范围对应的注册表根键
+func (s RegistryScope) rootKey() registry.Key {
+	if s == ScopeMachineWide {
+		return registry.LOCAL_MACHINE
+	}
+	return registry.CURRENT_USER
+}
+
+// String 返回范围的显示名称
+func (s RegistryScope) String() string {
+	if s == ScopeMachineWide {
+		return "HKEY_LOCAL_MACHINE"
+	}
+	return "HKEY_CURRENT_USER"
+}
+
+// ListStartupEntries 直接读取 scope 下 Run 键中的启动项，不经过缓存
+// 注册表中存在即为启用，Value 为注册表中的原始字符串
+// 当前用户的 Run 键通过 startupRegistry 读取
+func ListStartupEntries(scope RegistryScope) ([]CacheItem, error) {
+	values, err := readRunValues(scope)
+	if err != nil {
+		if err == registry.ErrNotExist {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("打开注册表失败: %v", err)
+	}
+
+	items := make([]CacheItem, 0, len(values))
+	for name, value := range values {
+		items = append(items, CacheItem{Name: name, Value: value, Enabled: true, Scope: scope})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+// readRunValues 读取 scope 下 Run 键中的所有值
+func readRunValues(scope RegistryScope) (map[string]string, error) {
+	if scope == ScopeCurrentUser {
+		return startupRegistry.ReadValues(runKeyPath)
+	}
+
+	key, err := registry.OpenKey(scope.rootKey(), runKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Close()
+	return readKeyValues(key), nil
+}
+
+// ListMachineStartupEntries 读取对所有用户生效的 HKLM Run 键中的启动项
+// 不需要管理员权限；无权读取时只显示警告并返回空列表
+func ListMachineStartupEntries() ([]CacheItem, error) {
+	items, err := ListStartupEntries(ScopeMachineWide)
+	if err != nil {
+		printer.Print(fmt.Sprintf("警告: 无法读取所有用户的启动项: %v\n", err), colorYellow)
+		return []CacheItem{}, nil
+	}
+	return items, nil
+}
+
+// showReadOnlyEntries 在状态列表下方单独显示本工具不能修改的启动项
+func showReadOnlyEntries(title string, items []CacheItem, namePattern string) {
+	var shown []CacheItem
+	indices, _ := SearchByRegex(&CacheData{Items: items}, namePattern, "")
+	for _, i := range indices {
+		shown = append(shown, items[i])
+	}
+	if len(shown) == 0 {
+		return
+	}
+
+	fmt.Println("\n" + strings.Repeat("-", 60))
+	fmt.Printf("%s：\n", title)
+	fmt.Println(strings.Repeat("-", 60))
+	for i, item := range shown {
+		fmt.Printf("%d. %s\n   %s\n", i+1, item.Name, item.Value)
+	}
+}
+
+// ErrNotFound 注册表和缓存中都没有指定名称的启动项
+var ErrNotFound = errors.New("启动项不存在")
+
+// GetStartupEntry 查找单个启动项
+// 以注册表中的实时值为准，并合并缓存中的标签、备注、文件哈希等信息；
+// 不在注册表中但缓存中记录为禁用的项以 Enabled=false 返回
+func GetStartupEntry(name string) (*CacheItem, error) {
+	value, err := startupRegistry.GetValue(runKeyPath, name)
+	inRegistry := err == nil
+	if err != nil && err != registry.ErrNotExist {
+		return nil, fmt.Errorf("读取注册表失败: %v", err)
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		return nil, fmt.Errorf("加载缓存失败: %v", err)
+	}
+	_, cached := findItemByName(cache, name)
+
+	switch {
+	case inRegistry && cached != nil:
+		// 延迟启动和设置了工作目录的项在注册表中是启动脚本，缓存中保存的是实际命令
+		if !hasStartWrapper(*cached) || value != startWrapperCommand(*cached) {
+			cached.Value = value
+			cached.DelaySeconds = 0
+			cached.WorkingDir = ""
+		}
+		cached.Enabled = true
+		return cached, nil
+	case inRegistry:
+		return &CacheItem{Name: name, Value: value, Enabled: true}, nil
+	case cached != nil && !cached.Enabled:
+		return cached, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+// handleShowLiveRegistry 直接显示注册表中的启动项，并标出与缓存不同步的项
+func handleShowLiveRegistry() {
+	scope := configScope()
+	items, err := ListStartupEntries(scope)
+	if err != nil {
+		printError("%v", err)
+		return
+	}
+	cache, err := loadCache()
+	if err != nil {
+		printError("加载缓存失败: %v", err)
+		return
+	}
+
+	fmt.Printf("\n=== 注册表实时状态（%s） ===\n", scope)
+	if len(items) == 0 {
+		fmt.Println("注册表中没有启动项。")
+	}
+
+	// 缓存只记录当前用户的启动项，其他范围只列出不比较
+	if scope != ScopeCurrentUser {
+		for i, item := range items {
+			fmt.Printf("%d. %s\n   %s\n", i+1, item.Name, item.Value)
+		}
+		return
+	}
+
+	inRegistry := make(map[string]bool, len(items))
+	newCount := 0
+	for i, item := range items {
+		inRegistry[item.Name] = true
+		fmt.Printf("%d. %s", i+1, item.Name)
+		if idx, _ := findItemByName(cache, item.Name); idx < 0 {
+			printer.Print(" [新]", colorYellow)
+			newCount++
+		}
+		fmt.Printf("\n   %s\n", item.Value)
+	}
+
+	// 缓存中为启用但注册表中已不存在的项
+	var stale []CacheItem
+	for _, item := range cache.Items {
+		if item.Enabled && !inRegistry[item.Name] && !item.RunOnce {
+			stale = append(stale, item)
+		}
+	}
+	if len(stale) > 0 {
+		fmt.Println("\n缓存中记录为启用、但注册表中已不存在的项：")
+		for _, item := range stale {
+			fmt.Printf("- %s", item.Name)
+			printer.Print(" [过期]", colorYellow)
+			fmt.Printf("\n   %s\n", item.Value)
+		}
+	}
+
+	if newCount == 0 && len(stale) == 0 {
+		fmt.Println("\n缓存与注册表同步。")
+	} else {
+		fmt.Printf("\n%d 项不在缓存中，%d 项已不在注册表中，可在“显示差异（缓存与注册表）”中同步。\n", newCount, len(stale))
+	}
+}
@@ -2,13 +2,20 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/sys/windows/registry"
 )
@@ -16,68 +23,112 @@ import (
 // 缓存文件路径
 var cacheFilePath string
 
+// 查看自启动状态时是否显示版本和发布者（--verbose）
+var verbose bool
+
+// 查看自启动状态时默认的名称筛选正则表达式（--filter）
+var statusFilter string
+
+// 查看自启动状态时是否总是先从注册表同步（--force-sync）
+var forceSync bool
+
+// 查看自启动状态时是否同时显示 Winlogon 钩子等其他启动位置（--all-sources）
+var allSources bool
+
+// 列表每页显示的项数（--page-size）
+const defaultPageSize = 10
+
+var pageSize = defaultPageSize
+
 // 缓存数据结构
+// JSON 字段顺序与结构体字段声明顺序一致，新增字段请追加在末尾，
+// 避免已有的缓存文件在下次保存时字段顺序变化；TestCacheJSONStability 检查保存的内容读回后不变
 type CacheItem struct {
-	Name    string `json:"name"`
-	Value   string `json:"value"`
-	Enabled bool   `json:"enabled"`
+	Name      string    `json:"name" toml:"name"`
+	Value     string    `json:"value" toml:"value"`
+	Enabled   bool      `json:"enabled" toml:"enabled"`
+	Tags      []string  `json:"tags,omitempty" toml:"tags,omitempty"`
+	Notes     string    `json:"notes,omitempty" toml:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at" toml:"created_at"`
+	Arguments string    `json:"arguments,omitempty" toml:"arguments,omitempty"`
+	Type      string    `json:"type,omitempty" toml:"type,omitempty"`
+	UpdatedAt time.Time `json:"updated_at" toml:"updated_at"`
+	LastValue string    `json:"last_value,omitempty" toml:"last_value,omitempty"`
+	Reason    string    `json:"reason,omitempty" toml:"reason,omitempty"`
+
+	LastDisabledReason string `json:"last_disabled_reason,omitempty" toml:"last_disabled_reason,omitempty"`
+	FileHash           string `json:"file_hash,omitempty" toml:"file_hash,omitempty"`
+	FileVersion        string `json:"file_version,omitempty" toml:"file_version,omitempty"`
+	WindowState        string `json:"window_state,omitempty" toml:"window_state,omitempty"`
+	DelaySeconds       int    `json:"delay_seconds,omitempty" toml:"delay_seconds,omitzero"`
+	WorkingDir         string `json:"working_dir,omitempty" toml:"working_dir,omitempty"` // 启动时切换到的工作目录，为空时不切换
+
+	Scope      RegistryScope `json:"scope,omitempty" toml:"scope,omitzero"` // 启动项所在的注册表根键
+	ArchivedAt time.Time     `json:"archived_at" toml:"archived_at"`        // 归档的时间，只用于已归档的启动项
+
+	History   []HistoryRecord `json:"history,omitempty" toml:"history,omitempty"`       // 修改历史，最多保留 maxHistoryRecords 条
+	ExpiresAt *time.Time      `json:"expires_at,omitempty" toml:"expires_at,omitempty"` // 到期时间，过期后同步时自动禁用
+	RunCount  int             `json:"run_count,omitempty" toml:"run_count,omitzero"`    // 启用期间本工具启动的次数，只增不减
+
+	FileModTime time.Time `json:"file_mod_time,omitempty" toml:"file_mod_time,omitempty"` // 添加时程序文件的修改时间，用于发现被替换的程序
+	Priority    int       `json:"priority,omitempty" toml:"priority,omitzero"`            // 显示和导出的顺序，越小越靠前，0 表示未调整过
+	RunOnce     bool      `json:"run_once,omitempty" toml:"run_once,omitempty"`           // 位于 Scope 下的 RunOnce 键而不是 Run 键
 }
 
 type CacheData struct {
-	Items []CacheItem `json:"items"`
+	Version       int         `json:"version" toml:"version"` // 缓存文件的结构版本，见 cacheVersion
+	Items         []CacheItem `json:"items" toml:"items"`
+	ArchivedItems []CacheItem `json:"archived_items,omitempty" toml:"archived_items,omitempty"` // 已归档的启动项，可恢复
+	SyncedAt      time.Time   `json:"synced_at" toml:"synced_at"`                               // 最近一次从注册表同步的时间
+	Hostname      string      `json:"hostname" toml:"hostname"`                                 // 最近一次写入缓存的电脑
+	Username      string      `json:"username" toml:"username"`                                 // 最近一次写入缓存的用户
 }
 
 const (
 	// 注册表路径：当前用户的启动项
 	runKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+	// 注册表路径：其他启动项管理工具（如 CCleaner、Autoruns）约定俗成的
+	// 禁用项存放位置，被禁用的启动项会从 Run 移到这里
+	runDisabledKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run-Disabled`
 )
 
 func init() {
-	// 初始化缓存文件路径
-	exePath, _ := os.Executable()
-	cacheFilePath = filepath.Join(filepath.Dir(exePath), "autostart.json")
+	// 加载默认位置的配置文件，--config 指定其他文件时在 main 中重新加载
+	configPath = defaultConfigPath()
+	config, _ = LoadConfig(configPath)
 
-	// 启动时同步缓存
-	syncCacheFromRegistry()
+	// 初始化缓存文件路径，并使用上次切换到的配置方案
+	useCacheDir(resolveCacheDir(config.PortableMode, config.CacheDir))
 }
 
+// 本次运行是否已累计过 RunCount，每次运行只累计一次
+var runCounted bool
+
 // syncCacheFromRegistry 从注册表同步缓存
-func syncCacheFromRegistry() {
+// 启动时调用的地方忽略错误，缓存保持原样
+func syncCacheFromRegistry() error {
 	cache, err := loadCache()
 	if err != nil {
-		return
-	}
-
-	// 打开注册表
-	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
-	if err != nil {
-		return
+		return fmt.Errorf("加载缓存失败: %v", err)
 	}
-	defer key.Close()
 
-	// 获取所有注册表项名称
-	names, err := key.ReadValueNames(0)
+	// 读取注册表中的所有启动项
+	registryItems, err := startupRegistry.ReadValues(runKeyPath)
 	if err != nil {
-		return
-	}
-
-	// 构建注册表项map，用于快速查找
-	registryItems := make(map[string]string)
-	for _, name := range names {
-		value, _, err := key.GetStringValue(name)
-		if err == nil {
-			registryItems[name] = value
-		}
+		return fmt.Errorf("读取注册表失败: %v", err)
 	}
 
 	// 步骤1：遍历缓存，设置 disable
-	// 缓存中存在但注册表中不存在 → 标记为禁用
+	// 缓存中存在但注册表中不存在 → 标记为禁用；RunOnce 项和移到所有用户的项单独检查，见 syncRunOnceItems 和 syncMachineItems
 	for i := range cache.Items {
 		item := &cache.Items[i]
-		if _, exists := registryItems[item.Name]; !exists {
+		if _, exists := registryItems[item.Name]; !exists && !item.RunOnce && item.Scope != ScopeMachineWide {
 			item.Enabled = false
 		}
 	}
+	syncRunOnceItems(cache)
+	syncMachineItems(cache)
 
 	// 步骤2：遍历注册表，设置 enable
 	// 注册表中存在 → 添加到缓存或更新，并标记为启用
@@ -85,905 +136,4164 @@ func syncCacheFromRegistry() {
 		idx, _ := findItemByName(cache, name)
 		if idx >= 0 {
 			// 缓存中存在，更新值并标记为启用
-			cache.Items[idx].Value = value
-			cache.Items[idx].Enabled = true
+			// 延迟启动和设置了工作目录的项在注册表中是启动脚本，缓存中保留原来的命令
+			item := &cache.Items[idx]
+			if !hasStartWrapper(*item) || value != startWrapperCommand(*item) {
+				item.Value = value
+				item.DelaySeconds = 0
+				item.WorkingDir = ""
+			}
+			item.Enabled = true
 		} else {
 			// 缓存中不存在，添加到缓存并标记为启用
 			cache.Items = append(cache.Items, CacheItem{
-				Name:    name,
-				Value:   value,
-				Enabled: true,
+				Name:      name,
+				Value:     value,
+				Enabled:   true,
+				CreatedAt: time.Now(),
 			})
 		}
 	}
 
-	// 保存缓存
-	saveCache(cache)
-}
-
-func main() {
-	// 显示主菜单
-	showMainMenu()
-}
-
-// loadCache 加载缓存文件
-func loadCache() (*CacheData, error) {
-	data := &CacheData{}
+	// 步骤3：读取其他工具的禁用项
+	// 在 Run-Disabled 中存在、但注册表和缓存中都不存在 → 添加到缓存并标记为禁用
+	for name, value := range readRunDisabledItems() {
+		if _, exists := registryItems[name]; exists {
+			continue
+		}
+		if idx, _ := findItemByName(cache, name); idx < 0 {
+			cache.Items = append(cache.Items, CacheItem{
+				Name:      name,
+				Value:     value,
+				Enabled:   false,
+				CreatedAt: time.Now(),
+			})
+		}
+	}
 
-	file, err := os.Open(cacheFilePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return data, nil
+	// 步骤4：检查启用的项的程序文件是否在添加后被修改
+	for i := range cache.Items {
+		item := &cache.Items[i]
+		if !item.Enabled {
+			continue
+		}
+		current, changed := modTimeChanged(*item)
+		if changed {
+			printer.Print(fmt.Sprintf("警告: 启动项 %s 的程序文件在添加后被修改过\n", item.Name), colorYellow)
+		}
+		if !current.IsZero() {
+			item.FileModTime = current
 		}
-		return nil, err
 	}
-	defer file.Close()
 
-	decoder := json.NewDecoder(file)
-	err = decoder.Decode(data)
-	if err != nil {
-		return nil, err
+	// 每次运行第一次同步时，为所有启用的项累计运行次数
+	if !runCounted {
+		for i := range cache.Items {
+			if cache.Items[i].Enabled {
+				cache.Items[i].RunCount++
+			}
+		}
+		runCounted = true
 	}
 
-	return data, nil
-}
+	// 保存缓存
+	cache.SyncedAt = time.Now()
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
 
-// saveCache 保存缓存文件
-func saveCache(data *CacheData) error {
-	file, err := os.Create(cacheFilePath)
-	if err != nil {
-		return err
+	// 步骤5：禁用已过期的启动项
+	for _, item := range cache.Items {
+		if !item.Enabled || !isExpired(item) {
+			continue
+		}
+		if err := DisableEntry(item.Name, "已过期"); err != nil {
+			printError("禁用过期的启动项 %s 失败 - %v", item.Name, err)
+			continue
+		}
+		printer.Print(fmt.Sprintf("已自动禁用过期的启动项: %s\n", item.Name), colorYellow)
 	}
-	defer file.Close()
+	return nil
+}
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(data)
+// isExpired 检查启动项是否已过了到期时间
+func isExpired(item CacheItem) bool {
+	return item.ExpiresAt != nil && time.Now().After(*item.ExpiresAt)
 }
 
-// findItemByName 根据名称查找缓存项
-func findItemByName(data *CacheData, name string) (int, *CacheItem) {
-	for i, item := range data.Items {
-		if item.Name == name {
-			return i, &item
+// readExpiryDate 询问启动项的到期日期，返回该日期当天结束的时间，留空时返回 nil
+func readExpiryDate() *time.Time {
+	for {
+		input := readInput("设置到期日期？(YYYY-MM-DD，留空表示永久): ")
+		if input == "" {
+			return nil
+		}
+		date, err := time.ParseInLocation("2006-01-02", input, time.Local)
+		if err != nil {
+			fmt.Println("无效的日期，请按 YYYY-MM-DD 格式输入。")
+			continue
+		}
+		expiresAt := date.AddDate(0, 0, 1).Add(-time.Second)
+		if time.Now().After(expiresAt) {
+			fmt.Println("到期日期不能早于今天。")
+			continue
 		}
+		return &expiresAt
 	}
-	return -1, nil
 }
 
-// addOrUpdateItem 添加或更新缓存项
-func addOrUpdateItem(data *CacheData, name, value string, enabled bool) {
-	idx, _ := findItemByName(data, name)
-	if idx >= 0 {
-		data.Items[idx].Value = value
-		data.Items[idx].Enabled = enabled
-	} else {
-		data.Items = append(data.Items, CacheItem{
-			Name:    name,
-			Value:   value,
-			Enabled: enabled,
-		})
+// cacheMaxAge 查看状态时缓存的有效期，超过后先从注册表同步
+const cacheMaxAge = 30 * time.Second
+
+// syncIfStale 缓存超过有效期（或指定了 --force-sync）时从注册表同步
+func syncIfStale() {
+	if !forceSync {
+		if cache, err := loadCache(); err == nil && time.Since(cache.SyncedAt) < cacheMaxAge {
+			return
+		}
 	}
+	syncCacheFromRegistry()
 }
 
-// removeItem 从缓存中删除项
-func removeItem(data *CacheData, name string) {
-	idx, _ := findItemByName(data, name)
-	if idx >= 0 {
-		data.Items = append(data.Items[:idx], data.Items[idx+1:]...)
+// RebuildCache 删除现有缓存文件，完全根据注册表重新生成缓存
+// 标签、备注、延迟、文件哈希等无法从注册表推断的信息会丢失
+func RebuildCache() error {
+	if !dryRunf("删除缓存文件 %s", cacheFilePath) {
+		if err := os.Remove(cacheFilePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除缓存文件失败: %v", err)
+		}
 	}
+	markOrphansStale()
+	return syncCacheFromRegistry()
 }
 
-// showMainMenu 显示主菜单
-func showMainMenu() {
-	for {
-		fmt.Println("\n" + strings.Repeat("=", 60))
-		fmt.Println("        Windows 自启动设置工具")
-		fmt.Println(strings.Repeat("=", 60))
-		fmt.Println("1. 添加程序到自启动")
-		fmt.Println("2. 移除程序的自启动")
-		fmt.Println("3. 查看当前自启动状态")
-		fmt.Println("4. 添加命令到自启动")
-		fmt.Println("5. 启用")
-		fmt.Println("6. 禁用")
-		fmt.Println("7. 退出")
-		fmt.Println(strings.Repeat("=", 60))
-		fmt.Print("请选择操作 (1-7): ")
-
-		reader := bufio.NewReader(os.Stdin)
-		choice, _ := reader.ReadString('\n')
-		choice = strings.TrimSpace(choice)
+// handleRebuildCache 确认后从注册表重建缓存
+func handleRebuildCache() {
+	fmt.Printf("\n确定要从注册表重建缓存吗？\n")
+	fmt.Printf("缓存文件: %s\n", cacheFilePath)
+	fmt.Println("已禁用的启动项，以及标签、备注、延迟启动、文件哈希等信息将会丢失。")
+	if !confirmYes("确认重建？(y/n): ") {
+		return
+	}
 
-		switch choice {
-		case "1":
-			handleAddToStartup()
-		case "2":
-			handleRemoveFromStartup()
-		case "3":
-			showStartupStatus()
-		case "4":
-			handleAddCommand()
-		case "5":
-			handleEnable()
-		case "6":
-			handleDisable()
-		case "7":
-			fmt.Println("再见！")
-			return
-		default:
-			fmt.Println("无效的选择，请重新输入。")
-		}
+	if err := RebuildCache(); err != nil {
+		printError("重建缓存失败 - %v", err)
+		return
 	}
+	fmt.Println("已成功从注册表重建缓存！")
 }
 
-// handleAddToStartup 处理添加自启动
-func handleAddToStartup() {
-	exePath := selectExeFile()
-	if exePath == "" {
-		return // 用户取消了选择
+// readRunDisabledItems 读取 Run-Disabled 键中的禁用项
+// 该键不存在时返回空 map
+func readRunDisabledItems() map[string]string {
+	values, err := startupRegistry.ReadValues(runDisabledKeyPath)
+	if err != nil {
+		return make(map[string]string)
 	}
+	return values
+}
 
-	// 获取程序名称作为注册表项名称
-	appName := getAppName(exePath)
+func main() {
+	importPath := flag.String("import", "", "从 CSV 文件导入启动项后退出")
+	importAutoruns := flag.String("import-autoruns", "", "从 Autoruns 导出的 CSV 文件导入启动项后退出")
+	force := flag.Bool("force", false, "导入、清理和添加时跳过确认，添加时覆盖同名的启动项；交互模式下忽略已在运行的其他实例")
+	exportPS1 := flag.String("export-ps1", "", "将启动项导出为 PowerShell 脚本后退出")
+	exportHTML := flag.String("export-html", "", "将启动项导出为 HTML 报告后退出")
+	generateReport := flag.String("generate-report", "", "生成包含摘要、签名状态饼图、校验问题和最近修改的 HTML 健康报告后退出（不支持 PDF）")
+	exportAutoruns := flag.String("export-autoruns", "", "将启动项导出为 Autoruns 格式的 CSV 后退出")
+	exportBatch := flag.String("export-batch", "", "将启动项导出为可在其他电脑上重建启动项的批处理文件后退出")
+	exportCleanupBatch := flag.String("export-cleanup-batch", "", "将启动项导出为删除这些启动项的批处理文件后退出")
+	watch := flag.Bool("watch", false, "监听注册表启动项变化并输出，按 Ctrl-C 退出")
+	flag.BoolVar(&verbose, "verbose", false, "查看自启动状态时显示程序的版本和发布者")
+	profile := flag.String("profile", "", "使用指定的配置方案（缓存文件 autostart_<name>.json）")
+	rebuild := flag.Bool("rebuild-cache", false, "确认后删除缓存文件并根据注册表重建")
+	flag.StringVar(&statusFilter, "filter", "", "查看自启动状态时只显示名称匹配该正则表达式的项")
+	flag.IntVar(&pageSize, "page-size", defaultPageSize, "列表每页显示的项数")
+	flag.BoolVar(&allSources, "all-sources", false, "查看自启动状态时同时显示 Winlogon 钩子等其他启动位置")
+	flag.BoolVar(&showRunningOnly, "show-running", false, "查看自启动状态时只显示程序正在运行的项")
+	flag.BoolVar(&forceSync, "force-sync", false, "查看自启动状态时总是先从注册表同步，不使用 30 秒内的缓存")
+	noColor := flag.Bool("no-color", false, "不使用彩色输出")
+	flag.BoolVar(&dryRun, "dry-run", false, "只显示将要执行的修改，不写入注册表、缓存和其他文件")
+	serve := flag.String("serve", "", "在指定地址（如 :8080）上提供 HTTP 管理接口")
+	apiToken := flag.String("api-token", "", "HTTP 接口中修改操作所需的 Bearer 令牌")
+	tlsCert := flag.String("tls-cert", "", "HTTP 接口使用的 TLS 证书文件")
+	tlsKey := flag.String("tls-key", "", "HTTP 接口使用的 TLS 私钥文件")
+	portable := flag.Bool("portable", false, "便携模式：缓存文件保存在程序所在目录")
+	cacheDir := flag.String("cache-dir", "", "缓存文件所在的目录，优先于便携模式和配置文件")
+	configFile := flag.String("config", "", `使用指定的配置文件（默认 %APPDATA%\AutostartManager\config.json）`)
+	pipeName := flag.String("pipe", "", `在命名管道 \\.\pipe\<name> 上接受 JSON-RPC 请求`)
+	flag.BoolVar(&noEventLog, "no-eventlog", false, "不将启动项的修改记录到 Windows 事件日志")
+	format := flag.String("cache-format", "", "缓存文件格式：json 或 toml（默认沿用已有缓存文件的格式）")
+	checkOrphans := flag.Bool("check-orphans", false, "检查指向不存在文件的启动项并输出摘要后退出")
+	cleanOrphans := flag.Bool("clean-orphans", false, "移除所有指向不存在文件的启动项后退出（逐项确认，--force 跳过确认）")
+	compare := flag.String("compare", "", "比较当前缓存与指定的缓存文件（如其他电脑导出的）中的启动项后退出")
+	output := flag.String("output", "text", "--list、--status、--check-orphans、--clean-orphans 和 --compare 的输出格式：text、json 或 ndjson（每行一个 JSON 对象）")
+	list := flag.Bool("list", false, "按显示顺序列出缓存中的所有启动项后退出")
+	statusName := flag.String("status", "", "显示指定启动项的详细状态（注册表、文件、签名、哈希、进程等）后退出，--output json 输出 JSON")
+	var machineReadable bool
+	flag.BoolVar(&machineReadable, "machine-readable", false, "--list 时每项输出以 NUL 结尾的 name、value、enabled 字段，便于 xargs -0 -n 3 处理")
+	flag.BoolVar(&machineReadable, "m", false, "--machine-readable 的简写")
+	listFormat := flag.String("format", "", "--list 时用 Go 模板自定义输出，如 --format='go-template={{range .}}{{.Name}}: {{.Enabled}}{{\"\\n\"}}{{end}}'")
+	templateFile := flag.String("template-file", "", "--list 时从文件读取 Go 模板，用于较长的模板")
+	flag.BoolVar(&ignoreHostCheck, "ignore-host-check", false, "不检查缓存文件是否来自其他电脑或其他用户")
+	convertCache := flag.String("convert-cache", "", "将缓存文件转换为指定格式（json 或 toml）后退出")
+	migrateFrom := flag.String("migrate-from", "", "将旧版本的缓存文件迁移到当前版本，写入 --migrate-to 指定的文件后退出")
+	migrateTo := flag.String("migrate-to", "", "--migrate-from 迁移后写入的文件（扩展名决定格式）")
+	fromProcess := flag.String("from-process", "", "将指定 PID 的正在运行的程序添加到自启动后退出")
+	fromShortcut := flag.String("from-shortcut", "", "将 .lnk 快捷方式指向的程序添加到自启动后退出（--force 跳过确认）")
+	fromService := flag.String("from-service", "", "将指定 Windows 服务的程序添加到 Run 键后退出（--force 跳过确认）")
+	runName := flag.String("run", "", "立即运行指定的启动项，显示程序的输出并等待其退出")
+	detach := flag.Bool("detach", false, "--run 时在后台启动程序，不等待其退出")
+	asSystem := flag.Bool("as-system", false, "--run 时通过临时计划任务以 SYSTEM 身份运行（需要管理员权限）")
+	killName := flag.String("kill", "", "结束指定启动项正在运行的进程后退出（--force 跳过确认）；没有找到进程时退出代码为 1")
+	killTimeout := flag.Duration("timeout", defaultKillTimeout, "--kill 时等待程序正常退出的时间，超时后强制结束（0 表示直接强制结束）")
+	addBatch := flag.Bool("add-batch", false, "从标准输入读取每行 名称<Tab>命令 的启动项，逐个添加后输出摘要并退出")
+	removeBatch := flag.Bool("remove-batch", false, "从标准输入读取每行一个的启动项名称，逐个移除后输出摘要并退出（可与 --dry-run 一起预览）")
+	snapshotName := flag.String("snapshot", "", "将当前的启动项保存为指定名称的快照后退出（--force 覆盖同名快照）")
+	revertSource := flag.String("revert", "", "将启动项恢复到 snapshot:<名称> 或 backup:<.reg、.csv 或缓存备份文件> 后退出，恢复前自动为当前状态创建快照（--force 跳过确认）")
+	addURL := flag.String("add-url", "", "下载程序并添加到自启动后退出，非本机地址需要用 --sha256 校验文件")
+	downloadSHA256 := flag.String("sha256", "", "--add-url 下载的文件应有的 SHA-256")
+	downloadDir := flag.String("download-dir", "", `--add-url 的下载目录（默认 %LOCALAPPDATA%\AutostartManager\downloads）`)
+	entryName := flag.String("name", "", "--from-process 等选项添加时使用的启动项名称，默认使用程序文件名")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	// interactive 子命令后面也可以跟选项，如 autostart interactive --verbose
+	startMenu := false
+	if flag.Arg(0) == "interactive" {
+		startMenu = true
+		flag.CommandLine.Parse(flag.Args()[1:])
+	}
 
-	// 获取绝对路径并构建注册表值
-	absPath, _ := filepath.Abs(exePath)
-	regValue := fmt.Sprintf(`"%s"`, absPath)
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
 
-	// 检查是否已经在注册表中
-	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
-	if err == nil {
-		_, _, err = key.GetStringValue(appName)
-		key.Close()
+	if *configFile != "" {
+		cfg, err := LoadConfig(*configFile)
+		if err != nil {
+			printError("%v", err)
+			os.Exit(1)
+		}
+		configPath, config = *configFile, cfg
+	}
+	if *configFile != "" || *portable || *cacheDir != "" {
+		dir := config.CacheDir
+		if *cacheDir != "" {
+			dir = *cacheDir
+		}
+		useCacheDir(resolveCacheDir(config.PortableMode || *portable, dir))
+	}
+	if *format != "" {
+		if !isValidCacheFormat(*format) {
+			printError("不支持的缓存格式 %s（可选 json、toml）", *format)
+			os.Exit(2)
+		}
+		cacheFormat = *format
+		cacheFilePath = profileCachePath(currentProfile)
 	}
 
-	exists := err == nil
+	// 命令行中没有指定的选项使用配置文件中的设置
+	if !setFlags["page-size"] && config.PageSize > 0 {
+		pageSize = config.PageSize
+	}
+	if !setFlags["no-color"] {
+		*noColor = !config.ColorEnabled
+	}
 
-	if exists {
-		fmt.Printf("\n程序 %s 已经在自启动列表中。\n", appName)
-		fmt.Print("是否要重新设置？(y/n): ")
-		reader := bufio.NewReader(os.Stdin)
-		confirm, _ := reader.ReadString('\n')
-		confirm = strings.TrimSpace(strings.ToLower(confirm))
-		if confirm != "y" && confirm != "yes" {
-			return
-		}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
 	}
+	printer = newColorPrinter(*noColor)
 
-	// 确认添加
-	fmt.Printf("\n确定要将以下程序添加到自启动吗？\n")
-	fmt.Printf("程序路径: %s\n", exePath)
-	fmt.Printf("程序名称: %s\n", appName)
-	fmt.Print("确认添加？(y/n): ")
-	reader := bufio.NewReader(os.Stdin)
-	confirm, _ := reader.ReadString('\n')
-	confirm = strings.TrimSpace(strings.ToLower(confirm))
-	if confirm == "y" || confirm == "yes" {
-		// 添加到注册表
-		err := AddToStartup(exePath, appName)
-		if err != nil {
-			fmt.Printf("添加失败: %v\n", err)
-			fmt.Printf("\n错误: 添加失败 - %v\n", err)
-		} else {
-			// 更新缓存
-			cache, _ := loadCache()
-			addOrUpdateItem(cache, appName, regValue, true)
-			saveCache(cache)
+	if flag.NArg() > 0 {
+		printError("未知的命令 %s\n", flag.Arg(0))
+		printUsage()
+		os.Exit(2)
+	}
 
-			fmt.Printf("已成功将 %s 添加到自启动！\n", appName)
+	if *profile != "" {
+		if err := validateProfileName(*profile); err != nil {
+			printError("%v", err)
+			os.Exit(1)
 		}
+		currentProfile = *profile
+		cacheFilePath = profileCachePath(*profile)
 	}
-}
 
-// handleRemoveFromStartup 处理移除自启动
-func handleRemoveFromStartup() {
-	// 获取所有自启动项
-	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
-	if err != nil {
-		fmt.Printf("无法读取注册表: %v\n", err)
+	// 命令行模式下直接同步缓存；交互模式下先显示缓存与注册表的差异，由用户决定是否同步
+	interactive := !*watch && *exportPS1 == "" && *exportHTML == "" && *generateReport == "" && *exportAutoruns == "" && *exportBatch == "" && *exportCleanupBatch == "" && *importPath == "" && *importAutoruns == "" && *serve == "" && *pipeName == "" && !*rebuild && *convertCache == "" && *migrateFrom == "" && !*checkOrphans && !*cleanOrphans && *compare == "" && !*list && *statusName == "" && *fromProcess == "" && *fromShortcut == "" && *fromService == "" && *runName == "" && *killName == "" && !*addBatch && !*removeBatch && *snapshotName == "" && *revertSource == "" && *addURL == ""
+
+	// 没有命令也没有选项时只显示用法，避免在脚本中误入交互菜单
+	if interactive && !startMenu {
+		printUsage()
 		return
 	}
-	defer key.Close()
 
-	// 获取所有值名称
-	names, err := key.ReadValueNames(0)
-	if err != nil {
-		fmt.Printf("读取注册表值失败: %v\n", err)
-		return
+	if interactive {
+		// 同时运行两个交互式实例会互相覆盖注册表和缓存的修改；命令行模式不加锁
+		release, err := acquireInstanceLock()
+		switch {
+		case err == nil:
+			defer release()
+		case errors.Is(err, ErrAnotherInstance) && *force:
+			printer.Print(fmt.Sprintf("警告: %v，已按 --force 继续\n", err), colorYellow)
+		default:
+			printError("%v。使用 --force 可以忽略", err)
+			os.Exit(1)
+		}
+
+		reviewStartupDiff()
+	} else {
+		syncCacheFromRegistry()
 	}
 
-	if len(names) == 0 {
-		fmt.Println("当前没有设置任何自启动程序。")
+	if *rebuild {
+		handleRebuildCache()
 		return
 	}
 
-	// 显示自启动项列表
-	fmt.Println("\n" + strings.Repeat("=", 60))
-	fmt.Println("当前自启动程序列表：")
-	fmt.Println(strings.Repeat("=", 60))
-
-	type startupItem struct {
-		name  string
-		value string
-		index int
+	if *output != "text" && *output != "json" && *output != "ndjson" {
+		printError("不支持的输出格式 %s（可选 text、json、ndjson）", *output)
+		os.Exit(2)
 	}
 
-	items := make([]startupItem, 0, len(names))
-	for i, name := range names {
-		value, _, err := key.GetStringValue(name)
-		if err == nil {
-			items = append(items, startupItem{
-				name:  name,
-				value: value,
-				index: i + 1,
-			})
-			fmt.Printf("%d. %s\n   %s\n\n", i+1, name, value)
-		} else {
-			items = append(items, startupItem{
-				name:  name,
-				value: "(无法读取路径)",
-				index: i + 1,
-			})
-			fmt.Printf("%d. %s\n   (无法读取路径)\n\n", i+1, name)
+	if *compare != "" {
+		if err := runCompare(*compare, *output); err != nil {
+			printError("比较失败 - %v", err)
+			os.Exit(1)
 		}
+		return
 	}
 
-	// 让用户选择要移除的项
-	fmt.Println(strings.Repeat("=", 60))
-	fmt.Print("请输入要移除的项编号（或输入 'b' 返回）: ")
-
-	reader := bufio.NewReader(os.Stdin)
-	choice, _ := reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
+	if *list {
+		tmpl, err := parseListTemplate(*listFormat, *templateFile)
+		if err != nil {
+			printError("%v", err)
+			os.Exit(2)
+		}
+		if tmpl != nil && (machineReadable || *output != "text") {
+			printError("--format 和 --template-file 不能与 --output、--machine-readable 同时使用")
+			os.Exit(2)
+		}
+		if err := runList(*output, machineReadable, tmpl); err != nil {
+			printError("%v", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	if choice == "b" || choice == "B" {
+	if *addBatch {
+		if err := runAddBatch(); err != nil {
+			printError("%v", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	// 解析序号
-	num, err := strconv.Atoi(choice)
-	if err != nil {
-		fmt.Println("无效的编号。")
+	if *removeBatch {
+		if err := runRemoveBatch(); err != nil {
+			printError("%v", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	if num < 1 || num > len(items) {
-		fmt.Println("无效的编号。")
+	if *addURL != "" {
+		if err := runAddFromURL(*addURL, *downloadSHA256, *downloadDir, *entryName, *force); err != nil {
+			printError("添加失败 - %v", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	selectedItem := items[num-1]
+	if *snapshotName != "" {
+		if err := runSnapshot(*snapshotName, *force); err != nil {
+			printError("%v", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	// 确认移除
-	fmt.Printf("\n确定要从自启动中移除以下程序吗？\n")
-	fmt.Printf("程序名称: %s\n", selectedItem.name)
-	fmt.Printf("程序路径: %s\n", selectedItem.value)
-	fmt.Print("确认移除？(y/n): ")
-	confirm, _ := reader.ReadString('\n')
-	confirm = strings.TrimSpace(strings.ToLower(confirm))
-	if confirm == "y" || confirm == "yes" {
-		err := RemoveFromStartup(selectedItem.name)
-		if err != nil {
-			fmt.Printf("\n错误: 移除失败 - %v\n", err)
-		} else {
-			// 从缓存中删除
-			cache, _ := loadCache()
-			removeItem(cache, selectedItem.name)
-			saveCache(cache)
+	if *revertSource != "" {
+		if err := Revert(*revertSource, *force); err != nil {
+			printError("恢复失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-			fmt.Printf("已成功从自启动中移除 %s！\n", selectedItem.name)
+	if *statusName != "" {
+		if err := runStatus(*statusName, *output); err != nil {
+			printError("%v", err)
+			os.Exit(1)
 		}
+		return
 	}
-}
 
-// showStartupStatus 显示当前自启动状态
-func showStartupStatus() {
-	cache, err := loadCache()
-	if err != nil {
-		fmt.Printf("加载缓存失败: %v\n", err)
+	if *checkOrphans || *cleanOrphans {
+		if err := runCheckOrphans(*cleanOrphans, *force, *output); err != nil {
+			printError("%v", err)
+			os.Exit(1)
+		}
 		return
 	}
 
+	if *convertCache != "" {
+		n, err := ConvertCacheFiles(strings.ToLower(*convertCache))
+		if err != nil {
+			printError("转换缓存失败 - %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已将 %d 个缓存文件转换为 %s 格式！\n", n, strings.ToLower(*convertCache))
+		return
+	}
+
+	if *migrateFrom != "" {
+		if *migrateTo == "" {
+			printError("--migrate-from 需要同时指定 --migrate-to")
+			os.Exit(2)
+		}
+		if err := MigrateFile(*migrateFrom, *migrateTo, cacheVersion); err != nil {
+			printError("迁移失败 - %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已将 %s 迁移到版本 %d 并保存到 %s！\n", *migrateFrom, cacheVersion, *migrateTo)
+		return
+	}
+
+	if *watch {
+		if err := runWatch(); err != nil {
+			printError("监听失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *serve != "" {
+		if (*tlsCert == "") != (*tlsKey == "") {
+			printError("--tls-cert 和 --tls-key 需要同时指定")
+			os.Exit(2)
+		}
+		if err := runServer(*serve, *apiToken, *tlsCert, *tlsKey); err != nil {
+			printError("启动服务失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *pipeName != "" {
+		fmt.Printf("正在命名管道 %s 上接受请求，按 Ctrl-C 退出...\n", pipePath(*pipeName))
+		if err := StartNamedPipeServer(*pipeName, NewManager()); err != nil {
+			printError("启动命名管道失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *exportPS1 != "" {
+		cache, err := loadCache()
+		if err == nil {
+			err = exportPowerShellFile(*exportPS1, cache.Items)
+		}
+		if err != nil {
+			printError("导出失败 - %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已成功导出 %d 项到 %s！\n", len(cache.Items), *exportPS1)
+		return
+	}
+
+	if *generateReport != "" {
+		if err := GenerateReport(*generateReport); err != nil {
+			printError("生成报告失败 - %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已生成健康报告 %s\n", *generateReport)
+		return
+	}
+
+	if *exportHTML != "" {
+		cache, err := loadCache()
+		if err == nil {
+			err = exportHTMLFile(*exportHTML, cache.Items)
+		}
+		if err != nil {
+			printError("导出失败 - %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已成功导出 %d 项到 %s！\n", len(cache.Items), *exportHTML)
+		return
+	}
+
+	if *exportAutoruns != "" {
+		cache, err := loadCache()
+		if err == nil {
+			err = exportAutorunsFile(*exportAutoruns, cache.Items)
+		}
+		if err != nil {
+			printError("导出失败 - %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已成功导出 %d 项到 %s！\n", len(cache.Items), *exportAutoruns)
+		return
+	}
+
+	if *exportBatch != "" {
+		cache, err := loadCache()
+		if err == nil {
+			err = exportBatchFile(*exportBatch, cache.Items, GenerateSetupBatch)
+		}
+		if err != nil {
+			printError("导出失败 - %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已成功导出 %d 项到 %s！\n", len(cache.Items), *exportBatch)
+		return
+	}
+
+	if *exportCleanupBatch != "" {
+		cache, err := loadCache()
+		if err == nil {
+			err = exportBatchFile(*exportCleanupBatch, cache.Items, GenerateCleanupBatch)
+		}
+		if err != nil {
+			printError("导出失败 - %v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已成功导出 %d 项到 %s！\n", len(cache.Items), *exportCleanupBatch)
+		return
+	}
+
+	if *fromProcess != "" {
+		pid, err := strconv.ParseUint(*fromProcess, 10, 32)
+		if err != nil {
+			printError("无效的进程 ID: %s", *fromProcess)
+			os.Exit(2)
+		}
+		if err := runAddFromProcess(uint32(pid), *entryName, *force); err != nil {
+			printError("添加失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *fromShortcut != "" {
+		if err := runAddFromShortcut(*fromShortcut, *entryName, *force); err != nil {
+			printError("添加失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *fromService != "" {
+		if err := runAddFromService(*fromService, *entryName, *force); err != nil {
+			printError("添加失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *runName != "" {
+		var err error
+		if *asSystem {
+			err = RunEntryAsSystem(*runName)
+		} else {
+			err = RunEntry(*runName, *detach)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			printError("%s 以退出代码 %d 结束", *runName, exitErr.ExitCode())
+			os.Exit(exitErr.ExitCode())
+		}
+		if err != nil {
+			printError("运行失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *killName != "" {
+		if err := runKill(*killName, *force, *killTimeout); err != nil {
+			printError("%v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *importPath != "" {
+		if err := runImportCSV(*importPath, *force); err != nil {
+			printError("导入失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *importAutoruns != "" {
+		if err := runImportAutoruns(*importAutoruns, *force); err != nil {
+			printError("导入失败 - %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 显示主菜单
+	showMainMenu()
+}
+
+// printUsage 显示命令行用法
+func printUsage() {
+	fmt.Fprintln(flag.CommandLine.Output(), "用法:")
+	fmt.Fprintln(flag.CommandLine.Output(), "  autostart interactive [选项]   进入交互菜单")
+	fmt.Fprintln(flag.CommandLine.Output(), "  autostart <选项>               执行选项对应的操作后退出")
+	fmt.Fprintln(flag.CommandLine.Output(), "\n选项:")
+	flag.PrintDefaults()
+}
+
+// runWatch 监听注册表启动项变化，每次变化后同步缓存，直到按下 Ctrl-C
+func runWatch() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	events, err := WatchRegistry(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("正在监听自启动项变化，按 Ctrl-C 退出...")
+	for event := range events {
+		timestamp := time.Now().Format("2006-01-02 15:04:05")
+		switch event.ChangeType {
+		case ChangeAdded:
+			fmt.Printf("[%s] 新增: %s = %s\n", timestamp, event.Name, event.NewValue)
+		case ChangeRemoved:
+			fmt.Printf("[%s] 删除: %s\n", timestamp, event.Name)
+		case ChangeModified:
+			fmt.Printf("[%s] 修改: %s = %s\n", timestamp, event.Name, event.NewValue)
+		}
+		syncCacheFromRegistry()
+	}
+	return nil
+}
+
+// loadCache 加载缓存文件
+func loadCache() (*CacheData, error) {
+	data, err := loadCacheFile(cacheFilePath)
+	if err != nil {
+		return nil, err
+	}
+	checkCacheOwner(data)
+	return data, nil
+}
+
+// loadCacheFile 加载指定路径的缓存文件，文件不存在时返回空缓存
+func loadCacheFile(path string) (*CacheData, error) {
+	unlock, err := acquireCacheLock(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	data, err := loadCacheAuto(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &CacheData{}, nil
+		}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field == "version" {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedCacheVersion, typeErr.Value)
+		}
+		return nil, err
+	}
+
+	if err := migrateCache(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// saveCache 保存缓存文件
+func saveCache(data *CacheData) error {
+	invalidateStats()
+	if dryRunf("保存缓存文件 %s（%d 项）", cacheFilePath, len(data.Items)) {
+		return nil
+	}
+
+	unlock, err := acquireCacheLock(cacheFilePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if config.AutoBackup {
+		if err := backupCacheFile(cacheFilePath); err != nil {
+			return fmt.Errorf("备份缓存失败: %v", err)
+		}
+	}
+
+	data.Version = cacheVersion
+	stampCacheOwner(data)
+	return saveCacheAuto(cacheFilePath, data)
+}
+
+// findItemByName 根据名称查找缓存项
+func findItemByName(data *CacheData, name string) (int, *CacheItem) {
+	for i, item := range data.Items {
+		if item.Name == name {
+			return i, &item
+		}
+	}
+	return -1, nil
+}
+
+// FindDuplicateName 检查缓存中是否已有同名的启动项
+// 注册表值名称不区分大小写，因此名称也不区分大小写比较
+func FindDuplicateName(data *CacheData, name string) bool {
+	for _, item := range data.Items {
+		if strings.EqualFold(item.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindDuplicateValue 检查缓存中是否已有启动同一程序的启动项，返回该启动项的名称
+// 只比较程序路径（不区分大小写），不比较启动参数
+func FindDuplicateValue(data *CacheData, value string) (string, bool) {
+	exePath, _, err := parseCommandPath(value)
+	if err != nil || exePath == "" {
+		return "", false
+	}
+	for _, item := range data.Items {
+		itemPath, _, err := parseCommandPath(unwrapWindowState(item.Value, item.Name, item.WindowState))
+		if err == nil && strings.EqualFold(filepath.Clean(itemPath), filepath.Clean(exePath)) {
+			return item.Name, true
+		}
+	}
+	return "", false
+}
+
+// confirmDuplicates 添加启动项前检查名称和程序是否已存在，返回是否继续添加
+func confirmDuplicates(cache *CacheData, name, value string) bool {
+	if FindDuplicateName(cache, name) {
+		if !confirmYes(fmt.Sprintf("已存在名为 %s 的启动项，是否覆盖？(y/n): ", name)) {
+			return false
+		}
+	}
+	if other, dup := FindDuplicateValue(cache, value); dup && other != name {
+		if !confirmYes(fmt.Sprintf("该程序已注册为 %s，是否仍要添加？(y/n): ", other)) {
+			return false
+		}
+	}
+	return true
+}
+
+// addOrUpdateItem 添加或更新缓存项，返回缓存中该项的指针以便设置其他字段
+func addOrUpdateItem(data *CacheData, name, value string, enabled bool) *CacheItem {
+	idx, _ := findItemByName(data, name)
+	if idx < 0 {
+		data.Items = append(data.Items, CacheItem{
+			Name:      name,
+			CreatedAt: time.Now(),
+			Priority:  nextPriority(data),
+		})
+		idx = len(data.Items) - 1
+	}
+
+	item := &data.Items[idx]
+	item.Value = value
+	item.Enabled = enabled
+	refreshFileModTime(item)
+	return item
+}
+
+// removeItem 从缓存中删除项
+func removeItem(data *CacheData, name string) {
+	idx, _ := findItemByName(data, name)
+	if idx >= 0 {
+		data.Items = append(data.Items[:idx], data.Items[idx+1:]...)
+	}
+}
+
+// showMainMenu 显示主菜单
+func showMainMenu() {
+	for {
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println("        Windows 自启动设置工具")
+		if summary := statsSummary(); summary != "" {
+			fmt.Println(summary)
+		}
+		if currentProfile != "" {
+			fmt.Printf("当前配置方案: %s\n", currentProfile)
+		}
+		if dryRun {
+			printer.Print("试运行模式（--dry-run）：不会修改注册表和缓存\n", colorYellow)
+		}
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Println("1. 添加程序到自启动")
+		fmt.Println("2. 移除程序的自启动")
+		fmt.Println("3. 查看当前自启动状态")
+		fmt.Println("4. 添加命令到自启动")
+		fmt.Println("5. 启用")
+		fmt.Println("6. 禁用")
+		fmt.Println("7. 清理失效的启动项")
+		fmt.Println("8. 校验所有启动项")
+		fmt.Println("9. 导入/导出")
+		fmt.Println("10. 管理启动文件夹")
+		fmt.Println("11. 重命名启动项")
+		fmt.Println("12. 编辑启动项")
+		fmt.Println("13. 撤销上一步操作")
+		fmt.Println("14. 切换配置方案")
+		fmt.Println("15. 显示差异（缓存与注册表）")
+		fmt.Println("16. 从注册表重建缓存")
+		fmt.Println("17. 清除已禁用的启动项")
+		fmt.Println("18. 批量启用")
+		fmt.Println("19. 批量禁用")
+		fmt.Println("20. 查看注册表实时状态")
+		fmt.Println("21. 管理登录时运行的计划任务")
+		fmt.Println("22. 编辑偏好设置")
+		fmt.Println("23. 已归档的启动项")
+		fmt.Println("24. 从正在运行的程序中添加")
+		fmt.Println("25. 查看启动项历史")
+		fmt.Println("26. 显示高风险启动项")
+		fmt.Println("27. 复制启动项")
+		fmt.Println("28. 试运行启动项")
+		fmt.Println("29. 搜索启动项")
+		fmt.Println("30. 高级搜索（正则表达式）")
+		fmt.Println("31. 规范化所有启动项的值")
+		fmt.Println("32. 调整启动项顺序")
+		fmt.Println("33. 一次性启动项（RunOnce）")
+		fmt.Println("34. 结束启动项的进程")
+		fmt.Println("35. 在当前用户和所有用户之间移动启动项")
+		fmt.Println("0. 退出")
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Print("请选择操作 (0-35): ")
+
+		reader := bufio.NewReader(os.Stdin)
+		choice, _ := reader.ReadString('\n')
+		choice = strings.TrimSpace(choice)
+
+		switch choice {
+		case "1":
+			handleAddToStartup()
+		case "2":
+			handleRemoveFromStartup()
+		case "3":
+			handleShowStatus()
+		case "4":
+			handleAddCommand()
+		case "5":
+			handleEnable()
+		case "6":
+			handleDisable()
+		case "7":
+			handleCleanOrphans()
+		case "8":
+			handleValidateEntries()
+		case "9":
+			handleImportExport()
+		case "10":
+			handleStartupFolder()
+		case "11":
+			handleRename()
+		case "12":
+			handleEditEntry()
+		case "13":
+			handleUndo()
+		case "14":
+			handleSwitchProfile()
+		case "15":
+			handleShowDiff()
+		case "16":
+			handleRebuildCache()
+		case "17":
+			handlePurge()
+		case "18":
+			handleBatchEnable()
+		case "19":
+			handleBatchDisable()
+		case "20":
+			handleShowLiveRegistry()
+		case "21":
+			handleScheduledTasks()
+		case "22":
+			handleEditPreferences()
+		case "23":
+			handleArchive()
+		case "24":
+			handleDiscoverProcesses()
+		case "25":
+			handleShowHistory()
+		case "26":
+			handleShowHighRisk()
+		case "27":
+			handleClone()
+		case "28":
+			handleTestLaunch()
+		case "29":
+			handleSearch()
+		case "30":
+			handleAdvancedSearch()
+		case "31":
+			handleNormalizeValues()
+		case "32":
+			handleReorder()
+		case "33":
+			handleRunOnce()
+		case "34":
+			handleKillEntry()
+		case "35":
+			handleMoveScope()
+		case "0":
+			fmt.Println("再见！")
+			return
+		default:
+			fmt.Println("无效的选择，请重新输入。")
+		}
+	}
+}
+
+// handleAddToStartup 处理添加自启动
+func handleAddToStartup() {
+	exePath := selectExeFile()
+	if exePath == "" {
+		return // 用户取消了选择
+	}
+
+	// 获取程序名称作为注册表项名称
+	appName := getAppName(exePath)
+
+	syncIfStale()
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	// 检查名称和程序是否已经在自启动中
+	absPath, _ := filepath.Abs(exePath)
+	if !confirmDuplicates(cache, appName, buildCommand(absPath, "")) {
+		return
+	}
+
+	// 启动参数
+	args := readInput("请输入启动参数（可选，如 --minimized）: ")
+
+	// 启动时的窗口状态
+	var windowState string
+	for {
+		windowState = strings.ToLower(readInput("启动时的窗口状态 (normal/minimized/hidden，默认 normal): "))
+		if windowState == "" {
+			windowState = windowStateNormal
+		}
+		if isValidWindowState(windowState) {
+			break
+		}
+		fmt.Println("无效的窗口状态，请输入 normal、minimized 或 hidden。")
+	}
+
+	// 延迟启动的秒数
+	var delaySeconds int
+	for {
+		input := readInput("登录后延迟多少秒启动（可选，默认不延迟）: ")
+		if input == "" {
+			break
+		}
+		n, err := strconv.Atoi(input)
+		if err == nil && n >= 0 {
+			delaySeconds = n
+			break
+		}
+		fmt.Println("请输入非负整数。")
+	}
+
+	// 启动时的工作目录
+	var workDir string
+	for {
+		workDir = readInput("工作目录（可选，输入 . 使用程序所在目录，留空不切换）: ")
+		if workDir == "." {
+			workDir = filepath.Dir(absPath)
+		}
+		if workDir == "" || isExistingDir(workDir) {
+			break
+		}
+		fmt.Println("目录不存在，请重新输入。")
+	}
+
+	// 开机启动的原因
+	reason := readInput("为什么需要开机启动这个程序？（可选）: ")
+
+	// 到期后自动禁用
+	expiresAt := readExpiryDate()
+
+	// 构建注册表值
+	regValue, _ := startupCommand(absPath, args)
+	regValue = windowStateCommand(regValue, appName, windowState)
+
+	// 确认添加
+	fmt.Printf("\n确定要将以下程序添加到自启动吗？\n")
+	fmt.Printf("程序路径: %s\n", exePath)
+	fmt.Printf("程序名称: %s\n", appName)
+	if args != "" {
+		fmt.Printf("启动参数: %s\n", args)
+	}
+	if windowState != windowStateNormal {
+		fmt.Printf("窗口状态: %s\n", windowState)
+	}
+	if delaySeconds > 0 {
+		fmt.Printf("延迟启动: %d 秒\n", delaySeconds)
+	}
+	if workDir != "" {
+		fmt.Printf("工作目录: %s\n", workDir)
+	}
+	if expiresAt != nil {
+		fmt.Printf("到期日期: %s\n", expiresAt.Format("2006-01-02"))
+	}
+	fmt.Print("确认添加？(y/n): ")
+	reader := bufio.NewReader(os.Stdin)
+	confirm, _ := reader.ReadString('\n')
+	confirm = strings.TrimSpace(strings.ToLower(confirm))
+	if confirm == "y" || confirm == "yes" {
+		// 添加到注册表
+		undo := newUndoRecord(OpAdd, appName)
+		err := addStartupEntry(exePath, appName, args, windowState, workDir, delaySeconds)
+		if err != nil {
+			fmt.Printf("添加失败: %v\n", err)
+			printError("添加失败 - %v", err)
+		} else {
+			// 更新缓存
+			cache, _ := loadCache()
+			item := addOrUpdateItem(cache, appName, regValue, true)
+			recordHistory(item, HistoryAdded, "", regValue)
+			item.Arguments = args
+			item.Type = startupFileType(absPath)
+			item.WindowState = windowState
+			refreshFileModTime(item)
+			item.DelaySeconds = delaySeconds
+			item.WorkingDir = workDir
+			if hash, err := computeFileHash(absPath); err == nil {
+				item.FileHash = hash
+			}
+			if reason != "" {
+				item.Reason = reason
+			}
+			item.ExpiresAt = expiresAt
+			if err := saveCache(cache); err != nil {
+				printError("保存缓存失败 - %v", err)
+			}
+			pushUndo(undo)
+
+			fmt.Printf("已成功将 %s 添加到自启动！\n", appName)
+		}
+	}
+}
+
+// handleRemoveFromStartup 处理移除自启动
+func handleRemoveFromStartup() {
+	// 获取所有自启动项
+	values, err := startupRegistry.ReadValues(runKeyPath)
+	if err != nil {
+		fmt.Printf("无法读取注册表: %v\n", err)
+		return
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		fmt.Println("当前没有设置任何自启动程序。")
+		return
+	}
+
+	// 显示自启动项列表
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("当前自启动程序列表：")
+	fmt.Println(strings.Repeat("=", 60))
+
+	type startupItem struct {
+		name  string
+		value string
+		index int
+	}
+
+	items := make([]startupItem, 0, len(names))
+	for i, name := range names {
+		items = append(items, startupItem{
+			name:  name,
+			value: values[name],
+			index: i + 1,
+		})
+		fmt.Printf("%d. %s\n   %s\n\n", i+1, name, values[name])
+	}
+
+	// 让用户选择要移除的项
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Print("请输入要移除的项编号（或输入 'b' 返回）: ")
+
+	reader := bufio.NewReader(os.Stdin)
+	choice, _ := reader.ReadString('\n')
+	choice = strings.TrimSpace(choice)
+
+	if choice == "b" || choice == "B" {
+		return
+	}
+
+	// 解析序号
+	num, err := strconv.Atoi(choice)
+	if err != nil {
+		fmt.Println("无效的编号。")
+		return
+	}
+
+	if num < 1 || num > len(items) {
+		fmt.Println("无效的编号。")
+		return
+	}
+
+	selectedItem := items[num-1]
+
+	// 确认移除
+	fmt.Printf("\n确定要从自启动中移除以下程序吗？\n")
+	fmt.Printf("程序名称: %s\n", selectedItem.name)
+	fmt.Printf("程序路径: %s\n", selectedItem.value)
+	fmt.Print("确认移除？(y/n): ")
+	confirm, _ := reader.ReadString('\n')
+	confirm = strings.TrimSpace(strings.ToLower(confirm))
+	if confirm == "y" || confirm == "yes" {
+		if err := RemoveEntry(selectedItem.name); err != nil {
+			printError("移除失败 - %v", err)
+		} else {
+			fmt.Printf("已成功从自启动中移除 %s！\n", selectedItem.name)
+		}
+	}
+}
+
+// AddCommandEntry 将命令添加到自启动并记录到缓存，reason 为可选的开机启动原因
+func AddCommandEntry(name, command, reason string) error {
+	command = normalizeRegistryValue(command)
+	undo := newUndoRecord(OpAdd, name)
+	if err := AddCommandToStartup(command, name); err != nil {
+		return err
+	}
+
+	// 更新缓存
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	item := addOrUpdateItem(cache, name, command, true)
+	recordHistory(item, HistoryAdded, "", command)
+	if reason != "" {
+		item.Reason = reason
+	}
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	pushUndo(undo)
+	return nil
+}
+
+// AddProgramEntry 将程序添加到自启动并记录到缓存，args 和 workDir 可以为空
+func AddProgramEntry(exePath, name, args, workDir string) error {
+	absPath, err := filepath.Abs(exePath)
+	if err != nil {
+		return fmt.Errorf("获取绝对路径失败: %v", err)
+	}
+
+	undo := newUndoRecord(OpAdd, name)
+	if err := addStartupEntry(absPath, name, args, windowStateNormal, workDir, 0); err != nil {
+		return err
+	}
+
+	// 更新缓存
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	value, _ := startupCommand(absPath, args)
+	item := addOrUpdateItem(cache, name, value, true)
+	recordHistory(item, HistoryAdded, "", value)
+	item.Arguments = args
+	item.Type = startupFileType(absPath)
+	item.WorkingDir = workDir
+	refreshFileModTime(item)
+	if hash, err := computeFileHash(absPath); err == nil {
+		item.FileHash = hash
+	}
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	pushUndo(undo)
+	return nil
+}
+
+// runAddProgram 命令行模式下将程序添加到自启动，name 为空时使用程序文件名
+// 已有同名的启动项时除非 force 为 true，否则不覆盖
+func runAddProgram(exePath, name, args, workDir string, force bool) error {
+	if name == "" {
+		name = getAppName(exePath)
+	}
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	if FindDuplicateName(cache, name) && !force {
+		return fmt.Errorf("已存在名为 %s 的启动项，使用 --force 覆盖或用 --name 指定其他名称", name)
+	}
+
+	if err := AddProgramEntry(exePath, name, args, workDir); err != nil {
+		return err
+	}
+	fmt.Printf("已成功将 %s 添加到自启动！\n", name)
+	return nil
+}
+
+// RemoveEntry 从自启动中移除启动项并删除其缓存记录，已禁用的项只删除缓存记录和生成的启动脚本
+func RemoveEntry(name string) error {
+	undo := newUndoRecord(OpRemove, name)
+	if before := undo.Before; before != nil && !before.Enabled {
+		if err := removeStartWrappers(name); err != nil {
+			return err
+		}
+		auditEvent(EventRemove, "移除", name)
+	} else {
+		scope := ScopeCurrentUser
+		if before != nil {
+			scope = before.Scope
+		}
+		if err := removeFromRunKey(name, scope); err != nil {
+			return err
+		}
+	}
+
+	// 从缓存中删除
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	removeItem(cache, name)
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+
+	// 禁用时保留隐藏窗口的 .vbs 以便重新启用，彻底移除时一并删除
+	if err := removeWindowStateWrapper(name); err != nil {
+		return err
+	}
+	pushUndo(undo)
+	return nil
+}
+
+// SortOrder 查看自启动状态时的排序方式
+type SortOrder int
+
+const (
+	SortByName     SortOrder = iota // 按调整过的顺序，相同时按名称
+	SortByRunCount                  // 按运行次数从多到少，次数相同时按名称
+)
+
+// 查看自启动状态时使用的排序方式
+var statusSort = SortByName
+
+// 为 true 时查看自启动状态只显示程序正在运行的项（--show-running）
+var showRunningOnly bool
+
+// handleShowStatus 询问筛选条件后显示自启动状态
+// 名称留空时使用命令行 --filter 指定的正则表达式
+func handleShowStatus() {
+	namePattern := statusFilter
+	if name := readInput("按名称筛选（留空显示全部）: "); name != "" {
+		namePattern = regexp.QuoteMeta(name)
+	}
+	tag := readInput("按标签筛选（留空显示全部）: ")
+
+	statusSort = SortByName
+	if readInput("排序方式（1. 自定义顺序  2. 运行次数最多，默认 1）: ") == "2" {
+		statusSort = SortByRunCount
+	}
+
+	showStartupStatusFiltered(namePattern, tag, false, false, false)
+}
+
+// showStartupStatusFiltered 按条件筛选后显示自启动程序
+// namePattern 为匹配名称的正则表达式，tag 为必须包含的标签，均为空时不筛选
+// highRiskOnly 为 true 时只显示高风险的启动项
+func showStartupStatusFiltered(namePattern, tag string, enabledOnly, disabledOnly, highRiskOnly bool) {
+	if _, err := compileSearchPattern(namePattern); err != nil {
+		fmt.Printf("无效的筛选表达式: %v\n", err)
+		return
+	}
+
+	// 短时间内反复查看时直接使用缓存，避免重复读取注册表
+	syncIfStale()
+
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	// 对所有用户生效的启动项和组策略下发的启动项不能在这里修改，在列表末尾单独显示
+	if tag == "" && !disabledOnly && !highRiskOnly && !showRunningOnly {
+		defer func() {
+			machineItems, _ := ListMachineStartupEntries()
+			showReadOnlyEntries("所有用户（只读）", untrackedMachineItems(cache, machineItems), namePattern)
+
+			policyItems, err := ListGroupPolicyStartupEntries()
+			if err != nil {
+				fmt.Printf("读取组策略启动项失败: %v\n", err)
+			}
+			showReadOnlyEntries("组策略（只读）", policyItems, namePattern)
+
+			if allSources {
+				showWinlogonEntries()
+			}
+		}()
+	}
+
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("当前自启动程序列表：")
 	fmt.Println(strings.Repeat("=", 60))
 
-	if len(cache.Items) == 0 {
-		fmt.Println("当前没有配置任何自启动程序。")
+	folderEntries, err := ListStartupFolderEntries()
+	if err != nil {
+		fmt.Printf("读取启动文件夹失败: %v\n", err)
+	}
+
+	// 注册表和启动文件夹中的启动项合并显示，用来源区分
+	type statusRow struct {
+		source   string
+		item     CacheItem
+		orphaned bool
+		risk     RiskLevel
+		running  bool
+		pid      int
+	}
+
+	rows := make([]statusRow, 0, len(cache.Items)+len(folderEntries))
+	for _, item := range cache.Items {
+		source := "注册表"
+		if item.RunOnce {
+			source = "RunOnce"
+		} else if item.Scope == ScopeMachineWide {
+			source = "所有用户"
+		}
+		rows = append(rows, statusRow{
+			source:   source,
+			item:     item,
+			orphaned: isOrphaned(item),
+			risk:     entryRisk(item),
+		})
+	}
+	for _, entry := range folderEntries {
+		rows = append(rows, statusRow{
+			source: "启动文件夹",
+			item: CacheItem{
+				Name:    entry.Name,
+				Value:   entry.commandLine(),
+				Enabled: true,
+			},
+			orphaned: entry.TargetPath != "" && !isExistingFile(entry.TargetPath),
+			risk:     AssessPathRisk(entry.TargetPath),
+		})
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("当前没有配置任何自启动程序。")
+		return
+	}
+
+	// 检查启用的项对应的程序是否正在运行
+	for i := range rows {
+		if !rows[i].item.Enabled {
+			continue
+		}
+		if name := entryProcessName(rows[i].item); name != "" {
+			rows[i].running, rows[i].pid, _ = IsRunning(name)
+		}
+	}
+
+	// 名称筛选与高级搜索使用相同的匹配规则
+	rowData := &CacheData{Items: make([]CacheItem, 0, len(rows))}
+	for _, row := range rows {
+		rowData.Items = append(rowData.Items, row.item)
+	}
+	nameMatches, _ := SearchByRegex(rowData, namePattern, "")
+	matched := make(map[int]bool, len(nameMatches))
+	for _, i := range nameMatches {
+		matched[i] = true
+	}
+
+	total := len(rows)
+	filtered := rows[:0]
+	for i, row := range rows {
+		if !matched[i] {
+			continue
+		}
+		if tag != "" && !hasTag(row.item, tag) {
+			continue
+		}
+		if (enabledOnly && !row.item.Enabled) || (disabledOnly && row.item.Enabled) {
+			continue
+		}
+		if highRiskOnly && row.risk != RiskHigh {
+			continue
+		}
+		if showRunningOnly && !row.running {
+			continue
+		}
+		filtered = append(filtered, row)
+	}
+	rows = filtered
+
+	fmt.Printf("显示 %d / %d 项\n\n", len(rows), total)
+
+	// 排序显示
+	sort.Slice(rows, func(i, j int) bool {
+		if statusSort == SortByRunCount && rows[i].item.RunCount != rows[j].item.RunCount {
+			return rows[i].item.RunCount > rows[j].item.RunCount
+		}
+		if statusSort == SortByName && rows[i].item.Priority != rows[j].item.Priority {
+			return rows[i].item.Priority < rows[j].item.Priority
+		}
+		return rows[i].item.Name < rows[j].item.Name
+	})
+
+	hasOrphan := false
+	versionChanged := false
+	for i, row := range rows {
+		fmt.Printf("%d. [%s] %s ", i+1, row.source, row.item.Name)
+		printer.Print(statusChip(row.item.Enabled))
+		if label := fileTypeLabel(row.item.Type); label != "" {
+			fmt.Print(" [" + label + "]")
+		}
+		fmt.Print(" ")
+		printer.Print(riskBadge(row.risk))
+		if row.item.Enabled {
+			if row.running {
+				fmt.Print(" ")
+				printer.Print(fmt.Sprintf("[运行中: PID %d]", row.pid), colorCyan)
+			} else {
+				fmt.Print(" [未运行]")
+			}
+		}
+		if row.orphaned {
+			fmt.Print(" ")
+			printer.Print("[!]", colorYellow)
+			hasOrphan = true
+		}
+		fmt.Println()
+
+		// 程序路径和启动参数分开显示，窗口状态包装的命令显示被包装的程序
+		exePath, args, err := parseCommandPath(unwrapWindowState(row.item.Value, row.item.Name, row.item.WindowState))
+		if err != nil {
+			fmt.Printf("   %s\n\n", row.item.Value)
+			continue
+		}
+		if row.item.Arguments != "" {
+			args = row.item.Arguments
+		}
+		fmt.Printf("   程序: %s\n", exePath)
+		if args != "" {
+			fmt.Printf("   参数: %s\n", args)
+		}
+		if label := windowStateLabel(row.item.WindowState); label != "" {
+			fmt.Printf("   窗口: %s\n", label)
+		}
+		if row.item.DelaySeconds > 0 {
+			fmt.Printf("   延迟: %d 秒\n", row.item.DelaySeconds)
+		}
+		if row.item.WorkingDir != "" {
+			fmt.Printf("   工作目录: %s\n", row.item.WorkingDir)
+		}
+		if row.item.Reason != "" {
+			fmt.Printf("   原因: %s\n", row.item.Reason)
+		}
+		if row.item.ExpiresAt != nil {
+			if isExpired(row.item) {
+				fmt.Printf("   已于 %s 到期\n", row.item.ExpiresAt.Format("2006-01-02"))
+			} else {
+				fmt.Printf("   到期: %s\n", row.item.ExpiresAt.Format("2006-01-02"))
+			}
+		}
+		if !row.item.Enabled && row.item.LastDisabledReason != "" {
+			fmt.Printf("   禁用原因: \"%s\"\n", row.item.LastDisabledReason)
+		}
+		if verbose && row.source == "注册表" {
+			fmt.Printf("   运行次数: %d\n", row.item.RunCount)
+		}
+		if verbose && !row.orphaned {
+			if version := showFileDetails(row.item); version != "" && row.source == "注册表" {
+				if idx, _ := findItemByName(cache, row.item.Name); idx >= 0 && cache.Items[idx].FileVersion != version {
+					cache.Items[idx].FileVersion = version
+					versionChanged = true
+				}
+			}
+		}
+		fmt.Println()
+	}
+
+	// 记录最近一次看到的版本
+	if versionChanged {
+		if err := saveCache(cache); err != nil {
+			printError("保存缓存失败 - %v", err)
+		}
+	}
+
+	if hasOrphan {
+		fmt.Println("[!] 表示程序文件已不存在，可通过主菜单“清理失效的启动项”移除。")
+	}
+}
+
+// showFileDetails 显示启动项程序的版本和发布者，返回读取到的版本
+func showFileDetails(item CacheItem) string {
+	path, err := entryFilePath(item)
+	if err != nil {
+		return ""
+	}
+
+	version, err := GetFileVersion(path)
+	if err == nil {
+		fmt.Printf("   版本: %s\n", version)
+	} else if item.FileVersion != "" {
+		fmt.Printf("   版本: %s（上次记录）\n", item.FileVersion)
+	}
+	if publisher, err := GetFilePublisher(path); err == nil {
+		fmt.Printf("   发布者: %s\n", publisher)
+	}
+	return version
+}
+
+// hasTag 检查启动项是否带有指定标签（不区分大小写）
+func hasTag(item CacheItem, tag string) bool {
+	for _, t := range item.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectExeFile 选择启动文件（exe/bat/cmd/ps1）
+func selectExeFile() string {
+	currentDir, _ := os.Getwd()
+
+	for {
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println("选择程序文件")
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Printf("当前目录: %s\n", currentDir)
+		fmt.Print("请输入文件路径（或输入 'b' 返回，输入 'd' 浏览当前目录，输入 'g' 跳转到指定目录，输入 'v' 粘贴剪贴板中的路径）: ")
+
+		reader := bufio.NewReader(os.Stdin)
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		if input == "b" || input == "B" {
+			return ""
+		}
+
+		if input == "d" || input == "D" {
+			// 浏览当前目录
+			selected := browseDirectory(currentDir)
+			if selected != "" {
+				return selected
+			}
+			continue
+		}
+
+		if input == "v" || input == "V" {
+			// 使用剪贴板中的路径，如从资源管理器地址栏或“复制为路径”复制的路径
+			path, err := clipboardPath()
+			if err != nil {
+				fmt.Printf("无法读取剪贴板: %v\n", err)
+				continue
+			}
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(currentDir, path)
+			}
+			if ok, _ := isValidStartupFile(path); ok {
+				fmt.Printf("已粘贴: %s\n", path)
+				return path
+			}
+			fmt.Printf("剪贴板中不是有效的启动文件（exe/bat/cmd/ps1）: %s\n", path)
+			continue
+		}
+
+		if input == "g" || input == "G" {
+			// 跳转到指定目录
+			fmt.Print("请输入要跳转的目录路径: ")
+			newPath, _ := reader.ReadString('\n')
+			newPath = strings.TrimSpace(newPath)
+
+			if newPath == "" {
+				continue
+			}
+
+			// 处理路径
+			var targetDir string
+			if filepath.IsAbs(newPath) {
+				targetDir = newPath
+			} else {
+				// 相对路径，相对于当前目录
+				targetDir = filepath.Join(currentDir, newPath)
+			}
+
+			// 检查是否是有效目录
+			info, err := os.Stat(targetDir)
+			if err != nil {
+				fmt.Printf("目录不存在或无法访问: %v\n", err)
+				fmt.Print("按回车键继续...")
+				reader.ReadString('\n')
+				continue
+			}
+
+			if !info.IsDir() {
+				fmt.Printf("路径不是目录: %s\n", targetDir)
+				fmt.Print("按回车键继续...")
+				reader.ReadString('\n')
+				continue
+			}
+
+			// 更新当前目录
+			absTarget, _ := filepath.Abs(targetDir)
+			currentDir = absTarget
+			continue
+		}
+
+		// 检查是否是完整路径
+		if filepath.IsAbs(input) {
+			if ok, _ := isValidStartupFile(input); ok {
+				return input
+			}
+			fmt.Printf("文件不存在或不是有效的启动文件（exe/bat/cmd/ps1）: %s\n", input)
+			continue
+		}
+
+		// 尝试作为相对路径
+		absPath, err := filepath.Abs(input)
+		if err == nil {
+			if ok, _ := isValidStartupFile(absPath); ok {
+				return absPath
+			}
+		}
+
+		fmt.Printf("文件不存在或不是有效的启动文件（exe/bat/cmd/ps1）: %s\n", input)
+	}
+}
+
+// browseDirectory 浏览目录（只一层）
+func browseDirectory(dirPath string) string {
+	absDir, err := filepath.Abs(dirPath)
+	if err != nil {
+		fmt.Printf("无法获取目录路径: %v\n", err)
+		return ""
+	}
+
+	// 读取目录内容
+	entries, err := os.ReadDir(absDir)
+	if err != nil {
+		fmt.Printf("无法读取目录: %v\n", err)
+		return ""
+	}
+
+	// 过滤出启动文件和目录
+	var exeFiles []string
+	var dirs []string
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, entry.Name())
+		} else if startupFileType(entry.Name()) != "" {
+			exeFiles = append(exeFiles, entry.Name())
+		}
+	}
+
+	// 排序
+	sort.Strings(dirs)
+	sort.Strings(exeFiles)
+
+	// 目录在前，启动文件在后，Value 为完整路径
+	var items []ListItem
+	for _, dir := range dirs {
+		items = append(items, ListItem{Name: dir + "/ (目录)", Value: filepath.Join(absDir, dir)})
+	}
+	for _, file := range exeFiles {
+		items = append(items, ListItem{Name: file, Value: filepath.Join(absDir, file)})
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	pages := pageCount(len(items), pageSize)
+	page := 0
+	var choice string
+
+	for {
+		// 显示文件列表
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Printf("当前目录: %s\n", absDir)
+		fmt.Println(strings.Repeat("=", 60))
+
+		if len(items) == 0 {
+			fmt.Println("当前目录没有找到可执行文件、脚本或子目录。")
+			return ""
+		}
+
+		start := page * pageSize
+		for i, item := range paginate(items, page, pageSize) {
+			index := start + i
+			// 每页开头和目录、文件的分界处显示分组标题
+			if i == 0 || index == len(dirs) {
+				if index < len(dirs) {
+					fmt.Println("\n目录:")
+				} else {
+					fmt.Println("\n可执行文件和脚本:")
+				}
+			}
+			fmt.Printf("  [%d] %s\n", index+1, item.Name)
+		}
+
+		// 让用户选择
+		fmt.Println(strings.Repeat("=", 60))
+		if pages > 1 {
+			fmt.Printf("第 %d / %d 页（输入 'n' 下一页，'p' 上一页）\n", page+1, pages)
+		}
+		fmt.Print("请输入序号选择文件（或输入 'b' 返回，输入 'u' 返回上一级，输入 'g' 跳转到指定目录）: ")
+
+		choice, _ = reader.ReadString('\n')
+		choice = strings.TrimSpace(choice)
+
+		if next, ok := turnPage(choice, page, pages); ok {
+			page = next
+			continue
+		}
+		break
+	}
+
+	if choice == "b" || choice == "B" {
+		return ""
+	}
+
+	if choice == "u" || choice == "U" {
+		parentDir := filepath.Dir(absDir)
+		if parentDir != absDir {
+			return browseDirectory(parentDir)
+		}
+		return browseDirectory(absDir)
+	}
+
+	if choice == "g" || choice == "G" {
+		// 跳转到指定目录
+		fmt.Print("请输入要跳转的目录路径: ")
+		newPath, _ := reader.ReadString('\n')
+		newPath = strings.TrimSpace(newPath)
+
+		if newPath == "" {
+			return browseDirectory(absDir)
+		}
+
+		// 处理路径
+		var targetDir string
+		if filepath.IsAbs(newPath) {
+			targetDir = newPath
+		} else {
+			// 相对路径，相对于当前目录
+			targetDir = filepath.Join(absDir, newPath)
+		}
+
+		// 检查是否是有效目录
+		info, err := os.Stat(targetDir)
+		if err != nil {
+			fmt.Printf("目录不存在或无法访问: %v\n", err)
+			fmt.Print("按回车键继续...")
+			reader.ReadString('\n')
+			return browseDirectory(absDir)
+		}
+
+		if !info.IsDir() {
+			fmt.Printf("路径不是目录: %s\n", targetDir)
+			fmt.Print("按回车键继续...")
+			reader.ReadString('\n')
+			return browseDirectory(absDir)
+		}
+
+		// 跳转到新目录
+		return browseDirectory(targetDir)
+	}
+
+	// 解析序号
+	num, err := strconv.Atoi(choice)
+	if err != nil || num < 1 || num > len(items) {
+		fmt.Println("无效的序号。")
+		return ""
+	}
+	selectedPath := items[num-1].Value
+
+	// 检查是否是目录
+	info, err := os.Stat(selectedPath)
+	if err != nil {
+		fmt.Printf("无法访问: %v\n", err)
+		return ""
+	}
+
+	if info.IsDir() {
+		// 进入子目录
+		return browseDirectory(selectedPath)
+	}
+
+	// 返回选中的exe文件
+	return selectedPath
+}
+
+// 启动文件类型
+const (
+	fileTypeExe        = "exe"
+	fileTypeBatch      = "batch"
+	fileTypePowerShell = "powershell"
+)
+
+// startupFileType 根据扩展名判断启动文件类型，不支持的类型返回空字符串
+func startupFileType(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".exe":
+		return fileTypeExe
+	case ".bat", ".cmd":
+		return fileTypeBatch
+	case ".ps1":
+		return fileTypePowerShell
+	}
+	return ""
+}
+
+// isValidStartupFile 检查是否是可以自启动的文件，并返回对应的启动命令
+// exe 直接启动，bat/cmd 通过 cmd.exe 启动，ps1 通过 powershell.exe 启动
+func isValidStartupFile(path string) (bool, string) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false, ""
+	}
+
+	switch startupFileType(path) {
+	case fileTypeExe:
+		return true, fmt.Sprintf(`"%s"`, path)
+	case fileTypeBatch:
+		return true, fmt.Sprintf(`cmd.exe /c "%s"`, path)
+	case fileTypePowerShell:
+		return true, fmt.Sprintf(`powershell.exe -ExecutionPolicy Bypass -File "%s"`, path)
+	}
+	return false, ""
+}
+
+// fileTypeLabel 启动文件类型的显示名称
+func fileTypeLabel(fileType string) string {
+	switch fileType {
+	case fileTypeBatch:
+		return "批处理"
+	case fileTypePowerShell:
+		return "PowerShell"
+	}
+	return ""
+}
+
+// getAppName 从文件路径获取程序名称（不含扩展名）
+func getAppName(exePath string) string {
+	base := filepath.Base(exePath)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// parseCommandPath 从启动命令中拆分出可执行文件路径和参数
+// 支持带引号的路径、不带引号的路径，以及含有空格但未加引号的路径
+func parseCommandPath(cmd string) (string, string, error) {
+	cmd = strings.TrimSpace(cmd)
+	if cmd == "" {
+		return "", "", fmt.Errorf("命令为空")
+	}
+
+	// 带引号的路径：取第一对引号中的内容
+	if strings.HasPrefix(cmd, `"`) {
+		end := strings.Index(cmd[1:], `"`)
+		if end < 0 {
+			return "", "", fmt.Errorf("引号不匹配: %s", cmd)
+		}
+		exePath := strings.TrimSpace(cmd[1 : end+1])
+		if exePath == "" {
+			return "", "", fmt.Errorf("程序路径为空: %s", cmd)
+		}
+		return expandEnvPath(exePath), strings.TrimSpace(cmd[end+2:]), nil
+	}
+
+	// 不带引号但路径中含有空格（如 C:\Program Files\app.exe --flag），
+	// 从最长的前缀开始尝试，取磁盘上实际存在的文件
+	for i := len(cmd); i > 0; i = strings.LastIndex(cmd[:i], " ") {
+		candidate := expandEnvPath(strings.TrimSpace(cmd[:i]))
+		if isExistingFile(candidate) {
+			return candidate, strings.TrimSpace(cmd[i:]), nil
+		}
+	}
+
+	// 都不存在时退回到第一个空格前的部分（如 python script.py、cmd.exe /c ...）
+	if idx := strings.Index(cmd, " "); idx >= 0 {
+		return expandEnvPath(cmd[:idx]), strings.TrimSpace(cmd[idx+1:]), nil
+	}
+	return expandEnvPath(cmd), "", nil
+}
+
+// expandEnvPath 展开路径中的环境变量，支持 Windows 风格的 %VAR% 和 $VAR / ${VAR}
+// 未定义的变量保留原样（而不是像 os.ExpandEnv 那样替换为空），
+// 以免 \\server\c$ 之类的路径被改坏；只用于检查文件，缓存和注册表中保存原始形式
+func expandEnvPath(path string) string {
+	path = envVarPattern.ReplaceAllStringFunc(path, func(match string) string {
+		if value, ok := os.LookupEnv(match[1 : len(match)-1]); ok {
+			return value
+		}
+		return match
+	})
+	if !strings.Contains(path, "$") {
+		return path
+	}
+	return os.Expand(path, func(name string) string {
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		return "$" + name
+	})
+}
+
+// isExistingFile 检查路径是否是已存在的文件
+func isExistingFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// isExistingDir 检查路径是否是已存在的目录
+func isExistingDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// AddToStartup 添加程序到Windows自启动
+func AddToStartup(exePath, appName string) error {
+	return AddToStartupWithArgs(exePath, appName, "")
+}
+
+// AddToStartupWithArgs 添加程序及启动参数到Windows自启动
+func AddToStartupWithArgs(exePath, appName, args string) error {
+	return AddToStartupWithWindowState(exePath, appName, args, windowStateNormal)
+}
+
+// AddToStartupWithWindowState 添加程序到Windows自启动，并指定启动时的窗口状态
+func AddToStartupWithWindowState(exePath, appName, args, windowState string) error {
+	return AddToStartupWithDelay(exePath, appName, args, windowState, 0)
+}
+
+// AddToStartupWithDelay 添加程序到Windows自启动，登录后等待 delaySeconds 秒再启动
+// delaySeconds 大于 0 时在缓存文件所在目录生成 <appName>_delay.cmd 并注册该文件
+func AddToStartupWithDelay(exePath, appName, args, windowState string, delaySeconds int) error {
+	return addStartupEntry(exePath, appName, args, windowState, "", delaySeconds)
+}
+
+// AddToStartupWithWorkingDir 添加程序到Windows自启动，启动前先切换到 workDir
+// 在缓存文件所在目录生成 <appName>_workdir.cmd 并注册该文件
+func AddToStartupWithWorkingDir(exePath, appName, args, workDir string) error {
+	return addStartupEntry(exePath, appName, args, windowStateNormal, workDir, 0)
+}
+
+// addStartupEntry 添加程序到Windows自启动
+// workDir 不为空或 delaySeconds 大于 0 时通过生成的启动脚本启动
+func addStartupEntry(exePath, appName, args, windowState, workDir string, delaySeconds int) error {
+	if !isValidWindowState(windowState) {
+		return fmt.Errorf("无效的窗口状态: %s", windowState)
+	}
+	if delaySeconds < 0 {
+		return fmt.Errorf("延迟秒数不能为负数")
+	}
+	if workDir != "" && !isExistingDir(workDir) {
+		return fmt.Errorf("工作目录不存在: %s", workDir)
+	}
+
+	// 获取可执行文件的绝对路径
+	absPath, err := filepath.Abs(exePath)
+	if err != nil {
+		return fmt.Errorf("获取绝对路径失败: %v", err)
+	}
+
+	// 检查文件是否存在
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("文件不存在: %s", absPath)
+	}
+
+	value, ok := startupCommand(absPath, args)
+	if !ok {
+		return fmt.Errorf("不支持的启动文件类型: %s", absPath)
+	}
+
+	// 最小化或隐藏窗口时通过包装命令启动
+	if err := writeWindowStateWrapper(value, appName, windowState); err != nil {
+		return err
+	}
+	value = windowStateCommand(value, appName, windowState)
+
+	if err := registerStartupItem(CacheItem{
+		Name:         appName,
+		Value:        value,
+		DelaySeconds: delaySeconds,
+		WorkingDir:   workDir,
+	}); err != nil {
+		return err
+	}
+	auditEvent(EventAdd, "添加", appName)
+	return nil
+}
+
+// startupCommand 根据启动文件类型构建注册表中的启动命令
+func startupCommand(absPath, args string) (string, bool) {
+	ok, value := isValidStartupFile(absPath)
+	if !ok {
+		return "", false
+	}
+	if args != "" {
+		value += " " + args
+	}
+	return value, true
+}
+
+// buildCommand 构建启动命令：带引号的程序路径，后跟启动参数
+func buildCommand(exePath, args string) string {
+	value := fmt.Sprintf(`"%s"`, exePath)
+	if args != "" {
+		value += " " + args
+	}
+	return value
+}
+
+// RemoveFromStartup 从Windows自启动中移除程序
+func RemoveFromStartup(appName string) error {
+	if dryRunf("删除注册表值 %s", appName) {
+		return removeStartWrappers(appName)
+	}
+
+	// 删除注册表值
+	err := startupRegistry.DeleteValue(runKeyPath, appName)
+	if err != nil {
+		if err == registry.ErrNotExist {
+			if isGroupPolicyEntry(appName) {
+				return ErrReadOnly
+			}
+			return fmt.Errorf("启动项不存在")
+		}
+		return fmt.Errorf("删除注册表值失败: %v", err)
+	}
+	auditEvent(EventRemove, "移除", appName)
+
+	// 删除延迟启动或设置工作目录时生成的启动脚本
+	return removeStartWrappers(appName)
+}
+
+// ========== 公共基础函数 ==========
+
+// ListItem 列表项结构
+type ListItem struct {
+	Name  string
+	Value string
+}
+
+// paginate 返回第 page 页（从 0 开始）的列表项
+func paginate(items []ListItem, page, pageSize int) []ListItem {
+	start := page * pageSize
+	if start >= len(items) {
+		return nil
+	}
+	end := start + pageSize
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+// pageCount 计算总页数，至少为 1
+func pageCount(total, pageSize int) int {
+	if total <= pageSize {
+		return 1
+	}
+	return (total + pageSize - 1) / pageSize
+}
+
+// turnPage 处理翻页输入 'n' 和 'p'，返回新的页码以及输入是否为翻页命令
+func turnPage(choice string, page, pages int) (int, bool) {
+	switch strings.ToLower(choice) {
+	case "n":
+		if page+1 < pages {
+			page++
+		} else {
+			fmt.Println("已经是最后一页。")
+		}
+		return page, true
+	case "p":
+		if page > 0 {
+			page--
+		} else {
+			fmt.Println("已经是第一页。")
+		}
+		return page, true
+	}
+	return page, false
+}
+
+// readPagedChoice 分页显示列表并读取输入，翻页命令在内部处理
+// 序号按全部列表从 1 开始编号，与所在页无关
+func readPagedChoice(items []ListItem, title, prompt string, pageSize int) string {
+	reader := bufio.NewReader(os.Stdin)
+	pages := pageCount(len(items), pageSize)
+	page := 0
+
+	for {
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println(title)
+		fmt.Println(strings.Repeat("=", 60))
+
+		start := page * pageSize
+		for i, item := range paginate(items, page, pageSize) {
+			fmt.Printf("%d. ", start+i+1)
+			printer.Print(item.Name, colorCyan)
+			fmt.Printf("\n   %s\n\n", item.Value)
+		}
+
+		fmt.Println(strings.Repeat("=", 60))
+		if pages > 1 {
+			fmt.Printf("第 %d / %d 页（输入 'n' 下一页，'p' 上一页）\n", page+1, pages)
+		}
+		fmt.Print(prompt)
+
+		choice, _ := reader.ReadString('\n')
+		choice = strings.TrimSpace(choice)
+
+		if next, ok := turnPage(choice, page, pages); ok {
+			page = next
+			continue
+		}
+		return choice
+	}
+}
+
+// showListWithBack 分页显示列表，支持返回
+// 返回：选中项的索引（从 0 开始），是否有效选择
+func showListWithBack(items []ListItem, title string, pageSize int) (int, bool) {
+	if len(items) == 0 {
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println(title)
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Println("没有可显示的项。")
+		return -1, false
+	}
+
+	choice := readPagedChoice(items, title, "请输入序号（或输入 'b' 返回）: ", pageSize)
+
+	if choice == "b" || choice == "B" {
+		return -1, false
+	}
+
+	num, err := strconv.Atoi(choice)
+	if err != nil || num < 1 || num > len(items) {
+		fmt.Println("无效的编号。")
+		return -1, false
+	}
+
+	return num - 1, true
+}
+
+// showMultiSelectWithBack 显示列表，支持用逗号和范围（如 1,3,5-7）一次选择多项
+// 返回：从 0 开始的索引（升序、去重），是否有效选择
+func showMultiSelectWithBack(items []ListItem, title string, pageSize int) ([]int, bool) {
+	if len(items) == 0 {
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println(title)
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Println("没有可显示的项。")
+		return nil, false
+	}
+
+	choice := readPagedChoice(items, title, "请输入序号，多个用逗号分隔，范围用 - 表示（如 1,3,5-7，或输入 'b' 返回）: ", pageSize)
+
+	if choice == "b" || choice == "B" {
+		return nil, false
+	}
+
+	nums, err := parseIndexList(choice, len(items))
+	if err != nil {
+		fmt.Printf("无效的编号: %v\n", err)
+		return nil, false
+	}
+
+	indices := make([]int, len(nums))
+	for i, num := range nums {
+		indices[i] = num - 1
+	}
+	return indices, true
+}
+
+// parseIndexList 解析逗号分隔的序号和范围（如 "1,3,5-7"），返回升序、去重的序号
+// 序号从 1 开始，不能超过 max
+func parseIndexList(input string, max int) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end := part, part
+		if i := strings.Index(part, "-"); i >= 0 {
+			start, end = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+
+		from, err := strconv.Atoi(start)
+		if err != nil {
+			return nil, fmt.Errorf("无法解析 %q", part)
+		}
+		to, err := strconv.Atoi(end)
+		if err != nil {
+			return nil, fmt.Errorf("无法解析 %q", part)
+		}
+		if from > to {
+			return nil, fmt.Errorf("范围 %q 的起点大于终点", part)
+		}
+		if from < 1 || to > max {
+			return nil, fmt.Errorf("%q 超出范围 1-%d", part, max)
+		}
+
+		for i := from; i <= to; i++ {
+			seen[i] = true
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("没有输入序号")
+	}
+
+	indices := make([]int, 0, len(seen))
+	for i := range seen {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
+
+// confirmWithBack 确认操作，支持返回
+// 返回：true=确认，false=取消或返回
+func confirmWithBack(title, name, value string) bool {
+	fmt.Printf("\n确定要%s吗？\n", title)
+	fmt.Printf("名称: %s\n", name)
+	fmt.Printf("内容: %s\n", value)
+	fmt.Print("确认？(y/n/b 返回): ")
+
+	reader := bufio.NewReader(os.Stdin)
+	confirm, _ := reader.ReadString('\n')
+	confirm = strings.TrimSpace(strings.ToLower(confirm))
+
+	if confirm == "b" || confirm == "B" {
+		return false
+	}
+	return confirm == "y" || confirm == "yes"
+}
+
+// readInput 显示提示并读取一行输入
+func readInput(prompt string) string {
+	fmt.Print(prompt)
+	reader := bufio.NewReader(os.Stdin)
+	input, _ := reader.ReadString('\n')
+	return strings.TrimSpace(input)
+}
+
+// confirmYes 显示提示并读取 y/n 确认
+func confirmYes(prompt string) bool {
+	confirm := strings.ToLower(readInput(prompt))
+	return confirm == "y" || confirm == "yes"
+}
+
+// IsInStartup 检查程序是否已在自启动列表中
+func IsInStartup(exePath, appName string) (bool, error) {
+	// 检查值是否存在
+	value, err := startupRegistry.GetValue(runKeyPath, appName)
+	if err != nil {
+		if err == registry.ErrNotExist {
+			return false, nil
+		}
+		return false, fmt.Errorf("查询注册表值失败: %v", err)
+	}
+
+	// 检查路径是否匹配（忽略引号和参数）
+	valuePath, _, err := parseCommandPath(value)
+	if err != nil {
+		return false, nil
+	}
+	absPath, _ := filepath.Abs(exePath)
+	valueAbs, _ := filepath.Abs(valuePath)
+	if strings.EqualFold(absPath, valueAbs) {
+		return true, nil
+	}
+
+	// 脚本通过 cmd.exe / powershell.exe 启动，路径出现在参数中
+	return strings.Contains(strings.ToLower(value), strings.ToLower(absPath)), nil
+}
+
+// handleAddCommand 处理添加自定义命令
+func handleAddCommand() {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println("添加自定义命令到自启动")
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Print("（输入 'b' 返回主菜单）\n\n")
+
+		// 步骤1：输入注册表项名称
+		fmt.Print("请输入注册表项名称（如 TaskManager）: ")
+		appName, _ := reader.ReadString('\n')
+		appName = strings.TrimSpace(appName)
+
+		if appName == "b" || appName == "B" {
+			return
+		}
+
+		if appName == "" {
+			fmt.Println("名称不能为空。")
+			continue
+		}
+
+		// 步骤2：输入启动命令
+		fmt.Println("\n请输入完整的启动命令：")
+		fmt.Println("示例：python E:\\project\\python\\task-manager\\main.py")
+		fmt.Println("示例：E:\\app\\program.exe --arg value")
+		fmt.Print("（输入 'b' 返回上一步）\n\n命令: ")
+
+		command, _ := reader.ReadString('\n')
+		command = strings.TrimSpace(command)
+
+		if command == "b" || command == "B" {
+			continue
+		}
+
+		if command == "" {
+			fmt.Println("命令不能为空。")
+			continue
+		}
+
+		syncIfStale()
+		cache, err := loadCache()
+		if err != nil {
+			fmt.Printf("加载缓存失败: %v\n", err)
+			return
+		}
+		if !confirmDuplicates(cache, appName, command) {
+			continue
+		}
+
+		// 步骤3：记录开机启动的原因（可选）
+		reason := readInput("\n为什么需要开机启动这个程序？（可选）: ")
+
+		// 确认添加
+		if confirmWithBack("添加", appName, command) {
+			if err := AddCommandEntry(appName, command, reason); err != nil {
+				printError("添加失败 - %v", err)
+			} else {
+				fmt.Printf("已成功将命令添加到自启动！\n")
+			}
+		}
+
+		// 添加成功后返回主菜单
+		return
+	}
+}
+
+// AddCommandToStartup 添加自定义命令到Windows自启动，写入前规范化程序路径的引号
+func AddCommandToStartup(command, appName string) error {
+	command = normalizeRegistryValue(command)
+	if dryRunf("设置注册表值 %s=%s", appName, command) {
+		return nil
+	}
+
+	if err := startupRegistry.SetValue(runKeyPath, appName, command); err != nil {
+		return fmt.Errorf("设置注册表值失败: %v", err)
+	}
+	return nil
+}
+
+// IsCommandInStartup 检查注册表项是否已存在
+func IsCommandInStartup(appName string) (bool, error) {
+	_, err := startupRegistry.GetValue(runKeyPath, appName)
+	if err == nil {
+		return true, nil
+	}
+	if err == registry.ErrNotExist {
+		return false, nil
+	}
+	return false, fmt.Errorf("查询注册表失败: %v", err)
+}
+
+// handleEnable 启用禁用的启动项
+func handleEnable() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	// 筛选出禁用的项并转换为 ListItem
+	var disabledItems []ListItem
+	for _, item := range cache.Items {
+		if !item.Enabled {
+			disabledItems = append(disabledItems, ListItem{
+				Name:  item.Name,
+				Value: item.Value,
+			})
+		}
+	}
+
+	idx, ok := showListWithBack(disabledItems, "禁用的启动项列表", pageSize)
+	if !ok {
+		return
+	}
+
+	selectedItem := disabledItems[idx]
+
+	// 确认启用
+	if !confirmWithBack("启用", selectedItem.Name, selectedItem.Value) {
+		return
+	}
+
+	if err := EnableEntry(selectedItem.Name); err != nil {
+		printError("启用失败 - %v", err)
+		return
+	}
+	fmt.Printf("已成功启用 %s！\n", selectedItem.Name)
+}
+
+// handleDisable 禁用启用的启动项
+func handleDisable() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	// 筛选出启用的项并转换为 ListItem
+	var enabledItems []ListItem
+	for _, item := range cache.Items {
+		if item.Enabled {
+			enabledItems = append(enabledItems, ListItem{
+				Name:  item.Name,
+				Value: item.Value,
+			})
+		}
+	}
+
+	idx, ok := showListWithBack(enabledItems, "已启用的启动项列表", pageSize)
+	if !ok {
+		return
+	}
+
+	selectedItem := enabledItems[idx]
+
+	// 确认禁用
+	if !confirmWithBack("禁用", selectedItem.Name, selectedItem.Value) {
+		return
+	}
+
+	disabledReason := readInput("为什么要禁用这个启动项？（可选）: ")
+
+	if err := DisableEntry(selectedItem.Name, disabledReason); err != nil {
+		printError("禁用失败 - %v", err)
+		return
+	}
+	fmt.Printf("已成功禁用 %s！\n", selectedItem.Name)
+}
+
+// EnableEntry 将缓存中已禁用的启动项重新写入注册表
+func EnableEntry(name string) error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	idx, item := findItemByName(cache, name)
+	if idx < 0 {
+		if isGroupPolicyEntry(name) {
+			return ErrReadOnly
+		}
+		return fmt.Errorf("启动项不存在")
+	}
+
+	// 添加到注册表
+	undo := newUndoRecord(OpEnable, name)
+	if err := registerStartupItem(*item); err != nil {
+		return err
+	}
+
+	// 更新缓存
+	cache.Items[idx].Enabled = true
+	cache.Items[idx].LastDisabledReason = ""
+	recordHistory(&cache.Items[idx], HistoryEnabled, "", "")
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	pushUndo(undo)
+	auditEvent(EventEnable, "启用", name)
+	return nil
+}
+
+// DisableEntry 从注册表中移除启动项，缓存中保留为禁用状态，reason 为禁用原因（可为空）
+func DisableEntry(name, reason string) error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	// 从注册表删除，RunOnce 项从所在范围的 RunOnce 键中删除
+	undo := newUndoRecord(OpDisable, name)
+	if idx, item := findItemByName(cache, name); idx >= 0 && item.RunOnce {
+		if err := RemoveFromRunOnce(name, item.Scope); err != nil {
+			return err
+		}
+	} else if idx >= 0 && item.Scope == ScopeMachineWide {
+		if err := removeFromRunKey(name, item.Scope); err != nil {
+			return err
+		}
+	} else if err := RemoveFromStartup(name); err != nil {
+		return err
+	}
+
+	// 更新缓存，不在缓存中的项按注册表中的值加入
+	value := ""
+	if undo.Before != nil {
+		value = undo.Before.Value
+	}
+	if idx, item := findItemByName(cache, name); idx >= 0 {
+		value = item.Value
+	}
+	item := addOrUpdateItem(cache, name, value, false)
+	item.LastDisabledReason = reason
+	recordHistory(item, HistoryDisabled, "", reason)
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	pushUndo(undo)
+	auditEvent(EventDisable, "禁用", name)
+	return nil
+}
+
+// handleBatchEnable 一次启用多个已禁用的启动项
+func handleBatchEnable() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	var disabledItems []ListItem
+	for _, item := range cache.Items {
+		if !item.Enabled {
+			disabledItems = append(disabledItems, ListItem{Name: item.Name, Value: item.Value})
+		}
+	}
+
+	indices, ok := showMultiSelectWithBack(disabledItems, "禁用的启动项列表", pageSize)
+	if !ok {
+		return
+	}
+
+	enabled, failed := 0, 0
+	for _, idx := range indices {
+		name := disabledItems[idx].Name
+		if err := EnableEntry(name); err != nil {
+			fmt.Printf("启用 %s 失败: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("已启用 %s\n", name)
+		enabled++
+	}
+	fmt.Printf("\n已启用 %d 项，失败 %d 项。\n", enabled, failed)
+}
+
+// handleBatchDisable 一次禁用多个已启用的启动项
+func handleBatchDisable() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	var enabledItems []ListItem
+	for _, item := range cache.Items {
+		if item.Enabled {
+			enabledItems = append(enabledItems, ListItem{Name: item.Name, Value: item.Value})
+		}
+	}
+
+	indices, ok := showMultiSelectWithBack(enabledItems, "已启用的启动项列表", pageSize)
+	if !ok {
+		return
+	}
+
+	disabledReason := readInput("为什么要禁用这些启动项？（可选）: ")
+
+	disabled, failed := 0, 0
+	for _, idx := range indices {
+		name := enabledItems[idx].Name
+		if err := DisableEntry(name, disabledReason); err != nil {
+			fmt.Printf("禁用 %s 失败: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("已禁用 %s\n", name)
+		disabled++
+	}
+	fmt.Printf("\n已禁用 %d 项，失败 %d 项。\n", disabled, failed)
+}
+
+// ========== 失效启动项 ==========
+
+// resolveExecutable 解析可执行文件的实际路径
+// 不是绝对路径的命令（如 python）按 PATH 查找
+func resolveExecutable(exePath string) (string, error) {
+	if !filepath.IsAbs(exePath) {
+		return exec.LookPath(exePath)
+	}
+	if _, err := os.Stat(exePath); err != nil {
+		return "", err
+	}
+	return exePath, nil
+}
+
+// isOrphaned 检查启动项指向的程序文件是否已不存在
+func isOrphaned(item CacheItem) bool {
+	exePath, _, err := parseCommandPath(item.Value)
+	if err != nil {
+		return false
+	}
+
+	_, err = resolveExecutable(exePath)
+	return err != nil
+}
+
+// FindOrphanedEntries 查找程序文件已不存在的启动项
+func FindOrphanedEntries() ([]CacheItem, error) {
+	cache, err := loadCache()
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []CacheItem
+	for _, item := range cache.Items {
+		if isOrphaned(item) {
+			orphans = append(orphans, item)
+		}
+	}
+	recordOrphanCount(len(orphans))
+	return orphans, nil
+}
+
+// handleCleanOrphans 处理清理失效的启动项
+func handleCleanOrphans() {
+	for {
+		orphans, err := FindOrphanedEntries()
+		if err != nil {
+			fmt.Printf("加载缓存失败: %v\n", err)
+			return
+		}
+
+		if len(orphans) == 0 {
+			fmt.Println("没有发现失效的启动项。")
+			return
+		}
+
+		items := make([]ListItem, 0, len(orphans))
+		for _, item := range orphans {
+			items = append(items, ListItem{
+				Name:  item.Name,
+				Value: item.Value,
+			})
+		}
+
+		idx, ok := showListWithBack(items, "失效的启动项列表（程序文件不存在）", pageSize)
+		if !ok {
+			return
+		}
+
+		selectedItem := orphans[idx]
+
+		// 确认移除
+		if !confirmWithBack("移除失效的启动项", selectedItem.Name, selectedItem.Value) {
+			continue
+		}
+
+		if err := removeOrphanedEntry(selectedItem); err != nil {
+			printError("移除失败 - %v", err)
+			continue
+		}
+
+		fmt.Printf("已成功移除失效的启动项 %s！\n", selectedItem.Name)
+	}
+}
+
+// removeOrphanedEntry 移除一个失效的启动项，已启用的项同时从注册表删除
+func removeOrphanedEntry(item CacheItem) error {
+	return RemoveEntry(item.Name)
+}
+
+// orphanReport --check-orphans 和 --clean-orphans 以 JSON 输出的结果
+type orphanReport struct {
+	Count   int           `json:"count"`
+	Orphans []orphanEntry `json:"orphans"`
+	Removed []string      `json:"removed,omitempty"`
+	Failed  []orphanEntry `json:"failed,omitempty"`
+}
+
+type orphanEntry struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Enabled bool   `json:"enabled"`
+	Error   string `json:"error,omitempty"`
+}
+
+// runCheckOrphans 检查失效的启动项并输出摘要，clean 为 true 时全部移除
+// 移除前逐项确认，force 为 true 时跳过确认；outputFormat 为 json 时输出 JSON，ndjson 时输出为一行
+func runCheckOrphans(clean, force bool, outputFormat string) error {
+	jsonOutput := outputFormat == "json" || outputFormat == "ndjson"
+	if clean && jsonOutput && !force {
+		return fmt.Errorf("--output=%s 时无法逐项确认，请同时指定 --force", outputFormat)
+	}
+
+	orphans, err := FindOrphanedEntries()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	report := orphanReport{Count: len(orphans), Orphans: []orphanEntry{}}
+	names := make([]string, 0, len(orphans))
+	for _, item := range orphans {
+		report.Orphans = append(report.Orphans, orphanEntry{Name: item.Name, Value: item.Value, Enabled: item.Enabled})
+		names = append(names, item.Name)
+	}
+
+	if !jsonOutput {
+		if len(orphans) == 0 {
+			fmt.Println("没有发现失效的启动项。")
+			return nil
+		}
+		fmt.Printf("发现 %d 个失效的启动项: %s。", len(orphans), strings.Join(names, ", "))
+		if !clean {
+			fmt.Print("运行 'autostart --clean-orphans' 可以移除它们。")
+		}
+		fmt.Println()
+	}
+
+	if clean {
+		for _, item := range orphans {
+			if !force && !confirmYes(fmt.Sprintf("移除 %s（%s）？(y/n): ", item.Name, item.Value)) {
+				continue
+			}
+			if err := removeOrphanedEntry(item); err != nil {
+				report.Failed = append(report.Failed, orphanEntry{Name: item.Name, Value: item.Value, Enabled: item.Enabled, Error: err.Error()})
+				if !jsonOutput {
+					printError("移除 %s 失败 - %v", item.Name, err)
+				}
+				continue
+			}
+			report.Removed = append(report.Removed, item.Name)
+			if !jsonOutput {
+				fmt.Printf("已移除 %s\n", item.Name)
+			}
+		}
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		if outputFormat == "json" {
+			encoder.SetIndent("", "  ")
+		}
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	}
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("%d 个启动项移除失败", len(report.Failed))
+	}
+	return nil
+}
+
+// ========== 启动项校验 ==========
+
+// Severity 校验问题的严重程度
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// ValidationResult 单个启动项的校验问题
+type ValidationResult struct {
+	Name     string
+	Issue    string
+	Severity Severity
+
+	mismatch *HashMismatch // 文件哈希不一致时非空
+}
+
+// 常见的脚本解释器，启动命令以它们开头时视为有效
+var knownInterpreters = map[string]bool{
+	"cmd":        true,
+	"powershell": true,
+	"pwsh":       true,
+	"wscript":    true,
+	"cscript":    true,
+	"python":     true,
+	"pythonw":    true,
+	"node":       true,
+	"java":       true,
+	"javaw":      true,
+	"rundll32":   true,
+}
+
+// envVarPattern 匹配 Windows 风格的 %VAR% 环境变量
+var envVarPattern = regexp.MustCompile(`%([^%\s]+)%`)
+
+// validateItem 校验单个启动项，返回发现的所有问题
+func validateItem(item CacheItem) []ValidationResult {
+	var results []ValidationResult
+	addIssue := func(severity Severity, format string, args ...interface{}) {
+		results = append(results, ValidationResult{
+			Name:     item.Name,
+			Issue:    fmt.Sprintf(format, args...),
+			Severity: severity,
+		})
+	}
+
+	if strings.TrimSpace(item.Value) == "" {
+		addIssue(SeverityError, "启动命令为空")
+		return results
+	}
+
+	// 检查无法解析的环境变量
+	unresolved := false
+	for _, match := range envVarPattern.FindAllStringSubmatch(item.Value, -1) {
+		if _, ok := os.LookupEnv(match[1]); !ok {
+			addIssue(SeverityWarning, "包含无法解析的环境变量 %s", match[0])
+			unresolved = true
+		}
+	}
+
+	exePath, _, err := parseCommandPath(item.Value)
+	if err != nil {
+		addIssue(SeverityError, "命令格式错误: %v", err)
+		return results
+	}
+
+	// 含有无法解析的环境变量时无法判断文件是否存在
+	if unresolved {
+		return results
+	}
+
+	resolved, err := resolveExecutable(exePath)
+	if err != nil {
+		addIssue(SeverityError, "程序文件不存在: %s", exePath)
+		return results
+	}
+
+	base := strings.ToLower(filepath.Base(resolved))
+	ext := filepath.Ext(base)
+	if ext != ".exe" && !knownInterpreters[strings.TrimSuffix(base, ext)] {
+		addIssue(SeverityWarning, "不是可执行文件: %s", resolved)
+	}
+
+	return results
+}
+
+// ValidateStartupEntries 校验所有启动项，返回发现的问题
+func ValidateStartupEntries() ([]ValidationResult, error) {
+	cache, err := loadCache()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ValidationResult
+	for _, item := range cache.Items {
+		results = append(results, validateItem(item)...)
+	}
+
+	mismatches, err := VerifyEntryHashes()
+	if err != nil {
+		return nil, err
+	}
+	for i := range mismatches {
+		m := &mismatches[i]
+		issue := "文件哈希与记录不一致，程序可能已被替换"
+		if m.CurrentHash == "" {
+			issue = "无法读取文件以校验哈希"
+		}
+		results = append(results, ValidationResult{
+			Name:     m.Name,
+			Issue:    issue,
+			Severity: SeverityError,
+			mismatch: m,
+		})
+	}
+	return results, nil
+}
+
+// handleValidateEntries 处理校验所有启动项
+func handleValidateEntries() {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		results, err := ValidateStartupEntries()
+		if err != nil {
+			fmt.Printf("加载缓存失败: %v\n", err)
+			return
+		}
+
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println("启动项校验结果")
+		fmt.Println(strings.Repeat("=", 60))
+
+		if len(results) == 0 {
+			fmt.Println("所有启动项均通过校验。")
+			return
+		}
+
+		fmt.Printf("%-4s %-6s %-24s %s\n", "序号", "级别", "名称", "问题")
+		fmt.Println(strings.Repeat("-", 60))
+		for i, result := range results {
+			level := "警告"
+			if result.Severity == SeverityError {
+				level = "错误"
+			}
+			fmt.Printf("%-4d %-6s %-24s %s\n", i+1, level, result.Name, result.Issue)
+		}
+
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Print("请输入序号处理对应的问题（或输入 'b' 返回）: ")
+
+		choice, _ := reader.ReadString('\n')
+		choice = strings.TrimSpace(choice)
+
+		if choice == "b" || choice == "B" {
+			return
+		}
+
+		num, err := strconv.Atoi(choice)
+		if err != nil || num < 1 || num > len(results) {
+			fmt.Println("无效的编号。")
+			continue
+		}
+
+		fixValidationIssue(results[num-1])
+	}
+}
+
+// fixValidationIssue 针对校验问题提供处理方式
+func fixValidationIssue(result ValidationResult) {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
 		return
 	}
 
-	// 排序显示
-	sort.Slice(cache.Items, func(i, j int) bool {
-		return cache.Items[i].Name < cache.Items[j].Name
-	})
+	idx, _ := findItemByName(cache, result.Name)
+	if idx < 0 {
+		fmt.Println("启动项已不存在。")
+		return
+	}
+	item := cache.Items[idx]
+
+	fmt.Printf("\n名称: %s\n", item.Name)
+	fmt.Printf("内容: %s\n", item.Value)
+	fmt.Printf("问题: %s\n", result.Issue)
+	if m := result.mismatch; m != nil {
+		fmt.Printf("文件: %s\n", m.Path)
+		fmt.Printf("记录的哈希: %s\n", m.StoredHash)
+		fmt.Printf("当前的哈希: %s\n", m.CurrentHash)
+		fmt.Println("警告: 如果不是您主动更新了该程序，请谨慎处理。")
+	}
+	fmt.Println("1. 移除该启动项")
+	if item.Enabled {
+		fmt.Println("2. 禁用该启动项")
+	}
+	if result.mismatch != nil && result.mismatch.CurrentHash != "" {
+		fmt.Println("3. 更新记录的文件哈希")
+	}
+	fmt.Print("请选择处理方式（或输入 'b' 返回）: ")
 
-	for i, item := range cache.Items {
-		status := "[启用]"
-		if !item.Enabled {
-			status = "[禁用]"
+	reader := bufio.NewReader(os.Stdin)
+	choice, _ := reader.ReadString('\n')
+	choice = strings.TrimSpace(choice)
+
+	switch {
+	case choice == "1":
+		if !confirmWithBack("移除", item.Name, item.Value) {
+			return
 		}
-		fmt.Printf("%d. %s %s\n   %s\n\n", i+1, item.Name, status, item.Value)
+		if err := RemoveEntry(item.Name); err != nil {
+			printError("移除失败 - %v", err)
+			return
+		}
+		fmt.Printf("已成功移除 %s！\n", item.Name)
+	case choice == "2" && item.Enabled:
+		if !confirmWithBack("禁用", item.Name, item.Value) {
+			return
+		}
+		if err := DisableEntry(item.Name, result.Issue); err != nil {
+			printError("禁用失败 - %v", err)
+			return
+		}
+		fmt.Printf("已成功禁用 %s！\n", item.Name)
+	case choice == "3" && result.mismatch != nil && result.mismatch.CurrentHash != "":
+		if err := updateEntryHash(item.Name); err != nil {
+			printError("更新哈希失败 - %v", err)
+			return
+		}
+		fmt.Printf("已更新 %s 的文件哈希！\n", item.Name)
 	}
 }
 
-// selectExeFile 选择exe文件
-func selectExeFile() string {
-	currentDir, _ := os.Getwd()
+// ========== 清除已禁用的启动项 ==========
+
+// ClearAllDisabledEntries 从缓存中删除所有已禁用的启动项
+// 这些项已不在注册表中，因此只修改缓存
+func ClearAllDisabledEntries() error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	kept := cache.Items[:0]
+	for _, item := range cache.Items {
+		if item.Enabled {
+			kept = append(kept, item)
+		}
+	}
+	cache.Items = kept
+
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	markOrphansStale()
+	return nil
+}
+
+// PurgeEntry 彻底删除启动项，无论是否启用
+// 启用的项同时从注册表中移除，并删除为其生成的辅助文件
+func PurgeEntry(name string) error {
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	idx, _ := findItemByName(cache, name)
+	exists, err := IsCommandInStartup(name)
+	if err != nil {
+		return err
+	}
+	if idx < 0 && !exists {
+		return fmt.Errorf("启动项不存在")
+	}
 
+	if exists {
+		if err := RemoveFromStartup(name); err != nil {
+			return err
+		}
+	}
+	if err := removeStartWrappers(name); err != nil {
+		return err
+	}
+	if err := removeWindowStateWrapper(name); err != nil {
+		return err
+	}
+
+	removeItem(cache, name)
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	markOrphansStale()
+	return nil
+}
+
+// handlePurge 清除已禁用的启动项子菜单
+func handlePurge() {
 	for {
 		fmt.Println("\n" + strings.Repeat("=", 60))
-		fmt.Println("选择程序文件")
+		fmt.Println("清除已禁用的启动项")
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Println("1. 清除所有已禁用的启动项")
+		fmt.Println("2. 彻底删除指定的启动项")
 		fmt.Println(strings.Repeat("=", 60))
-		fmt.Printf("当前目录: %s\n", currentDir)
-		fmt.Print("请输入文件路径（或输入 'b' 返回，输入 'd' 浏览当前目录，输入 'g' 跳转到指定目录）: ")
 
-		reader := bufio.NewReader(os.Stdin)
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
+		choice := readInput("请选择操作（或输入 'b' 返回）: ")
 
-		if input == "b" || input == "B" {
-			return ""
+		switch choice {
+		case "1":
+			handleClearDisabled()
+		case "2":
+			handlePurgeEntry()
+		case "b", "B":
+			return
+		default:
+			fmt.Println("无效的选择，请重新输入。")
 		}
+	}
+}
 
-		if input == "d" || input == "D" {
-			// 浏览当前目录
-			selected := browseDirectory(currentDir)
-			if selected != "" {
-				return selected
-			}
-			continue
+// handleClearDisabled 处理清除所有已禁用的启动项
+func handleClearDisabled() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	var names []string
+	for _, item := range cache.Items {
+		if !item.Enabled {
+			names = append(names, item.Name)
 		}
+	}
+	if len(names) == 0 {
+		fmt.Println("没有已禁用的启动项。")
+		return
+	}
 
-		if input == "g" || input == "G" {
-			// 跳转到指定目录
-			fmt.Print("请输入要跳转的目录路径: ")
-			newPath, _ := reader.ReadString('\n')
-			newPath = strings.TrimSpace(newPath)
+	if !confirmWithBack("清除以下已禁用的启动项", fmt.Sprintf("共 %d 项", len(names)), strings.Join(names, ", ")) {
+		return
+	}
 
-			if newPath == "" {
-				continue
-			}
+	if err := ClearAllDisabledEntries(); err != nil {
+		printError("清除失败 - %v", err)
+		return
+	}
+	fmt.Printf("已成功清除 %d 个已禁用的启动项！\n", len(names))
+}
 
-			// 处理路径
-			var targetDir string
-			if filepath.IsAbs(newPath) {
-				targetDir = newPath
-			} else {
-				// 相对路径，相对于当前目录
-				targetDir = filepath.Join(currentDir, newPath)
-			}
+// handlePurgeEntry 处理彻底删除指定的启动项
+func handlePurgeEntry() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
 
-			// 检查是否是有效目录
-			info, err := os.Stat(targetDir)
-			if err != nil {
-				fmt.Printf("目录不存在或无法访问: %v\n", err)
-				fmt.Print("按回车键继续...")
-				reader.ReadString('\n')
-				continue
-			}
+	items := make([]ListItem, 0, len(cache.Items))
+	for _, item := range cache.Items {
+		status := "[启用]"
+		if !item.Enabled {
+			status = "[禁用]"
+		}
+		items = append(items, ListItem{Name: item.Name + " " + status, Value: item.Value})
+	}
 
-			if !info.IsDir() {
-				fmt.Printf("路径不是目录: %s\n", targetDir)
-				fmt.Print("按回车键继续...")
-				reader.ReadString('\n')
-				continue
-			}
+	idx, ok := showListWithBack(items, "所有启动项", pageSize)
+	if !ok {
+		return
+	}
 
-			// 更新当前目录
-			absTarget, _ := filepath.Abs(targetDir)
-			currentDir = absTarget
-			continue
-		}
+	selected := cache.Items[idx]
+	fmt.Println("\n彻底删除后，该启动项的所有记录（包括原因、备注等）都将被清除，且无法撤销。")
+	if !confirmWithBack("彻底删除", selected.Name, selected.Value) {
+		return
+	}
 
-		// 检查是否是完整路径
-		if filepath.IsAbs(input) {
-			if isValidExeFile(input) {
-				return input
-			}
-			fmt.Printf("文件不存在或不是有效的exe文件: %s\n", input)
-			continue
+	if err := PurgeEntry(selected.Name); err != nil {
+		printError("删除失败 - %v", err)
+		return
+	}
+	fmt.Printf("已彻底删除 %s！\n", selected.Name)
+}
+
+// ========== 重命名 ==========
+
+// RenameStartupEntry 重命名启动项
+// 注册表中依次写入新名称、删除旧名称，任一步失败都会撤销已完成的步骤；
+// 缓存中直接修改原有项的名称，保留其他字段
+func RenameStartupEntry(oldName, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("新名称不能为空")
+	}
+	if oldName == newName {
+		return nil
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	if idx, _ := findItemByName(cache, newName); idx >= 0 {
+		return fmt.Errorf("名称 %s 已存在", newName)
+	}
+
+	if _, err := startupRegistry.GetValue(runKeyPath, newName); err == nil {
+		return fmt.Errorf("注册表中已存在名称 %s", newName)
+	}
+
+	// 撤销时删除新名称并恢复旧名称的快照
+	undo := UndoRecord{
+		OperationType: OpRename,
+		Name:          newName,
+		Before:        snapshotItem(oldName),
+		Timestamp:     time.Now(),
+	}
+
+	// 已禁用的项只存在于缓存中；不在缓存中的项按注册表中的值改名
+	idx, cached := findItemByName(cache, oldName)
+	value, err := startupRegistry.GetValue(runKeyPath, oldName)
+	switch {
+	case err == registry.ErrNotExist && idx < 0:
+		return fmt.Errorf("启动项不存在")
+	case err != nil && err != registry.ErrNotExist:
+		return fmt.Errorf("查询注册表值失败: %v", err)
+	}
+	inRegistry := err == nil
+	if dryRunf("将启动项 %s 重命名为 %s", oldName, newName) {
+		return nil
+	}
+
+	// 辅助文件以名称命名，需要按新名称重新生成
+	renamed := CacheItem{Name: oldName, Value: value, Enabled: true}
+	if cached != nil {
+		renamed = *cached
+	}
+	if err := moveWindowStateWrapper(&renamed, newName); err != nil {
+		return err
+	}
+	renamed.Name = newName
+
+	if inRegistry {
+		if err := registerStartupItem(renamed); err != nil {
+			return fmt.Errorf("写入新名称失败: %v", err)
+		}
+		if err := startupRegistry.DeleteValue(runKeyPath, oldName); err != nil {
+			// 撤销写入的新名称，保持注册表原状
+			startupRegistry.DeleteValue(runKeyPath, newName)
+			removeStartWrappers(newName)
+			return fmt.Errorf("删除旧名称失败: %v", err)
+		}
+	}
+	if err := removeStartWrappers(oldName); err != nil {
+		return err
+	}
+	if renamed.WindowState == windowStateHidden {
+		if err := removeWindowStateWrapper(oldName); err != nil {
+			return err
 		}
+	}
+
+	if idx >= 0 {
+		cache.Items[idx] = renamed
+		recordHistory(&cache.Items[idx], HistoryRenamed, oldName, newName)
+	} else {
+		item := addOrUpdateItem(cache, newName, renamed.Value, true)
+		recordHistory(item, HistoryRenamed, oldName, newName)
+	}
+
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	pushUndo(undo)
+	return nil
+}
+
+// handleRename 处理重命名启动项
+func handleRename() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	items := make([]ListItem, 0, len(cache.Items))
+	for _, item := range cache.Items {
+		items = append(items, ListItem{
+			Name:  item.Name,
+			Value: item.Value,
+		})
+	}
+
+	idx, ok := showListWithBack(items, "选择要重命名的启动项", pageSize)
+	if !ok {
+		return
+	}
+
+	selectedItem := items[idx]
+	newName := readInput(fmt.Sprintf("请输入新名称（当前: %s）: ", selectedItem.Name))
+	if newName == "" {
+		fmt.Println("名称不能为空。")
+		return
+	}
+
+	if !confirmWithBack("重命名为 "+newName, selectedItem.Name, selectedItem.Value) {
+		return
+	}
+
+	if err := RenameStartupEntry(selectedItem.Name, newName); err != nil {
+		printError("重命名失败 - %v", err)
+		return
+	}
+	fmt.Printf("已成功将 %s 重命名为 %s！\n", selectedItem.Name, newName)
+}
+
+// CloneEntry 以新名称复制启动项
+// 复制的启动项处于禁用状态，只保存在缓存中，修改命令后再启用；创建时间和历史记录不复制
+func CloneEntry(srcName, destName string) error {
+	if destName == "" {
+		return fmt.Errorf("新名称不能为空")
+	}
+
+	src := snapshotItem(srcName)
+	if src == nil {
+		return fmt.Errorf("启动项不存在")
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	if idx, _ := findItemByName(cache, destName); idx >= 0 {
+		return fmt.Errorf("名称 %s 已存在", destName)
+	}
+	if exists, _ := IsCommandInStartup(destName); exists {
+		return fmt.Errorf("注册表中已存在名称 %s", destName)
+	}
+
+	undo := newUndoRecord(OpAdd, destName)
+
+	clone := *src
+	clone.Name = destName
+	clone.Enabled = false
+	clone.Tags = append([]string(nil), src.Tags...)
+	clone.CreatedAt = time.Now()
+	clone.UpdatedAt = time.Time{}
+	clone.History = nil
+	recordHistory(&clone, HistoryAdded, "", clone.Value)
+	cache.Items = append(cache.Items, clone)
+
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	pushUndo(undo)
+	return nil
+}
+
+// handleClone 处理复制启动项
+func handleClone() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
 
-		// 尝试作为相对路径
-		absPath, err := filepath.Abs(input)
-		if err == nil && isValidExeFile(absPath) {
-			return absPath
-		}
+	items := make([]ListItem, 0, len(cache.Items))
+	for _, item := range cache.Items {
+		items = append(items, ListItem{
+			Name:  item.Name,
+			Value: item.Value,
+		})
+	}
 
-		fmt.Printf("文件不存在或不是有效的exe文件: %s\n", input)
+	idx, ok := showListWithBack(items, "选择要复制的启动项", pageSize)
+	if !ok {
+		return
 	}
-}
 
-// browseDirectory 浏览目录（只一层）
-func browseDirectory(dirPath string) string {
-	absDir, err := filepath.Abs(dirPath)
-	if err != nil {
-		fmt.Printf("无法获取目录路径: %v\n", err)
-		return ""
+	selectedItem := items[idx]
+	newName := readInput("请输入新名称: ")
+	if newName == "" {
+		fmt.Println("名称不能为空。")
+		return
 	}
 
-	// 读取目录内容
-	entries, err := os.ReadDir(absDir)
-	if err != nil {
-		fmt.Printf("无法读取目录: %v\n", err)
-		return ""
+	if err := CloneEntry(selectedItem.Name, newName); err != nil {
+		printError("复制失败 - %v", err)
+		return
 	}
+	fmt.Printf("已将 %s 复制为 %s（已禁用，可编辑命令后再启用）\n", selectedItem.Name, newName)
+}
 
-	// 过滤出exe文件和目录
-	var exeFiles []string
-	var dirs []string
+// ========== 编辑 ==========
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			dirs = append(dirs, entry.Name())
-		} else if strings.ToLower(filepath.Ext(entry.Name())) == ".exe" {
-			exeFiles = append(exeFiles, entry.Name())
-		}
+// UpdateStartupEntry 修改启动项的启动命令
+// 原来的命令保存在 LastValue 中；已禁用的项只修改缓存，不写入注册表
+func UpdateStartupEntry(name, newValue string) error {
+	if strings.TrimSpace(newValue) == "" {
+		return fmt.Errorf("命令不能为空")
 	}
 
-	// 排序
-	sort.Strings(dirs)
-	sort.Strings(exeFiles)
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
 
-	// 显示文件列表
-	fmt.Println("\n" + strings.Repeat("=", 60))
-	fmt.Printf("当前目录: %s\n", absDir)
-	fmt.Println(strings.Repeat("=", 60))
+	idx, _ := findItemByName(cache, name)
+	if idx < 0 {
+		return fmt.Errorf("启动项不存在")
+	}
+	item := &cache.Items[idx]
+	undo := newUndoRecord(OpEdit, name)
+
+	if item.Enabled {
+		updated := *item
+		updated.Value = newValue
+		if err := registerStartupItem(updated); err != nil {
+			return err
+		}
+	}
 
-	index := 1
-	fileMap := make(map[int]string)
+	_, args, _ := parseCommandPath(newValue)
+	recordHistory(item, HistoryEdited, item.Value, newValue)
+	item.LastValue = item.Value
+	item.Value = newValue
+	item.Arguments = args
+	item.UpdatedAt = time.Now()
+	refreshFileModTime(item)
 
-	// 显示目录
-	if len(dirs) > 0 {
-		fmt.Println("\n目录:")
-		for _, dir := range dirs {
-			fmt.Printf("  [%d] %s/ (目录)\n", index, dir)
-			fileMap[index] = filepath.Join(absDir, dir)
-			index++
-		}
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
 	}
+	pushUndo(undo)
+	return nil
+}
 
-	// 显示exe文件
-	if len(exeFiles) > 0 {
-		fmt.Println("\n可执行文件:")
-		for _, file := range exeFiles {
-			fullPath := filepath.Join(absDir, file)
-			fmt.Printf("  [%d] %s\n", index, file)
-			fileMap[index] = fullPath
-			index++
-		}
+// handleEditEntry 处理编辑启动项
+func handleEditEntry() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
 	}
 
-	if len(fileMap) == 0 {
-		fmt.Println("当前目录没有找到exe文件或子目录。")
-		return ""
+	items := make([]ListItem, 0, len(cache.Items))
+	for _, item := range cache.Items {
+		items = append(items, ListItem{
+			Name:  item.Name,
+			Value: item.Value,
+		})
 	}
 
-	// 让用户选择
-	fmt.Println(strings.Repeat("=", 60))
-	fmt.Print("请输入序号选择文件（或输入 'b' 返回，输入 'u' 返回上一级，输入 'g' 跳转到指定目录）: ")
+	idx, ok := showListWithBack(items, "选择要编辑的启动项", pageSize)
+	if !ok {
+		return
+	}
 
-	reader := bufio.NewReader(os.Stdin)
-	choice, _ := reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
+	selectedItem := items[idx]
+	newValue := readInput(fmt.Sprintf("请输入新的启动命令（当前: %s）: ", selectedItem.Value))
+	if newValue == "" {
+		fmt.Println("命令不能为空。")
+		return
+	}
 
-	if choice == "b" || choice == "B" {
-		return ""
+	// 确保命令中的程序存在
+	exePath, _, err := parseCommandPath(newValue)
+	if err != nil {
+		fmt.Printf("命令格式错误: %v\n", err)
+		return
+	}
+	if _, err := resolveExecutable(exePath); err != nil {
+		fmt.Printf("程序文件不存在: %s\n", exePath)
+		return
 	}
 
-	if choice == "u" || choice == "U" {
-		parentDir := filepath.Dir(absDir)
-		if parentDir != absDir {
-			return browseDirectory(parentDir)
-		}
-		return browseDirectory(absDir)
+	if !confirmWithBack("修改启动命令", selectedItem.Name, newValue) {
+		return
 	}
 
-	if choice == "g" || choice == "G" {
-		// 跳转到指定目录
-		fmt.Print("请输入要跳转的目录路径: ")
-		newPath, _ := reader.ReadString('\n')
-		newPath = strings.TrimSpace(newPath)
+	if err := UpdateStartupEntry(selectedItem.Name, newValue); err != nil {
+		printError("修改失败 - %v", err)
+		return
+	}
+	fmt.Printf("已成功修改 %s 的启动命令！\n", selectedItem.Name)
+}
 
-		if newPath == "" {
-			return browseDirectory(absDir)
-		}
+// ========== 启动文件夹 ==========
 
-		// 处理路径
-		var targetDir string
-		if filepath.IsAbs(newPath) {
-			targetDir = newPath
-		} else {
-			// 相对路径，相对于当前目录
-			targetDir = filepath.Join(absDir, newPath)
-		}
+// handleStartupFolder 启动文件夹子菜单
+func handleStartupFolder() {
+	for {
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println("管理启动文件夹")
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Println("1. 查看启动文件夹中的程序")
+		fmt.Println("2. 添加程序到启动文件夹")
+		fmt.Println("3. 从启动文件夹移除程序")
+		fmt.Println(strings.Repeat("=", 60))
 
-		// 检查是否是有效目录
-		info, err := os.Stat(targetDir)
-		if err != nil {
-			fmt.Printf("目录不存在或无法访问: %v\n", err)
-			fmt.Print("按回车键继续...")
-			reader.ReadString('\n')
-			return browseDirectory(absDir)
-		}
+		choice := readInput("请选择操作（或输入 'b' 返回）: ")
 
-		if !info.IsDir() {
-			fmt.Printf("路径不是目录: %s\n", targetDir)
-			fmt.Print("按回车键继续...")
-			reader.ReadString('\n')
-			return browseDirectory(absDir)
+		switch choice {
+		case "1":
+			showStartupFolderEntries()
+		case "2":
+			handleAddToStartupFolder()
+		case "3":
+			handleRemoveFromStartupFolder()
+		case "b", "B":
+			return
+		default:
+			fmt.Println("无效的选择，请重新输入。")
 		}
-
-		// 跳转到新目录
-		return browseDirectory(targetDir)
 	}
+}
 
-	// 解析序号
-	num, err := strconv.Atoi(choice)
+// startupFolderListItems 将启动文件夹中的快捷方式转换为 ListItem
+func startupFolderListItems() ([]StartupFolderEntry, []ListItem, error) {
+	entries, err := ListStartupFolderEntries()
 	if err != nil {
-		fmt.Println("无效的序号。")
-		return ""
+		return nil, nil, err
 	}
 
-	selectedPath, exists := fileMap[num]
-	if !exists {
-		fmt.Println("无效的序号。")
-		return ""
+	items := make([]ListItem, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, ListItem{
+			Name:  entry.Name,
+			Value: entry.commandLine(),
+		})
 	}
+	return entries, items, nil
+}
 
-	// 检查是否是目录
-	info, err := os.Stat(selectedPath)
+// showStartupFolderEntries 显示启动文件夹中的快捷方式
+func showStartupFolderEntries() {
+	_, items, err := startupFolderListItems()
 	if err != nil {
-		fmt.Printf("无法访问: %v\n", err)
-		return ""
+		fmt.Printf("读取启动文件夹失败: %v\n", err)
+		return
 	}
 
-	if info.IsDir() {
-		// 进入子目录
-		return browseDirectory(selectedPath)
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("启动文件夹中的程序：")
+	fmt.Println(strings.Repeat("=", 60))
+
+	if len(items) == 0 {
+		fmt.Println("启动文件夹中没有快捷方式。")
+		return
 	}
 
-	// 返回选中的exe文件
-	return selectedPath
+	for i, item := range items {
+		fmt.Printf("%d. %s\n   %s\n\n", i+1, item.Name, item.Value)
+	}
 }
 
-// isValidExeFile 检查是否是有效的exe文件
-func isValidExeFile(path string) bool {
-	info, err := os.Stat(path)
-	if err != nil {
-		return false
+// handleAddToStartupFolder 处理添加程序到启动文件夹
+func handleAddToStartupFolder() {
+	exePath := selectExeFile()
+	if exePath == "" {
+		return // 用户取消了选择
 	}
-	if info.IsDir() {
-		return false
+
+	linkName := readInput(fmt.Sprintf("请输入快捷方式名称（默认 %s）: ", getAppName(exePath)))
+	if linkName == "" {
+		linkName = getAppName(exePath)
 	}
-	return strings.ToLower(filepath.Ext(path)) == ".exe"
-}
 
-// getAppName 从文件路径获取程序名称（不含扩展名）
-func getAppName(exePath string) string {
-	base := filepath.Base(exePath)
-	return strings.TrimSuffix(base, filepath.Ext(base))
-}
+	args := readInput("请输入启动参数（可选）: ")
+	workDir := readInput("请输入工作目录（默认为程序所在目录）: ")
 
-// AddToStartup 添加程序到Windows自启动
-func AddToStartup(exePath, appName string) error {
-	// 获取可执行文件的绝对路径
-	absPath, err := filepath.Abs(exePath)
-	if err != nil {
-		return fmt.Errorf("获取绝对路径失败: %v", err)
+	if !confirmWithBack("添加到启动文件夹", linkName, buildCommand(exePath, args)) {
+		return
 	}
 
-	// 检查文件是否存在
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return fmt.Errorf("文件不存在: %s", absPath)
+	if err := AddToStartupFolder(exePath, linkName, args, workDir); err != nil {
+		printError("添加失败 - %v", err)
+		return
 	}
+	fmt.Printf("已成功将 %s 添加到启动文件夹！\n", linkName)
+}
 
-	// 打开注册表键
-	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+// handleRemoveFromStartupFolder 处理从启动文件夹移除程序
+func handleRemoveFromStartupFolder() {
+	entries, items, err := startupFolderListItems()
 	if err != nil {
-		return fmt.Errorf("打开注册表失败: %v", err)
+		fmt.Printf("读取启动文件夹失败: %v\n", err)
+		return
 	}
-	defer key.Close()
 
-	// 设置注册表值（使用双引号包裹路径，防止路径中有空格）
-	value := fmt.Sprintf(`"%s"`, absPath)
-	err = key.SetStringValue(appName, value)
-	if err != nil {
-		return fmt.Errorf("设置注册表值失败: %v", err)
+	idx, ok := showListWithBack(items, "启动文件夹中的程序", pageSize)
+	if !ok {
+		return
 	}
 
-	return nil
-}
+	selectedEntry := entries[idx]
+	if !confirmWithBack("从启动文件夹移除", selectedEntry.Name, selectedEntry.commandLine()) {
+		return
+	}
 
-// RemoveFromStartup 从Windows自启动中移除程序
-func RemoveFromStartup(appName string) error {
-	// 打开注册表键
-	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
-	if err != nil {
-		return fmt.Errorf("打开注册表失败: %v", err)
+	if err := RemoveFromStartupFolder(selectedEntry.Name); err != nil {
+		printError("移除失败 - %v", err)
+		return
 	}
-	defer key.Close()
+	fmt.Printf("已成功从启动文件夹移除 %s！\n", selectedEntry.Name)
+}
 
-	// 删除注册表值
-	err = key.DeleteValue(appName)
-	if err != nil {
-		if err == registry.ErrNotExist {
-			return fmt.Errorf("启动项不存在")
+// ========== 计划任务 ==========
+
+// handleScheduledTasks 计划任务子菜单
+func handleScheduledTasks() {
+	for {
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println("管理登录时运行的计划任务")
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Println("1. 查看登录时运行的计划任务")
+		fmt.Println("2. 添加程序为计划任务")
+		fmt.Println("3. 删除计划任务")
+		fmt.Println(strings.Repeat("=", 60))
+
+		choice := readInput("请选择操作（或输入 'b' 返回）: ")
+
+		switch choice {
+		case "1":
+			showLogonTasks()
+		case "2":
+			handleAddScheduledTask()
+		case "3":
+			handleRemoveScheduledTask()
+		case "b", "B":
+			return
+		default:
+			fmt.Println("无效的选择，请重新输入。")
 		}
-		return fmt.Errorf("删除注册表值失败: %v", err)
 	}
-
-	return nil
 }
 
-// ========== 公共基础函数 ==========
+// logonTaskListItems 读取登录时运行的计划任务并转换为列表项
+func logonTaskListItems() ([]ScheduledTaskEntry, []ListItem, error) {
+	tasks, err := ListLogonTasks()
+	if err != nil {
+		return nil, nil, err
+	}
 
-// ListItem 列表项结构
-type ListItem struct {
-	Name  string
-	Value string
+	items := make([]ListItem, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, ListItem{
+			Name:  task.Name,
+			Value: task.commandLine(),
+		})
+	}
+	return tasks, items, nil
 }
 
-// showListWithBack 显示列表，支持返回
-func showListWithBack(items []ListItem, title string) (int, bool) {
+// showLogonTasks 显示登录时运行的计划任务
+func showLogonTasks() {
+	tasks, err := ListLogonTasks()
+	if err != nil {
+		fmt.Printf("读取计划任务失败: %v\n", err)
+		return
+	}
+
 	fmt.Println("\n" + strings.Repeat("=", 60))
-	fmt.Println(title)
+	fmt.Println("登录时运行的计划任务：")
 	fmt.Println(strings.Repeat("=", 60))
 
-	if len(items) == 0 {
-		fmt.Println("没有可显示的项。")
-		return -1, false
+	if len(tasks) == 0 {
+		fmt.Println("没有登录时运行的计划任务。")
+		return
 	}
 
-	for i, item := range items {
-		fmt.Printf("%d. %s\n   %s\n\n", i+1, item.Name, item.Value)
+	for i, task := range tasks {
+		fmt.Printf("%d. %s ", i+1, task.Name)
+		printer.Print(statusChip(task.Enabled))
+		fmt.Printf(" [%s]\n   %s\n\n", task.RunLevel, task.commandLine())
 	}
+}
 
-	fmt.Println(strings.Repeat("=", 60))
-	fmt.Print("请输入序号（或输入 'b' 返回）: ")
-
-	reader := bufio.NewReader(os.Stdin)
-	choice, _ := reader.ReadString('\n')
-	choice = strings.TrimSpace(choice)
-
-	if choice == "b" || choice == "B" {
-		return -1, false
+// handleAddScheduledTask 处理添加程序为计划任务
+func handleAddScheduledTask() {
+	exePath := selectExeFile()
+	if exePath == "" {
+		return // 用户取消了选择
 	}
 
-	num, err := strconv.Atoi(choice)
-	if err != nil || num < 1 || num > len(items) {
-		fmt.Println("无效的编号。")
-		return -1, false
+	taskName := readInput(fmt.Sprintf("请输入任务名称（默认 %s）: ", getAppName(exePath)))
+	if taskName == "" {
+		taskName = getAppName(exePath)
 	}
 
-	return num - 1, true
-}
+	args := readInput("请输入启动参数（可选）: ")
 
-// confirmWithBack 确认操作，支持返回
-// 返回：true=确认，false=取消或返回
-func confirmWithBack(title, name, value string) bool {
-	fmt.Printf("\n确定要%s吗？\n", title)
-	fmt.Printf("名称: %s\n", name)
-	fmt.Printf("内容: %s\n", value)
-	fmt.Print("确认？(y/n/b 返回): ")
+	runLevel := TaskRunLevelLimited
+	if confirmYes("是否以最高权限运行？(y/n): ") {
+		runLevel = TaskRunLevelHighest
+	}
+
+	// 创建登录触发的任务需要管理员权限
+	if !ensureAdmin() {
+		return
+	}
 
-	reader := bufio.NewReader(os.Stdin)
-	confirm, _ := reader.ReadString('\n')
-	confirm = strings.TrimSpace(strings.ToLower(confirm))
+	if !confirmWithBack("添加为计划任务", taskName, buildCommand(exePath, args)) {
+		return
+	}
 
-	if confirm == "b" || confirm == "B" {
-		return false
+	if err := AddToScheduledTask(exePath, taskName, args, runLevel); err != nil {
+		printError("添加失败 - %v", err)
+		return
 	}
-	return confirm == "y" || confirm == "yes"
+	fmt.Printf("已成功将 %s 添加为登录时运行的计划任务！\n", taskName)
 }
 
-// IsInStartup 检查程序是否已在自启动列表中
-func IsInStartup(exePath, appName string) (bool, error) {
-	// 打开注册表键
-	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
+// handleRemoveScheduledTask 处理删除计划任务
+func handleRemoveScheduledTask() {
+	tasks, items, err := logonTaskListItems()
 	if err != nil {
-		return false, fmt.Errorf("打开注册表失败: %v", err)
+		fmt.Printf("读取计划任务失败: %v\n", err)
+		return
 	}
-	defer key.Close()
 
-	// 检查值是否存在
-	value, _, err := key.GetStringValue(appName)
-	if err != nil {
-		if err == registry.ErrNotExist {
-			return false, nil
-		}
-		return false, fmt.Errorf("查询注册表值失败: %v", err)
+	idx, ok := showListWithBack(items, "登录时运行的计划任务", pageSize)
+	if !ok {
+		return
 	}
 
-	// 检查路径是否匹配（去除引号）
-	absPath, _ := filepath.Abs(exePath)
-	value = strings.Trim(value, `"`)
-	valueAbs, _ := filepath.Abs(value)
+	selectedTask := tasks[idx]
+	if !confirmWithBack("删除计划任务", selectedTask.Name, selectedTask.commandLine()) {
+		return
+	}
 
-	return absPath == valueAbs, nil
+	if err := RemoveScheduledTask(selectedTask.Name); err != nil {
+		printError("删除失败 - %v", err)
+		return
+	}
+	fmt.Printf("已成功删除计划任务 %s！\n", selectedTask.Name)
 }
 
-// handleAddCommand 处理添加自定义命令
-func handleAddCommand() {
-	reader := bufio.NewReader(os.Stdin)
+// ========== 导入/导出 ==========
 
+// handleImportExport 导入/导出子菜单
+func handleImportExport() {
 	for {
 		fmt.Println("\n" + strings.Repeat("=", 60))
-		fmt.Println("添加自定义命令到自启动")
+		fmt.Println("导入/导出")
+		fmt.Println(strings.Repeat("=", 60))
+		fmt.Println("1. 导出到 .reg 文件")
+		fmt.Println("2. 从 .reg 文件导入")
+		fmt.Println("3. 导出到 CSV 文件")
+		fmt.Println("4. 从 CSV 文件导入")
+		fmt.Println("5. 导出为 PowerShell 脚本")
+		fmt.Println("6. 导出 HTML 报告")
+		fmt.Println("7. 从 Autoruns CSV 导入")
+		fmt.Println("8. 导出选中的启动项")
 		fmt.Println(strings.Repeat("=", 60))
-		fmt.Print("（输入 'b' 返回主菜单）\n\n")
 
-		// 步骤1：输入注册表项名称
-		fmt.Print("请输入注册表项名称（如 TaskManager）: ")
-		appName, _ := reader.ReadString('\n')
-		appName = strings.TrimSpace(appName)
+		choice := readInput("请选择操作（或输入 'b' 返回）: ")
 
-		if appName == "b" || appName == "B" {
+		switch choice {
+		case "1":
+			handleExportRegFile()
+		case "2":
+			handleImportRegFile()
+		case "3":
+			handleExportCSV()
+		case "4":
+			path := readInput("请输入要导入的 CSV 文件路径: ")
+			if path == "" {
+				continue
+			}
+			if err := runImportCSV(path, false); err != nil {
+				printError("导入失败 - %v", err)
+			}
+		case "5":
+			handleExportPowerShell()
+		case "6":
+			handleExportHTML()
+		case "7":
+			path := readInput("请输入要导入的 Autoruns CSV 文件路径: ")
+			if path == "" {
+				continue
+			}
+			if err := runImportAutoruns(path, false); err != nil {
+				printError("导入失败 - %v", err)
+			}
+		case "8":
+			handleExportSelected()
+		case "b", "B":
 			return
+		default:
+			fmt.Println("无效的选择，请重新输入。")
 		}
+	}
+}
 
-		if appName == "" {
-			fmt.Println("名称不能为空。")
-			continue
-		}
+// handleExportRegFile 处理导出到 .reg 文件
+func handleExportRegFile() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
 
-		// 步骤2：输入启动命令
-		fmt.Println("\n请输入完整的启动命令：")
-		fmt.Println("示例：python E:\\project\\python\\task-manager\\main.py")
-		fmt.Println("示例：E:\\app\\program.exe --arg value")
-		fmt.Print("（输入 'b' 返回上一步）\n\n命令: ")
+	if len(cache.Items) == 0 {
+		fmt.Println("当前没有配置任何自启动程序。")
+		return
+	}
 
-		command, _ := reader.ReadString('\n')
-		command = strings.TrimSpace(command)
+	path := readInput("请输入导出文件路径（默认 autostart.reg）: ")
+	if path == "" {
+		path = "autostart.reg"
+	}
 
-		if command == "b" || command == "B" {
-			continue
-		}
+	if err := ExportToRegFile(path, cache.Items); err != nil {
+		printError("导出失败 - %v", err)
+		return
+	}
+	fmt.Printf("已成功导出 %d 项到 %s！\n", len(cache.Items), path)
+}
 
-		if command == "" {
-			fmt.Println("命令不能为空。")
-			continue
-		}
+// handleImportRegFile 处理从 .reg 文件导入
+func handleImportRegFile() {
+	path := readInput("请输入要导入的 .reg 文件路径: ")
+	if path == "" {
+		return
+	}
 
-		// 确认添加
-		if confirmWithBack("添加", appName, command) {
-			err := AddCommandToStartup(command, appName)
-			if err != nil {
-				fmt.Printf("\n错误: 添加失败 - %v\n", err)
-			} else {
-				// 更新缓存
-				cache, _ := loadCache()
-				addOrUpdateItem(cache, appName, command, true)
-				saveCache(cache)
+	items, err := ImportFromRegFile(path)
+	if err != nil {
+		printError("导入失败 - %v", err)
+		return
+	}
 
-				fmt.Printf("已成功将命令添加到自启动！\n")
+	if len(items) == 0 {
+		fmt.Println("文件中没有找到启动项。")
+		return
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	// 所有项作为一个事务导入，任一项失败时全部回滚
+	tx := Begin()
+	imported := 0
+	for _, item := range items {
+		// "name"=- 删除项：只处理已存在且启用的启动项，导入后变为禁用
+		if !item.Enabled && item.Value == "" {
+			idx, existing := findItemByName(cache, item.Name)
+			if idx < 0 || !existing.Enabled {
+				continue
+			}
+			fmt.Printf("\n文件要求删除启动项 %s\n", item.Name)
+			fmt.Printf("当前内容: %s\n", existing.Value)
+			if !confirmYes("是否禁用？(y/n): ") {
+				continue
+			}
+			item.Value = existing.Value
+		} else if idx, existing := findItemByName(cache, item.Name); idx >= 0 {
+			// 与现有启动项冲突时确认是否覆盖
+			if existing.Value == item.Value && existing.Enabled {
+				continue
+			}
+			fmt.Printf("\n已存在同名启动项 %s\n", item.Name)
+			fmt.Printf("当前内容: %s\n", existing.Value)
+			fmt.Printf("导入内容: %s\n", item.Value)
+			if !confirmYes("是否覆盖？(y/n): ") {
+				continue
 			}
 		}
 
-		// 添加成功后返回主菜单
+		item := item
+		tx.AddEntryOperation(item.Name, func() error {
+			return applyImportedItem(cache, item)
+		})
+		imported++
+	}
+
+	if err := commitImport(tx, cache); err != nil {
+		printError("导入失败 - %v", err)
 		return
 	}
+	fmt.Printf("已成功导入 %d 项！\n", imported)
 }
 
-// AddCommandToStartup 添加自定义命令到Windows自启动
-func AddCommandToStartup(command, appName string) error {
-	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+// handleExportCSV 处理导出到 CSV 文件
+func handleExportCSV() {
+	cache, err := loadCache()
 	if err != nil {
-		return fmt.Errorf("打开注册表失败: %v", err)
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
 	}
-	defer key.Close()
 
-	err = key.SetStringValue(appName, command)
-	if err != nil {
-		return fmt.Errorf("设置注册表值失败: %v", err)
+	if len(cache.Items) == 0 {
+		fmt.Println("当前没有配置任何自启动程序。")
+		return
 	}
 
-	return nil
+	path := readInput("请输入导出文件路径（默认 autostart.csv）: ")
+	if path == "" {
+		path = "autostart.csv"
+	}
+
+	if err := exportCSVFile(path, cache.Items); err != nil {
+		printError("导出失败 - %v", err)
+		return
+	}
+	fmt.Printf("已成功导出 %d 项到 %s！\n", len(cache.Items), path)
 }
 
-// IsCommandInStartup 检查注册表项是否已存在
-func IsCommandInStartup(appName string) (bool, error) {
-	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
+// handleExportPowerShell 处理导出为 PowerShell 脚本
+func handleExportPowerShell() {
+	cache, err := loadCache()
 	if err != nil {
-		return false, fmt.Errorf("打开注册表失败: %v", err)
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
 	}
-	defer key.Close()
 
-	_, _, err = key.GetStringValue(appName)
-	if err == nil {
-		return true, nil
+	if len(cache.Items) == 0 {
+		fmt.Println("当前没有配置任何自启动程序。")
+		return
 	}
-	if err == registry.ErrNotExist {
-		return false, nil
+
+	path := readInput("请输入导出文件路径（默认 autostart.ps1）: ")
+	if path == "" {
+		path = "autostart.ps1"
 	}
-	return false, fmt.Errorf("查询注册表失败: %v", err)
+
+	if err := exportPowerShellFile(path, cache.Items); err != nil {
+		printError("导出失败 - %v", err)
+		return
+	}
+	fmt.Printf("已成功导出 %d 项到 %s！\n", len(cache.Items), path)
 }
 
-// handleEnable 启用禁用的启动项
-func handleEnable() {
+// handleExportHTML 处理导出 HTML 报告
+func handleExportHTML() {
 	cache, err := loadCache()
 	if err != nil {
 		fmt.Printf("加载缓存失败: %v\n", err)
 		return
 	}
 
-	// 筛选出禁用的项并转换为 ListItem
-	var disabledItems []ListItem
-	for _, item := range cache.Items {
-		if !item.Enabled {
-			disabledItems = append(disabledItems, ListItem{
-				Name:  item.Name,
-				Value: item.Value,
-			})
-		}
-	}
-
-	idx, ok := showListWithBack(disabledItems, "禁用的启动项列表")
-	if !ok {
+	if len(cache.Items) == 0 {
+		fmt.Println("当前没有配置任何自启动程序。")
 		return
 	}
 
-	selectedItem := disabledItems[idx]
+	path := readInput("请输入导出文件路径（默认 autostart.html）: ")
+	if path == "" {
+		path = "autostart.html"
+	}
 
-	// 确认启用
-	if !confirmWithBack("启用", selectedItem.Name, selectedItem.Value) {
+	if err := exportHTMLFile(path, cache.Items); err != nil {
+		printError("导出失败 - %v", err)
 		return
 	}
+	fmt.Printf("已成功导出 %d 项到 %s！\n", len(cache.Items), path)
+}
 
-	// 添加到注册表
-	err = AddCommandToStartup(selectedItem.Value, selectedItem.Name)
-	if err != nil {
-		fmt.Printf("\n错误: 启用失败 - %v\n", err)
-	} else {
-		// 更新缓存
-		addOrUpdateItem(cache, selectedItem.Name, selectedItem.Value, true)
-		saveCache(cache)
+// 导出选中的启动项时支持的格式
+var exportFormats = []string{"reg", "csv", "html", "ps1", "autoruns"}
+
+// ExportSelected 将 data.Items 中下标为 indices 的启动项按 format 导出到 destPath
+// format 为 reg、csv、html、ps1 或 autoruns
+func ExportSelected(indices []int, data *CacheData, format string, destPath string) error {
+	items := make([]CacheItem, 0, len(indices))
+	for _, i := range indices {
+		if i < 0 || i >= len(data.Items) {
+			return fmt.Errorf("无效的序号: %d", i+1)
+		}
+		items = append(items, data.Items[i])
+	}
 
-		fmt.Printf("已成功启用 %s！\n", selectedItem.Name)
+	switch strings.ToLower(format) {
+	case "reg":
+		return ExportToRegFile(destPath, items)
+	case "csv":
+		return exportCSVFile(destPath, items)
+	case "html":
+		return exportHTMLFile(destPath, items)
+	case "ps1":
+		return exportPowerShellFile(destPath, items)
+	case "autoruns":
+		return exportAutorunsFile(destPath, items)
 	}
+	return fmt.Errorf("不支持的导出格式: %s（可选 %s）", format, strings.Join(exportFormats, "、"))
 }
 
-// handleDisable 禁用启用的启动项
-func handleDisable() {
+// handleExportSelected 处理只导出选中的启动项
+func handleExportSelected() {
 	cache, err := loadCache()
 	if err != nil {
 		fmt.Printf("加载缓存失败: %v\n", err)
 		return
 	}
 
-	// 筛选出启用的项并转换为 ListItem
-	var enabledItems []ListItem
+	if len(cache.Items) == 0 {
+		fmt.Println("当前没有配置任何自启动程序。")
+		return
+	}
+
+	items := make([]ListItem, 0, len(cache.Items))
 	for _, item := range cache.Items {
-		if item.Enabled {
-			enabledItems = append(enabledItems, ListItem{
-				Name:  item.Name,
-				Value: item.Value,
-			})
-		}
+		items = append(items, ListItem{
+			Name:  item.Name,
+			Value: item.Value,
+		})
 	}
 
-	idx, ok := showListWithBack(enabledItems, "已启用的启动项列表")
+	indices, ok := showMultiSelectWithBack(items, "选择要导出的启动项", pageSize)
 	if !ok {
 		return
 	}
 
-	selectedItem := enabledItems[idx]
+	format := strings.ToLower(readInput(fmt.Sprintf("请输入导出格式（%s，默认 reg）: ", strings.Join(exportFormats, "/"))))
+	if format == "" {
+		format = "reg"
+	}
+	ext := format
+	if format == "autoruns" {
+		ext = "csv"
+	}
 
-	// 确认禁用
-	if !confirmWithBack("禁用", selectedItem.Name, selectedItem.Value) {
+	path := readInput(fmt.Sprintf("请输入导出文件路径（默认 autostart.%s）: ", ext))
+	if path == "" {
+		path = "autostart." + ext
+	}
+
+	if err := ExportSelected(indices, cache, format, path); err != nil {
+		printError("导出失败 - %v", err)
 		return
 	}
+	fmt.Printf("已成功导出 %d 项到 %s！\n", len(indices), path)
+}
+
+// runImportCSV 从 CSV 文件导入启动项，force 为 true 时跳过逐项确认
+func runImportCSV(path string, force bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	items, err := ImportFromCSV(file)
+	if err != nil {
+		return err
+	}
+	return importItems(items, force)
+}
+
+// runImportAutoruns 从 Autoruns 导出的 CSV 文件导入启动项，force 为 true 时跳过逐项确认
+func runImportAutoruns(path string, force bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	items, err := ImportFromAutoruns(file)
+	if err != nil {
+		return err
+	}
+	return importItems(items, force)
+}
+
+// importItems 作为一个事务导入启动项，force 为 true 时跳过逐项确认
+func importItems(items []CacheItem, force bool) error {
+	if len(items) == 0 {
+		fmt.Println("文件中没有找到启动项。")
+		return nil
+	}
 
-	// 从注册表删除
-	err = RemoveFromStartup(selectedItem.Name)
+	cache, err := loadCache()
 	if err != nil {
-		fmt.Printf("\n错误: 禁用失败 - %v\n", err)
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	// 所有项作为一个事务导入，任一项失败时全部回滚
+	tx := Begin()
+	imported := 0
+	for _, item := range items {
+		if !force && !confirmWithBack("导入", item.Name, item.Value) {
+			continue
+		}
+
+		item := item
+		tx.AddEntryOperation(item.Name, func() error {
+			if err := applyImportedItem(cache, item); err != nil {
+				return fmt.Errorf("导入 %s 失败: %v", item.Name, err)
+			}
+			return nil
+		})
+		imported++
+	}
+
+	if err := commitImport(tx, cache); err != nil {
+		return err
+	}
+	fmt.Printf("已成功导入 %d 项！\n", imported)
+	return nil
+}
+
+// commitImport 提交导入事务并保存缓存，保存失败时同样回滚
+func commitImport(tx *Transaction, cache *CacheData) error {
+	markOrphansStale()
+	// 保存缓存是最后一步，之后不会再有需要回滚的操作；之前失败时缓存文件保持原样
+	tx.AddOperation(func() error {
+		if err := saveCache(cache); err != nil {
+			return fmt.Errorf("保存缓存失败: %v", err)
+		}
+		return nil
+	})
+	return tx.Commit()
+}
+
+// applyImportedItem 将导入的启动项写入注册表和缓存
+// 启用的项写入注册表，禁用的项从注册表删除并只保留在缓存中
+func applyImportedItem(cache *CacheData, item CacheItem) error {
+	if item.Enabled {
+		// 隐藏窗口的 .vbs 只能在添加时生成，其中的命令无法从 Value 还原
+		if item.WindowState == windowStateHidden && !isExistingFile(wrapperPath(item.Name, "_hidden.vbs")) {
+			return fmt.Errorf("隐藏窗口的启动脚本 %s 已被删除，请重新添加", wrapperPath(item.Name, "_hidden.vbs"))
+		}
+		// 设置了延迟或工作目录的项重新生成启动脚本
+		if err := registerStartupItem(item); err != nil {
+			return err
+		}
 	} else {
-		// 更新缓存
-		addOrUpdateItem(cache, selectedItem.Name, selectedItem.Value, false)
-		saveCache(cache)
+		exists, err := IsCommandInStartup(item.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			if err := RemoveFromStartup(item.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	oldIdx, old := findItemByName(cache, item.Name)
+	imported := addOrUpdateItem(cache, item.Name, item.Value, item.Enabled)
+	if oldIdx < 0 {
+		recordHistory(imported, HistoryAdded, "", item.Value)
+	} else if old.Value != item.Value {
+		recordHistory(imported, HistoryEdited, old.Value, item.Value)
+	}
 
-		fmt.Printf("已成功禁用 %s！\n", selectedItem.Name)
+	// 保留导入的附加信息，启动方式以导入的为准
+	idx, _ := findItemByName(cache, item.Name)
+	cache.Items[idx].WindowState = item.WindowState
+	cache.Items[idx].DelaySeconds = item.DelaySeconds
+	cache.Items[idx].WorkingDir = item.WorkingDir
+	if len(item.Tags) > 0 {
+		cache.Items[idx].Tags = item.Tags
+	}
+	if item.Notes != "" {
+		cache.Items[idx].Notes = item.Notes
 	}
+	if !item.CreatedAt.IsZero() {
+		cache.Items[idx].CreatedAt = item.CreatedAt
+	}
+	if item.FileHash != "" {
+		cache.Items[idx].FileHash = item.FileHash
+	}
+	if item.FileVersion != "" {
+		cache.Items[idx].FileVersion = item.FileVersion
+	}
+	return nil
 }
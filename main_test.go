@@ -0,0 +1,473 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// memoryRegistry 保存在内存中的 RegistryBackend，测试时代替真实的 HKCU
+type memoryRegistry map[string]map[string]string
+
+func (m memoryRegistry) ReadValues(path string) (map[string]string, error) {
+	values, ok := m[path]
+	if !ok {
+		return nil, registry.ErrNotExist
+	}
+	result := make(map[string]string, len(values))
+	for name, value := range values {
+		result[name] = value
+	}
+	return result, nil
+}
+
+func (m memoryRegistry) GetValue(path, name string) (string, error) {
+	value, ok := m[path][name]
+	if !ok {
+		return "", registry.ErrNotExist
+	}
+	return value, nil
+}
+
+func (m memoryRegistry) SetValue(path, name, value string) error {
+	if m[path] == nil {
+		m[path] = make(map[string]string)
+	}
+	m[path][name] = value
+	return nil
+}
+
+func (m memoryRegistry) DeleteValue(path, name string) error {
+	if _, ok := m[path][name]; !ok {
+		return registry.ErrNotExist
+	}
+	delete(m[path], name)
+	return nil
+}
+
+// setupTestEnv 让缓存文件位于临时目录，注册表使用内存中的实现，测试结束后恢复
+func setupTestEnv(t *testing.T) memoryRegistry {
+	t.Helper()
+
+	oldRegistry, oldCachePath, oldFormat, oldProfile := startupRegistry, cacheFilePath, cacheFormat, currentProfile
+	oldConfig, oldEventLog, oldHostCheck, oldRunCounted, oldDryRun := config, noEventLog, ignoreHostCheck, runCounted, dryRun
+	t.Cleanup(func() {
+		startupRegistry, cacheFilePath, cacheFormat, currentProfile = oldRegistry, oldCachePath, oldFormat, oldProfile
+		config, noEventLog, ignoreHostCheck, runCounted, dryRun = oldConfig, oldEventLog, oldHostCheck, oldRunCounted, oldDryRun
+	})
+
+	reg := memoryRegistry{runKeyPath: {}}
+	startupRegistry = reg
+	config = defaultConfig()
+	noEventLog = true
+	ignoreHostCheck = true
+	runCounted = true
+	dryRun = false
+	useCacheDir(t.TempDir())
+	return reg
+}
+
+// writeTestFile 在 dir 下创建 name 文件并返回完整路径
+func writeTestFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAddOrUpdateItem(t *testing.T) {
+	t.Run("添加新项", func(t *testing.T) {
+		data := &CacheData{}
+		item := addOrUpdateItem(data, "App", `"C:\app.exe"`, true)
+		if len(data.Items) != 1 {
+			t.Fatalf("len(Items) = %d, want 1", len(data.Items))
+		}
+		if item.Name != "App" || item.Value != `"C:\app.exe"` || !item.Enabled {
+			t.Errorf("item = %+v", *item)
+		}
+		if item.CreatedAt.IsZero() {
+			t.Error("CreatedAt 未设置")
+		}
+	})
+
+	t.Run("更新已有项", func(t *testing.T) {
+		data := &CacheData{Items: []CacheItem{{Name: "App", Value: "old", Enabled: true, Notes: "保留"}}}
+		item := addOrUpdateItem(data, "App", "new", false)
+		if len(data.Items) != 1 {
+			t.Fatalf("len(Items) = %d, want 1", len(data.Items))
+		}
+		if item.Value != "new" || item.Enabled {
+			t.Errorf("item = %+v", *item)
+		}
+		if item.Notes != "保留" {
+			t.Errorf("Notes = %q，其他字段不应被修改", item.Notes)
+		}
+	})
+
+	t.Run("内容相同时不变", func(t *testing.T) {
+		data := &CacheData{}
+		first := *addOrUpdateItem(data, "App", "cmd", true)
+		second := *addOrUpdateItem(data, "App", "cmd", true)
+		if len(data.Items) != 1 {
+			t.Fatalf("len(Items) = %d, want 1", len(data.Items))
+		}
+		if first.Value != second.Value || first.Enabled != second.Enabled || !first.CreatedAt.Equal(second.CreatedAt) {
+			t.Errorf("first = %+v, second = %+v", first, second)
+		}
+	})
+}
+
+func TestRemoveItem(t *testing.T) {
+	t.Run("存在", func(t *testing.T) {
+		data := &CacheData{Items: []CacheItem{{Name: "A"}, {Name: "B"}, {Name: "C"}}}
+		removeItem(data, "B")
+		if len(data.Items) != 2 || data.Items[0].Name != "A" || data.Items[1].Name != "C" {
+			t.Errorf("Items = %+v", data.Items)
+		}
+	})
+
+	t.Run("不存在", func(t *testing.T) {
+		data := &CacheData{Items: []CacheItem{{Name: "A"}}}
+		removeItem(data, "B")
+		if len(data.Items) != 1 {
+			t.Errorf("Items = %+v", data.Items)
+		}
+	})
+}
+
+func TestFindItemByName(t *testing.T) {
+	data := &CacheData{Items: []CacheItem{{Name: "App", Value: "cmd"}}}
+
+	if idx, item := findItemByName(data, "App"); idx != 0 || item == nil || item.Value != "cmd" {
+		t.Errorf("findItemByName(App) = %d, %v", idx, item)
+	}
+	if idx, item := findItemByName(data, "app"); idx != -1 || item != nil {
+		t.Errorf("findItemByName(app) = %d, %v，名称应区分大小写", idx, item)
+	}
+	if idx, item := findItemByName(&CacheData{}, "App"); idx != -1 || item != nil {
+		t.Errorf("空缓存中 findItemByName = %d, %v", idx, item)
+	}
+
+	// 返回的是副本，修改它不影响缓存
+	_, item := findItemByName(data, "App")
+	item.Value = "changed"
+	if data.Items[0].Value != "cmd" {
+		t.Error("修改返回值影响了缓存")
+	}
+}
+
+func TestSyncCacheFromRegistry(t *testing.T) {
+	tests := []struct {
+		name        string
+		cache       []CacheItem
+		run         map[string]string
+		runDisabled map[string]string
+		wantValue   string
+		wantEnabled bool
+	}{
+		{
+			name:        "缓存中启用但注册表中不存在时禁用",
+			cache:       []CacheItem{{Name: "App", Value: "cmd", Enabled: true}},
+			wantValue:   "cmd",
+			wantEnabled: false,
+		},
+		{
+			name:        "两边都存在时以注册表的值为准并启用",
+			cache:       []CacheItem{{Name: "App", Value: "old", Enabled: false}},
+			run:         map[string]string{"App": "new"},
+			wantValue:   "new",
+			wantEnabled: true,
+		},
+		{
+			name:        "只在注册表中时加入缓存",
+			run:         map[string]string{"App": "cmd"},
+			wantValue:   "cmd",
+			wantEnabled: true,
+		},
+		{
+			name:        "其他工具禁用的项以禁用状态加入缓存",
+			runDisabled: map[string]string{"App": "cmd"},
+			wantValue:   "cmd",
+			wantEnabled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := setupTestEnv(t)
+			for name, value := range tt.run {
+				reg.SetValue(runKeyPath, name, value)
+			}
+			for name, value := range tt.runDisabled {
+				reg.SetValue(runDisabledKeyPath, name, value)
+			}
+			if err := saveCache(&CacheData{Items: tt.cache}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := syncCacheFromRegistry(); err != nil {
+				t.Fatalf("syncCacheFromRegistry: %v", err)
+			}
+
+			cache, err := loadCache()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cache.Items) != 1 {
+				t.Fatalf("Items = %+v, want 1 项", cache.Items)
+			}
+			item := cache.Items[0]
+			if item.Value != tt.wantValue || item.Enabled != tt.wantEnabled {
+				t.Errorf("item = {Value: %q, Enabled: %v}, want {Value: %q, Enabled: %v}",
+					item.Value, item.Enabled, tt.wantValue, tt.wantEnabled)
+			}
+			if cache.SyncedAt.IsZero() {
+				t.Error("SyncedAt 未设置")
+			}
+		})
+	}
+}
+
+func TestRemoveEntry(t *testing.T) {
+	reg := setupTestEnv(t)
+	for name, value := range map[string]string{"A": `C:\a.exe`, "B": `C:\b.exe`} {
+		if err := AddCommandEntry(name, value, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := DisableEntry("B", ""); err != nil {
+		t.Fatal(err)
+	}
+	undoStack = nil
+	t.Cleanup(func() { undoStack = nil })
+
+	// 已禁用的项不在注册表中，也能移除
+	for _, name := range []string{"A", "B"} {
+		if err := RemoveEntry(name); err != nil {
+			t.Errorf("RemoveEntry(%s): %v", name, err)
+		}
+	}
+	if _, err := reg.GetValue(runKeyPath, "A"); err == nil {
+		t.Error("A 应从注册表中删除")
+	}
+	cache, err := loadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.Items) != 0 {
+		t.Errorf("缓存 = %+v, want 空", cache.Items)
+	}
+	if len(undoStack) != 2 {
+		t.Errorf("撤销栈有 %d 项，want 2", len(undoStack))
+	}
+	if err := RemoveEntry("Missing"); err == nil {
+		t.Error("移除不存在的启动项应返回错误")
+	}
+}
+
+func TestParseCommandPath(t *testing.T) {
+	dir := t.TempDir()
+	spaced := writeTestFile(t, dir, `My App\app.exe`)
+
+	tests := []struct {
+		cmd      string
+		wantPath string
+		wantArgs string
+		wantErr  bool
+	}{
+		{cmd: `"C:\Program Files\App\app.exe"`, wantPath: `C:\Program Files\App\app.exe`},
+		{cmd: `"C:\Program Files\App\app.exe" --min -x`, wantPath: `C:\Program Files\App\app.exe`, wantArgs: "--min -x"},
+		{cmd: `C:\tools\app.exe`, wantPath: `C:\tools\app.exe`},
+		{cmd: `C:\tools\app.exe --flag`, wantPath: `C:\tools\app.exe`, wantArgs: "--flag"},
+		{cmd: spaced + " --flag", wantPath: spaced, wantArgs: "--flag"},
+		{cmd: `python script.py`, wantPath: "python", wantArgs: "script.py"},
+		{cmd: "  ", wantErr: true},
+		{cmd: `"C:\app.exe`, wantErr: true},
+		{cmd: `"" --flag`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		path, args, err := parseCommandPath(tt.cmd)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseCommandPath(%q) 应返回错误", tt.cmd)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseCommandPath(%q): %v", tt.cmd, err)
+			continue
+		}
+		if path != tt.wantPath || args != tt.wantArgs {
+			t.Errorf("parseCommandPath(%q) = %q, %q, want %q, %q", tt.cmd, path, args, tt.wantPath, tt.wantArgs)
+		}
+	}
+}
+
+func TestExpandEnvPath(t *testing.T) {
+	t.Setenv("AUTOSTART_TEST_DIR", `C:\data`)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`%AUTOSTART_TEST_DIR%\app.exe`, `C:\data\app.exe`},
+		{`%autostart_test_dir%\app.exe`, `C:\data\app.exe`},
+		{`$AUTOSTART_TEST_DIR\app.exe`, `C:\data\app.exe`},
+		{`${AUTOSTART_TEST_DIR}\app.exe`, `C:\data\app.exe`},
+		{`%AUTOSTART_UNDEFINED_VAR%\app.exe`, `%AUTOSTART_UNDEFINED_VAR%\app.exe`},
+		{`\\server\c$\app.exe`, `\\server\c$\app.exe`},
+		{`C:\plain\app.exe`, `C:\plain\app.exe`},
+	}
+	for _, tt := range tests {
+		if got := expandEnvPath(tt.path); got != tt.want {
+			t.Errorf("expandEnvPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAssessPathRisk(t *testing.T) {
+	oldConfig := config
+	config = defaultConfig()
+	t.Cleanup(func() { config = oldConfig })
+
+	t.Setenv("TEMP", `C:\fake\temp`)
+	t.Setenv("TMP", `C:\fake\tmp`)
+	t.Setenv("USERPROFILE", `C:\fake\user`)
+	t.Setenv("APPDATA", `C:\fake\user\AppData\Roaming`)
+	t.Setenv("ProgramFiles", `C:\fake\Program Files`)
+
+	tests := []struct {
+		path string
+		want RiskLevel
+	}{
+		{`C:\fake\temp\dropper.exe`, RiskHigh},
+		{`%TEMP%\dropper.exe`, RiskHigh},
+		{`C:\FAKE\TMP\x.exe`, RiskHigh},
+		{`C:\fake\user\Downloads\setup.exe`, RiskHigh},
+		// 文件不存在，读取不到版本信息
+		{`C:\fake\user\AppData\Roaming\tool\tool.exe`, RiskMedium},
+		{`C:\fake\Program Files\App\app.exe`, RiskLow},
+		{`C:\fake\temperature\app.exe`, RiskLow},
+		{`D:\tools\app.exe`, RiskLow},
+	}
+	for _, tt := range tests {
+		if got := AssessPathRisk(tt.path); got != tt.want {
+			t.Errorf("AssessPathRisk(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestValidateStartupEntries(t *testing.T) {
+	setupTestEnv(t)
+	dir := t.TempDir()
+	exe := writeTestFile(t, dir, "app.exe")
+	txt := writeTestFile(t, dir, "notes.txt")
+
+	items := []CacheItem{
+		{Name: "Valid", Value: buildCommand(exe, "--min"), Enabled: true},
+		{Name: "Empty", Value: " ", Enabled: true},
+		{Name: "Missing", Value: buildCommand(filepath.Join(dir, "missing.exe"), ""), Enabled: true},
+		{Name: "NotExe", Value: buildCommand(txt, ""), Enabled: true},
+		{Name: "EnvVar", Value: `"%AUTOSTART_UNDEFINED_VAR%\app.exe"`, Enabled: true},
+		{Name: "BadQuote", Value: `"C:\app.exe`, Enabled: true},
+	}
+	if err := saveCache(&CacheData{Items: items}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ValidateStartupEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]Severity)
+	for _, result := range results {
+		got[result.Name] = result.Severity
+	}
+	want := map[string]Severity{
+		"Empty":    SeverityError,
+		"Missing":  SeverityError,
+		"NotExe":   SeverityWarning,
+		"EnvVar":   SeverityWarning,
+		"BadQuote": SeverityError,
+	}
+	for name, severity := range want {
+		if s, ok := got[name]; !ok || s != severity {
+			t.Errorf("%s: severity = %v (found %v), want %v", name, s, ok, severity)
+		}
+	}
+	if _, ok := got["Valid"]; ok {
+		t.Errorf("Valid 不应有问题: %+v", results)
+	}
+}
+
+func TestCacheJSONStability(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 123456789, cst)
+	expires := time.Date(2025, 6, 7, 8, 9, 10, 0, time.UTC)
+
+	data := &CacheData{
+		Version: cacheVersion,
+		Items: []CacheItem{
+			{
+				Name:         "App",
+				Value:        `"C:\Program Files\App\app.exe" --min`,
+				Enabled:      true,
+				Tags:         []string{"工作", "dev"},
+				Notes:        "带 \"引号\" 和\n换行",
+				CreatedAt:    created,
+				UpdatedAt:    created.Add(time.Hour),
+				WindowState:  windowStateMinimized,
+				DelaySeconds: 30,
+				WorkingDir:   `C:\Work`,
+				History: []HistoryRecord{
+					{Timestamp: created, Action: "添加", NewValue: "cmd", User: `PC\user`},
+				},
+				ExpiresAt:   &expires,
+				RunCount:    3,
+				FileModTime: created,
+				Priority:    2,
+			},
+			{Name: "Disabled", Value: "cmd.exe /c exit", Enabled: false, Scope: ScopeMachineWide, RunOnce: true},
+		},
+		ArchivedItems: []CacheItem{{Name: "Old", Value: "old.exe", ArchivedAt: created}},
+		SyncedAt:      created,
+		Hostname:      "PC",
+		Username:      `PC\user`,
+	}
+
+	path := filepath.Join(t.TempDir(), "autostart.json")
+	if err := saveCacheAuto(path, data); err != nil {
+		t.Fatal(err)
+	}
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadCacheAuto(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := saveCacheAuto(path, loaded); err != nil {
+		t.Fatal(err)
+	}
+	second, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(first, second) {
+		t.Errorf("读回后重新保存的内容发生变化\n第一次:\n%s\n第二次:\n%s", first, second)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Manager 供非交互前端（HTTP 接口、命名管道等）使用的启动项操作入口
+// 与命令行菜单调用相同的函数；多个请求并发时依次执行，避免同时读写注册表和缓存
+type Manager struct {
+	mu sync.Mutex
+}
+
+// NewManager 创建 Manager
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// List 先从注册表同步，再返回缓存中的所有启动项
+func (m *Manager) List() ([]CacheItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := syncCacheFromRegistry(); err != nil {
+		return nil, err
+	}
+	cache, err := loadCache()
+	if err != nil {
+		return nil, fmt.Errorf("加载缓存失败: %v", err)
+	}
+	return cache.Items, nil
+}
+
+// Get 返回单个启动项，不存在时返回 ErrNotFound
+func (m *Manager) Get(name string) (*CacheItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return GetStartupEntry(name)
+}
+
+// Add 将 item.Value 作为启动命令添加到自启动，item.Reason 可选
+func (m *Manager) Add(item CacheItem) error {
+	if item.Name == "" {
+		return fmt.Errorf("名称不能为空")
+	}
+	if item.Value == "" {
+		return fmt.Errorf("命令不能为空")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return AddCommandEntry(item.Name, item.Value, item.Reason)
+}
+
+// Remove 从自启动中移除启动项
+func (m *Manager) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return RemoveEntry(name)
+}
+
+// Enable 启用启动项
+func (m *Manager) Enable(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return EnableEntry(name)
+}
+
+// Disable 禁用启动项，reason 为可选的禁用原因
+func (m *Manager) Disable(name, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return DisableEntry(name, reason)
+}
@@ -0,0 +1,40 @@
+// Package migrations 缓存文件各版本之间的迁移，每个文件对应一次版本升级
+//
+// 迁移函数作用于解码为通用结构的缓存文件（JSON 对象），不依赖主程序的缓存类型，
+// 因此旧版本的迁移不会随 CacheItem 的修改而变化。
+package migrations
+
+// Document 解码为通用结构的缓存文件，键为 JSON 字段名
+type Document = map[string]any
+
+// All 第 i 个函数把版本 i 的缓存迁移到版本 i+1
+// 给缓存增加字段时新建 vN_to_vN+1.go 并追加到末尾
+var All = []func(Document){
+	V0toV1,
+	V1toV2,
+	V2toV3,
+	V3toV4,
+	V4toV5,
+	V5toV6,
+	V6toV7,
+	V7toV8,
+	V8toV9,
+	V9toV10,
+}
+
+// Latest 迁移后的最新版本
+func Latest() int {
+	return len(All)
+}
+
+// items 返回缓存中的启动项，跳过格式不对的项
+func items(doc Document) []map[string]any {
+	list, _ := doc["items"].([]any)
+	result := make([]map[string]any, 0, len(list))
+	for _, v := range list {
+		if item, ok := v.(map[string]any); ok {
+			result = append(result, item)
+		}
+	}
+	return result
+}
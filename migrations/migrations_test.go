@@ -0,0 +1,30 @@
+package migrations
+
+import "testing"
+
+func TestAll(t *testing.T) {
+	doc := Document{
+		"items": []any{
+			map[string]any{"name": "App", "value": "app.exe", "enabled": true},
+			"无效的项",
+		},
+	}
+	for _, migrate := range All {
+		migrate(doc)
+	}
+	if doc["version"] != Latest() {
+		t.Errorf("version = %v, want %d", doc["version"], Latest())
+	}
+	item := doc["items"].([]any)[0].(map[string]any)
+	if item["scope"] != 0 || item["name"] != "App" || item["enabled"] != true {
+		t.Errorf("item = %v", item)
+	}
+}
+
+func TestV1toV2WithoutItems(t *testing.T) {
+	doc := Document{"version": 1}
+	V1toV2(doc)
+	if doc["version"] != 2 {
+		t.Errorf("version = %v, want 2", doc["version"])
+	}
+}
@@ -0,0 +1,7 @@
+package migrations
+
+// V0toV1 迁移加入版本号之前写入的缓存文件
+// 这些文件缺少的字段解码后已是零值，只需补上版本号
+func V0toV1(doc Document) {
+	doc["version"] = 1
+}
@@ -0,0 +1,9 @@
+package migrations
+
+// V1toV2 加入 scope 字段，之前缓存的启动项都在当前用户的 Run 键中（0 表示 HKEY_CURRENT_USER）
+func V1toV2(doc Document) {
+	for _, item := range items(doc) {
+		item["scope"] = 0
+	}
+	doc["version"] = 2
+}
@@ -0,0 +1,6 @@
+package migrations
+
+// V2toV3 加入已归档的启动项，旧缓存中没有归档
+func V2toV3(doc Document) {
+	doc["version"] = 3
+}
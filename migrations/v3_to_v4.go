@@ -0,0 +1,6 @@
+package migrations
+
+// V3toV4 加入修改历史，旧缓存中的启动项没有历史记录
+func V3toV4(doc Document) {
+	doc["version"] = 4
+}
@@ -0,0 +1,6 @@
+package migrations
+
+// V4toV5 加入到期时间，旧缓存中的启动项都不会到期
+func V4toV5(doc Document) {
+	doc["version"] = 5
+}
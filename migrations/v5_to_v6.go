@@ -0,0 +1,6 @@
+package migrations
+
+// V5toV6 加入运行次数，旧缓存中的启动项从 0 开始计数
+func V5toV6(doc Document) {
+	doc["version"] = 6
+}
@@ -0,0 +1,6 @@
+package migrations
+
+// V6toV7 加入程序文件的修改时间，旧缓存中的启动项在下次同步时记录
+func V6toV7(doc Document) {
+	doc["version"] = 7
+}
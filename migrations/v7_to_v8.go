@@ -0,0 +1,6 @@
+package migrations
+
+// V7toV8 加入工作目录，旧缓存中的启动项都不切换工作目录
+func V7toV8(doc Document) {
+	doc["version"] = 8
+}
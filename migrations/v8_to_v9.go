@@ -0,0 +1,6 @@
+package migrations
+
+// V8toV9 加入显示顺序，旧缓存中的启动项都按名称排序
+func V8toV9(doc Document) {
+	doc["version"] = 9
+}
@@ -0,0 +1,6 @@
+package migrations
+
+// V9toV10 加入 RunOnce 标记，旧缓存中的启动项都位于 Run 键
+func V9toV10(doc Document) {
+	doc["version"] = 10
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 不带引号的命令中可识别的程序扩展名，用于在文件不存在时判断程序路径的结尾
+var normalizeExtensions = []string{".exe", ".bat", ".cmd", ".com"}
+
+// normalizeRegistryValue 规范化启动命令中程序路径的引号
+// 去掉多余的重复引号（""C:\app.exe"" 或 \"C:\app.exe\"），
+// 程序路径含有空格但没有引号时加上引号；无法判断程序路径时原样返回
+func normalizeRegistryValue(value string) string {
+	cmd := strings.TrimSpace(value)
+
+	// 转义的引号：\"C:\path\app.exe\" --flag
+	if strings.HasPrefix(cmd, `\"`) {
+		if end := strings.Index(cmd[2:], `\"`); end >= 0 {
+			cmd = `"` + cmd[2:end+2] + `"` + cmd[end+4:]
+		}
+	}
+	// 重复的引号：""C:\path\app.exe"" --flag
+	for strings.HasPrefix(cmd, `""`) {
+		end := strings.Index(cmd[2:], `""`)
+		if end < 0 {
+			break
+		}
+		cmd = `"` + cmd[2:end+2] + `"` + cmd[end+4:]
+	}
+
+	if strings.HasPrefix(cmd, `"`) {
+		return cmd
+	}
+
+	exePath, args := splitUnquotedCommand(cmd)
+	if exePath == "" || !strings.Contains(exePath, " ") {
+		return cmd
+	}
+	return buildCommand(exePath, args)
+}
+
+// splitUnquotedCommand 拆分不带引号的命令，返回原始形式的程序路径和参数
+// 优先取磁盘上实际存在的最长前缀，其次取第一个以程序扩展名结尾的前缀
+func splitUnquotedCommand(cmd string) (string, string) {
+	for i := len(cmd); i > 0; i = strings.LastIndex(cmd[:i], " ") {
+		if isExistingFile(expandEnvPath(strings.TrimSpace(cmd[:i]))) {
+			return strings.TrimSpace(cmd[:i]), strings.TrimSpace(cmd[i:])
+		}
+	}
+
+	lower := strings.ToLower(cmd)
+	for i := 0; i < len(cmd); i++ {
+		for _, ext := range normalizeExtensions {
+			end := i + len(ext)
+			if !strings.HasPrefix(lower[i:], ext) || (end < len(cmd) && cmd[end] != ' ') {
+				continue
+			}
+			return cmd[:end], strings.TrimSpace(cmd[end:])
+		}
+	}
+	return "", ""
+}
+
+// normalizeChange 一个需要规范化的启动项
+type normalizeChange struct {
+	Name     string
+	OldValue string
+	NewValue string
+}
+
+// FindUnnormalizedEntries 查找缓存中 Value 与规范化结果不同的启动项
+func FindUnnormalizedEntries(cache *CacheData) []normalizeChange {
+	var changes []normalizeChange
+	for _, item := range cache.Items {
+		if normalized := normalizeRegistryValue(item.Value); normalized != item.Value {
+			changes = append(changes, normalizeChange{
+				Name:     item.Name,
+				OldValue: item.Value,
+				NewValue: normalized,
+			})
+		}
+	}
+	return changes
+}
+
+// NormalizeAllEntries 规范化所有启动项的值，已启用的项同时重新写入注册表
+// 返回规范化的启动项数量
+func NormalizeAllEntries() (int, error) {
+	cache, err := loadCache()
+	if err != nil {
+		return 0, fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	changes := FindUnnormalizedEntries(cache)
+	for _, change := range changes {
+		idx, _ := findItemByName(cache, change.Name)
+		item := &cache.Items[idx]
+		item.Value = change.NewValue
+		recordHistory(item, HistoryEdited, change.OldValue, change.NewValue)
+		if item.Enabled {
+			if err := registerStartupItem(*item); err != nil {
+				return 0, fmt.Errorf("更新 %s 失败: %v", item.Name, err)
+			}
+		}
+	}
+
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	if err := saveCache(cache); err != nil {
+		return 0, fmt.Errorf("保存缓存失败: %v", err)
+	}
+	markOrphansStale()
+	return len(changes), nil
+}
+
+// handleNormalizeValues 显示需要规范化的启动项，确认后统一规范化
+func handleNormalizeValues() {
+	syncIfStale()
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	changes := FindUnnormalizedEntries(cache)
+	if len(changes) == 0 {
+		fmt.Println("所有启动项的值都已规范。")
+		return
+	}
+
+	fmt.Printf("\n以下 %d 个启动项的程序路径引号不规范:\n", len(changes))
+	for i, change := range changes {
+		fmt.Printf("%d. %s\n", i+1, change.Name)
+		fmt.Printf("   当前: %s\n", change.OldValue)
+		fmt.Printf("   改为: %s\n", change.NewValue)
+	}
+	if !confirmYes("\n确定要规范化以上启动项吗？(y/n): ") {
+		return
+	}
+
+	count, err := NormalizeAllEntries()
+	if err != nil {
+		printError("规范化失败 - %v", err)
+		return
+	}
+	fmt.Printf("已规范化 %d 个启动项。\n", count)
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// 命名管道的缓冲区大小
+	pipeBufferSize = 64 * 1024
+	// 客户端等待管道空闲的最长时间
+	pipeBusyTimeout = 2 * time.Second
+)
+
+// JSON-RPC 2.0 错误码
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+)
+
+// rpcRequest JSON-RPC 2.0 请求
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// rpcError JSON-RPC 2.0 错误对象
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s（错误码 %d）", e.Message, e.Code)
+}
+
+// rpcResponse JSON-RPC 2.0 响应
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// rpcEntryParams autostart.add/remove/enable/disable 的参数
+type rpcEntryParams struct {
+	Name   string `json:"name"`
+	Value  string `json:"value,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// pipePath 返回命名管道的完整路径
+func pipePath(pipeName string) string {
+	return `\\.\pipe\` + pipeName
+}
+
+// StartNamedPipeServer 在 \\.\pipe\<pipeName> 上接受 JSON-RPC 2.0 请求，直到出错才返回
+// 每个连接中可依次发送多个请求；支持 autostart.list、add、remove、enable、disable
+func StartNamedPipeServer(pipeName string, manager *Manager) error {
+	path, err := windows.UTF16PtrFromString(pipePath(pipeName))
+	if err != nil {
+		return err
+	}
+
+	for {
+		handle, err := windows.CreateNamedPipe(path, windows.PIPE_ACCESS_DUPLEX,
+			windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+			windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, nil)
+		if err != nil {
+			return fmt.Errorf("创建命名管道失败: %v", err)
+		}
+
+		// 客户端在 CreateNamedPipe 与 ConnectNamedPipe 之间连接时返回 ERROR_PIPE_CONNECTED
+		if err := windows.ConnectNamedPipe(handle, nil); err != nil && err != windows.ERROR_PIPE_CONNECTED {
+			windows.CloseHandle(handle)
+			return fmt.Errorf("等待客户端连接失败: %v", err)
+		}
+
+		conn := os.NewFile(uintptr(handle), pipePath(pipeName))
+		go servePipeConn(conn, manager)
+	}
+}
+
+// servePipeConn 处理一个客户端连接中的所有请求
+func servePipeConn(conn io.ReadWriteCloser, manager *Manager) {
+	defer conn.Close()
+
+	decoder := json.NewDecoder(conn)
+	encoder := json.NewEncoder(conn)
+	for {
+		var req rpcRequest
+		if err := decoder.Decode(&req); err != nil {
+			if err != io.EOF {
+				encoder.Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+					Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+			}
+			return
+		}
+
+		resp := handleRPC(req, manager)
+		// 没有 id 的请求是通知，不需要响应
+		if req.ID == nil {
+			continue
+		}
+		resp.ID = req.ID
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handleRPC 执行一个 JSON-RPC 请求
+func handleRPC(req rpcRequest, manager *Manager) rpcResponse {
+	resp := rpcResponse{JSONRPC: "2.0"}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{Code: rpcInvalidRequest, Message: "无效的请求"}
+		return resp
+	}
+
+	var params rpcEntryParams
+	if req.Method != "autostart.list" {
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+			resp.Error = &rpcError{Code: rpcInvalidParams, Message: "缺少参数 name"}
+			return resp
+		}
+	}
+
+	var result interface{}
+	var err error
+	switch req.Method {
+	case "autostart.list":
+		result, err = manager.List()
+	case "autostart.add":
+		if err = manager.Add(CacheItem{Name: params.Name, Value: params.Value, Reason: params.Reason}); err == nil {
+			result, err = manager.Get(params.Name)
+		}
+	case "autostart.remove":
+		err = manager.Remove(params.Name)
+	case "autostart.enable":
+		err = manager.Enable(params.Name)
+	case "autostart.disable":
+		err = manager.Disable(params.Name, params.Reason)
+	default:
+		resp.Error = &rpcError{Code: rpcMethodNotFound, Message: "未知的方法: " + req.Method}
+		return resp
+	}
+
+	if err != nil {
+		resp.Error = &rpcError{Code: rpcServerError, Message: err.Error()}
+		return resp
+	}
+	if result == nil {
+		result = true
+	}
+	resp.Result, _ = json.Marshal(result)
+	return resp
+}
+
+// SendNamedPipeCommand 连接 \\.\pipe\<pipeName> 并调用 method，返回结果的原始 JSON
+func SendNamedPipeCommand(pipeName, method string, params interface{}) (json.RawMessage, error) {
+	path, err := windows.UTF16PtrFromString(pipePath(pipeName))
+	if err != nil {
+		return nil, err
+	}
+
+	// 所有管道实例都在使用时稍后重试
+	deadline := time.Now().Add(pipeBusyTimeout)
+	var handle windows.Handle
+	for {
+		handle, err = windows.CreateFile(path, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil,
+			windows.OPEN_EXISTING, 0, 0)
+		if err == nil {
+			break
+		}
+		if err != windows.ERROR_PIPE_BUSY || time.Now().After(deadline) {
+			return nil, fmt.Errorf("连接命名管道失败: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	conn := os.NewFile(uintptr(handle), pipePath(pipeName))
+	defer conn.Close()
+
+	req := rpcRequest{JSONRPC: "2.0", Method: method, ID: json.RawMessage("1")}
+	if params != nil {
+		if req.Params, err = json.Marshal(params); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("发送请求失败: %v", err)
+	}
+
+	var resp rpcResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("读取响应失败: %v", err)
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Result == nil {
+		return nil, errors.New("响应中没有结果")
+	}
+	return resp.Result, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ExportToPowerShell 将启动项导出为 PowerShell 脚本
+// 启用的项生成 Set-ItemProperty，禁用的项生成注释掉的 Remove-ItemProperty，
+// 生成的脚本不依赖本工具，可在其他电脑上直接运行；启动项按调整过的顺序输出
+func ExportToPowerShell(w io.Writer, items []CacheItem) error {
+	hostname, _ := os.Hostname()
+
+	var sb strings.Builder
+	sb.WriteString("# " + strings.Repeat("=", 58) + "\r\n")
+	sb.WriteString("# Windows 自启动配置\r\n")
+	sb.WriteString("# 导出时间: " + time.Now().Format("2006-01-02 15:04:05") + "\r\n")
+	sb.WriteString("# 计算机名: " + hostname + "\r\n")
+	sb.WriteString("# 由 Windows 自启动设置工具生成，可直接在 PowerShell 中运行\r\n")
+	sb.WriteString("# " + strings.Repeat("=", 58) + "\r\n\r\n")
+
+	sb.WriteString("$runKey = " + quotePowerShell(`HKCU:\`+runKeyPath) + "\r\n")
+	sb.WriteString("if (-not (Test-Path $runKey)) {\r\n")
+	sb.WriteString("    New-Item -Path $runKey -Force | Out-Null\r\n")
+	sb.WriteString("}\r\n\r\n")
+
+	for _, item := range sortByPriority(items) {
+		// 使用单引号字符串，保留路径中的环境变量原样输出
+		name := quotePowerShell(item.Name)
+		if item.Enabled {
+			sb.WriteString(fmt.Sprintf("Set-ItemProperty -Path $runKey -Name %s -Value %s\r\n",
+				name, quotePowerShell(item.Value)))
+		} else {
+			sb.WriteString(fmt.Sprintf("# 已禁用: %s\r\n", item.Value))
+			sb.WriteString(fmt.Sprintf("# Remove-ItemProperty -Path $runKey -Name %s -ErrorAction SilentlyContinue\r\n", name))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// exportPowerShellFile 将启动项导出为 .ps1 文件
+func exportPowerShellFile(path string, items []CacheItem) error {
+	if dryRunf("导出 %d 项到 %s", len(items), path) {
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Windows PowerShell 5 需要 BOM 才能正确识别 UTF-8 编码的中文
+	if _, err := file.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return err
+	}
+	return ExportToPowerShell(file, items)
+}
+
+// quotePowerShell 将字符串转为 PowerShell 单引号字符串
+func quotePowerShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
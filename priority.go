@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sortByPriority 返回按 Priority 从小到大、相同时按名称排序的启动项副本
+// 未调整过顺序的项 Priority 为 0，按名称排在最前
+func sortByPriority(items []CacheItem) []CacheItem {
+	sorted := append([]CacheItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority < sorted[j].Priority
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// renumberPriorities 按当前顺序将所有启动项的 Priority 重新编号为 1..n，返回排好序的名称
+func renumberPriorities(data *CacheData) []string {
+	sorted := sortByPriority(data.Items)
+	names := make([]string, len(sorted))
+	for i, item := range sorted {
+		names[i] = item.Name
+		idx, _ := findItemByName(data, item.Name)
+		data.Items[idx].Priority = i + 1
+	}
+	return names
+}
+
+// nextPriority 新添加的启动项的 Priority：调整过顺序时排在最后，否则为 0
+func nextPriority(data *CacheData) int {
+	max := 0
+	for _, item := range data.Items {
+		if item.Priority > max {
+			max = item.Priority
+		}
+	}
+	if max == 0 {
+		return 0
+	}
+	return max + 1
+}
+
+// MoveEntry 将启动项 name 与上一项（offset 为 -1）或下一项（offset 为 1）交换位置，移动后重新连续编号
+func MoveEntry(data *CacheData, name string, offset int) error {
+	names := renumberPriorities(data)
+	from := -1
+	for i, n := range names {
+		if n == name {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return fmt.Errorf("启动项不存在: %s", name)
+	}
+
+	to := from + offset
+	if to < 0 {
+		return fmt.Errorf("%s 已经在最前面", name)
+	}
+	if to >= len(names) {
+		return fmt.Errorf("%s 已经在最后面", name)
+	}
+
+	names[from], names[to] = names[to], names[from]
+	for i, n := range names {
+		idx, _ := findItemByName(data, n)
+		data.Items[idx].Priority = i + 1
+	}
+	return nil
+}
+
+// handleReorder 处理调整启动项的显示顺序
+func handleReorder() {
+	for {
+		cache, err := loadCache()
+		if err != nil {
+			fmt.Printf("加载缓存失败: %v\n", err)
+			return
+		}
+		if len(cache.Items) == 0 {
+			fmt.Println("当前没有任何启动项。")
+			return
+		}
+
+		fmt.Println("\n启动项顺序:")
+		for i, item := range sortByPriority(cache.Items) {
+			fmt.Printf("%d. %s ", i+1, item.Name)
+			printer.Print(statusChip(item.Enabled))
+			fmt.Println()
+		}
+
+		input := readInput("\n输入 u 序号 上移、d 序号 下移（如 u 3），0 返回: ")
+		if input == "0" || input == "" {
+			return
+		}
+
+		fields := strings.Fields(strings.ToLower(input))
+		if len(fields) != 2 || (fields[0] != "u" && fields[0] != "d") {
+			fmt.Println("无效的命令，请输入 u 或 d 加序号。")
+			continue
+		}
+		n, err := strconv.Atoi(fields[1])
+		sorted := sortByPriority(cache.Items)
+		if err != nil || n < 1 || n > len(sorted) {
+			fmt.Println("无效的序号。")
+			continue
+		}
+
+		offset := 1
+		if fields[0] == "u" {
+			offset = -1
+		}
+		if err := MoveEntry(cache, sorted[n-1].Name, offset); err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if err := saveCache(cache); err != nil {
+			printError("保存缓存失败 - %v", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// ProcessInfo 一个正在运行的进程
+type ProcessInfo struct {
+	PID     uint32
+	Name    string
+	ExePath string // 无权查询的进程（如系统进程）为空
+}
+
+// ImportFromRunningProcesses 列出当前正在运行的进程及其程序路径
+func ImportFromRunningProcesses() ([]ProcessInfo, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("获取进程列表失败: %v", err)
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	if err := windows.Process32First(snapshot, &entry); err != nil {
+		return nil, fmt.Errorf("获取进程列表失败: %v", err)
+	}
+
+	var processes []ProcessInfo
+	for {
+		processes = append(processes, ProcessInfo{
+			PID:     entry.ProcessID,
+			Name:    windows.UTF16ToString(entry.ExeFile[:]),
+			ExePath: processImagePath(entry.ProcessID),
+		})
+		if err := windows.Process32Next(snapshot, &entry); err != nil {
+			break
+		}
+	}
+	return processes, nil
+}
+
+// IsRunning 检查是否有名为 processName 的进程正在运行（不区分大小写，不含路径），返回其中一个进程的 PID
+func IsRunning(processName string) (bool, int, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return false, 0, fmt.Errorf("获取进程列表失败: %v", err)
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	if err := windows.Process32First(snapshot, &entry); err != nil {
+		return false, 0, fmt.Errorf("获取进程列表失败: %v", err)
+	}
+
+	for {
+		if strings.EqualFold(windows.UTF16ToString(entry.ExeFile[:]), processName) {
+			return true, int(entry.ProcessID), nil
+		}
+		if err := windows.Process32Next(snapshot, &entry); err != nil {
+			return false, 0, nil
+		}
+	}
+}
+
+// entryProcessName 获取启动项启动的程序的进程名（不含路径），窗口状态包装的命令返回被包装的程序
+func entryProcessName(item CacheItem) string {
+	exePath, _, err := parseCommandPath(unwrapWindowState(item.Value, item.Name, item.WindowState))
+	if err != nil {
+		return ""
+	}
+	name := filepath.Base(exePath)
+	if filepath.Ext(name) == "" {
+		name += ".exe"
+	}
+	return name
+}
+
+// processImagePath 获取进程的程序路径，无权访问时返回空字符串
+func processImagePath(pid uint32) string {
+	path, _ := GetProcessPath(pid)
+	return path
+}
+
+// GetProcessPath 获取进程的程序文件的完整路径
+func GetProcessPath(pid uint32) (string, error) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	switch {
+	case err == windows.ERROR_INVALID_PARAMETER:
+		return "", fmt.Errorf("进程 %d 不存在", pid)
+	case err == windows.ERROR_ACCESS_DENIED:
+		return "", fmt.Errorf("无权访问进程 %d，可能需要以管理员身份运行", pid)
+	case err != nil:
+		return "", fmt.Errorf("打开进程 %d 失败: %v", pid, err)
+	}
+	defer windows.CloseHandle(handle)
+
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(handle, 0, &buf[0], &size); err != nil {
+		return "", fmt.Errorf("获取进程 %d 的程序路径失败: %v", pid, err)
+	}
+	return windows.UTF16ToString(buf[:size]), nil
+}
+
+// runAddFromProcess 将正在运行的进程 pid 的程序添加到自启动
+func runAddFromProcess(pid uint32, name string, force bool) error {
+	exePath, err := GetProcessPath(pid)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("进程 %d 的程序: %s\n", pid, exePath)
+	return runAddProgram(exePath, name, "", "", force)
+}
+
+// isSystemProcess 检查进程是否属于 Windows 自身，这类进程不适合加入自启动
+func isSystemProcess(p ProcessInfo) bool {
+	if p.ExePath == "" {
+		return true
+	}
+	windir, err := windows.GetWindowsDirectory()
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(p.ExePath), strings.ToLower(windir)+`\`)
+}
+
+// handleDiscoverProcesses 处理从正在运行的进程中选择程序添加到自启动
+func handleDiscoverProcesses() {
+	processes, err := ImportFromRunningProcesses()
+	if err != nil {
+		printError("%v", err)
+		return
+	}
+
+	// 同一程序的多个进程只列出一次
+	seen := make(map[string]bool)
+	var candidates []ProcessInfo
+	for _, p := range processes {
+		key := strings.ToLower(p.ExePath)
+		if isSystemProcess(p) || seen[key] {
+			continue
+		}
+		seen[key] = true
+		candidates = append(candidates, p)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return strings.ToLower(candidates[i].Name) < strings.ToLower(candidates[j].Name)
+	})
+
+	items := make([]ListItem, 0, len(candidates))
+	for _, p := range candidates {
+		items = append(items, ListItem{Name: fmt.Sprintf("%s (PID %d)", p.Name, p.PID), Value: p.ExePath})
+	}
+
+	indices, ok := showMultiSelectWithBack(items, "正在运行的程序", pageSize)
+	if !ok {
+		return
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	added, duplicates, failed := 0, 0, 0
+	for _, idx := range indices {
+		p := candidates[idx]
+		appName := getAppName(p.ExePath)
+		if name, ok := registeredName(cache, appName, p.ExePath); ok {
+			fmt.Printf("跳过 %s：已在自启动中（%s）\n", p.Name, name)
+			duplicates++
+			continue
+		}
+
+		undo := newUndoRecord(OpAdd, appName)
+		if err := AddToStartupWithArgs(p.ExePath, appName, ""); err != nil {
+			fmt.Printf("添加 %s 失败: %v\n", appName, err)
+			failed++
+			continue
+		}
+
+		value, _ := startupCommand(p.ExePath, "")
+		item := addOrUpdateItem(cache, appName, value, true)
+		recordHistory(item, HistoryAdded, "", value)
+		item.Type = startupFileType(p.ExePath)
+		refreshFileModTime(item)
+		if hash, err := computeFileHash(p.ExePath); err == nil {
+			item.FileHash = hash
+		}
+		pushUndo(undo)
+		fmt.Printf("已添加 %s\n", appName)
+		added++
+	}
+
+	if err := saveCache(cache); err != nil {
+		printError("保存缓存失败 - %v", err)
+	}
+	fmt.Printf("\n已添加 %d 项，%d 项已存在，失败 %d 项。\n", added, duplicates, failed)
+}
+
+// registeredName 检查程序是否已经在自启动中（名称相同或启动的是同一个文件），返回已有启动项的名称
+func registeredName(cache *CacheData, appName, exePath string) (string, bool) {
+	for _, item := range cache.Items {
+		if item.Name == appName {
+			return item.Name, true
+		}
+		if path, err := entryFilePath(item); err == nil && strings.EqualFold(path, exePath) {
+			return item.Name, true
+		}
+	}
+	return "", false
+}
+
+// ErrNoEntryProcess 没有找到启动项对应的正在运行的进程
+var ErrNoEntryProcess = errors.New("没有找到正在运行的进程")
+
+// 结束进程前等待其正常退出的默认时间
+const defaultKillTimeout = 5 * time.Second
+
+// FindEntryProcesses 查找程序路径与启动项相同的正在运行的进程
+// 通过 cmd、powershell 等解释器运行脚本的启动项无法区分对应的进程，返回错误
+func FindEntryProcesses(item CacheItem) ([]ProcessInfo, error) {
+	exePath, _, err := entryCommandLine(item)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveExecutable(exePath)
+	if err != nil {
+		return nil, fmt.Errorf("程序文件不存在: %s", exePath)
+	}
+	base := strings.ToLower(filepath.Base(resolved))
+	if knownInterpreters[strings.TrimSuffix(base, filepath.Ext(base))] {
+		return nil, fmt.Errorf("启动项通过 %s 运行，无法确定对应的进程", filepath.Base(resolved))
+	}
+
+	processes, err := ImportFromRunningProcesses()
+	if err != nil {
+		return nil, err
+	}
+	var matched []ProcessInfo
+	for _, p := range processes {
+		if p.ExePath != "" && strings.EqualFold(filepath.Clean(p.ExePath), filepath.Clean(resolved)) {
+			matched = append(matched, p)
+		}
+	}
+	return matched, nil
+}
+
+// TerminateEntryProcess 结束进程：先请求其正常退出，timeout 内没有退出时强制结束
+// timeout 为 0 时直接强制结束；返回是否是强制结束的
+func TerminateEntryProcess(pid uint32, timeout time.Duration) (bool, error) {
+	if dryRunf("结束进程 %d", pid) {
+		return false, nil
+	}
+
+	handle, err := windows.OpenProcess(windows.PROCESS_TERMINATE|windows.SYNCHRONIZE, false, pid)
+	if err != nil {
+		return false, fmt.Errorf("打开进程 %d 失败: %v", pid, err)
+	}
+	defer windows.CloseHandle(handle)
+
+	// 不带 /F 的 taskkill 向进程的窗口发送关闭消息；没有窗口的程序会失败，直接强制结束
+	if timeout > 0 && exec.Command("taskkill.exe", "/PID", strconv.FormatUint(uint64(pid), 10)).Run() == nil {
+		event, err := windows.WaitForSingleObject(handle, uint32(timeout.Milliseconds()))
+		if err == nil && event == windows.WAIT_OBJECT_0 {
+			return false, nil
+		}
+	}
+
+	if err := windows.TerminateProcess(handle, 1); err != nil {
+		return true, fmt.Errorf("结束进程 %d 失败: %v", pid, err)
+	}
+	return true, nil
+}
+
+// killEntryProcesses 结束启动项对应的所有进程，confirm 为 true 时先逐一列出并确认
+func killEntryProcesses(item CacheItem, confirm bool, timeout time.Duration) error {
+	processes, err := FindEntryProcesses(item)
+	if err != nil {
+		return err
+	}
+	if len(processes) == 0 {
+		return fmt.Errorf("%w: %s", ErrNoEntryProcess, item.Name)
+	}
+
+	fmt.Printf("启动项 %s 正在运行的进程：\n", item.Name)
+	for _, p := range processes {
+		fmt.Printf("  PID %d  %s\n", p.PID, p.ExePath)
+	}
+	if confirm && !confirmYes(fmt.Sprintf("确认结束这 %d 个进程？(y/n): ", len(processes))) {
+		fmt.Println("已取消。")
+		return nil
+	}
+
+	var firstErr error
+	for _, p := range processes {
+		forced, err := TerminateEntryProcess(p.PID, timeout)
+		switch {
+		case err != nil:
+			printError("%v", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		case forced:
+			fmt.Printf("已强制结束进程 %d\n", p.PID)
+		default:
+			fmt.Printf("进程 %d 已正常退出\n", p.PID)
+		}
+	}
+	return firstErr
+}
+
+// runKill 命令行模式下结束启动项的进程，force 为 true 时跳过确认
+func runKill(name string, force bool, timeout time.Duration) error {
+	item, err := findEntry(name)
+	if err != nil {
+		return err
+	}
+	return killEntryProcesses(item, !force, timeout)
+}
+
+// handleKillEntry 处理结束启动项正在运行的进程
+func handleKillEntry() {
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	items := make([]ListItem, 0, len(cache.Items))
+	for _, item := range cache.Items {
+		items = append(items, ListItem{Name: item.Name, Value: item.Value})
+	}
+	idx, ok := showListWithBack(items, "选择要结束进程的启动项", pageSize)
+	if !ok {
+		return
+	}
+
+	if err := killEntryProcesses(cache.Items[idx], true, defaultKillTimeout); err != nil {
+		printError("%v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 当前使用的配置方案，空字符串表示默认配置（autostart.json）
+var currentProfile string
+
+// currentProfileFile 记录当前配置方案的文件，与缓存文件位于同一目录
+func currentProfileFile() string {
+	return filepath.Join(filepath.Dir(cacheFilePath), "autostart_current.json")
+}
+
+// profileCachePath 获取配置方案对应的缓存文件路径
+func profileCachePath(name string) string {
+	if name == "" {
+		return filepath.Join(filepath.Dir(cacheFilePath), "autostart."+cacheFormat)
+	}
+	return filepath.Join(filepath.Dir(cacheFilePath), "autostart_"+name+"."+cacheFormat)
+}
+
+// profileLabel 配置方案的显示名称
+func profileLabel(name string) string {
+	if name == "" {
+		return "默认"
+	}
+	return name
+}
+
+// validateProfileName 检查配置方案名称能否用作文件名
+func validateProfileName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if name == "current" || strings.ContainsAny(name, `\/:*?"<>|`) {
+		return fmt.Errorf("无效的配置方案名称: %s", name)
+	}
+	return nil
+}
+
+// loadCurrentProfile 读取上次切换到的配置方案，文件不存在或无效时返回默认配置
+func loadCurrentProfile() string {
+	data, err := os.ReadFile(currentProfileFile())
+	if err != nil {
+		return ""
+	}
+
+	var current struct {
+		Profile string `json:"profile"`
+	}
+	if err := json.Unmarshal(data, &current); err != nil || validateProfileName(current.Profile) != nil {
+		return ""
+	}
+	return current.Profile
+}
+
+// saveCurrentProfile 记录当前配置方案，下次启动时使用
+func saveCurrentProfile(name string) error {
+	data, err := json.MarshalIndent(struct {
+		Profile string `json:"profile"`
+	}{name}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dryRunf("记录当前配置方案 %s", profileLabel(name)) {
+		return nil
+	}
+	return os.WriteFile(currentProfileFile(), data, 0644)
+}
+
+// ListProfiles 列出所有配置方案，默认配置（空字符串）始终排在第一位
+func ListProfiles() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(cacheFilePath), "autostart_*."+cacheFormat))
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []string{""}
+	for _, match := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), "autostart_"), "."+cacheFormat)
+		if name == "current" || name == "" {
+			continue
+		}
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles[1:])
+	return profiles, nil
+}
+
+// SwitchProfile 切换到指定的配置方案
+// 禁用注册表中不属于目标方案的启动项（不在目标方案中的项以禁用状态加入目标方案，
+// 以便之后重新启用），再启用目标方案中所有已启用的项
+func SwitchProfile(profileName string) error {
+	if err := validateProfileName(profileName); err != nil {
+		return err
+	}
+
+	path := profileCachePath(profileName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fmt.Errorf("配置方案不存在: %s", profileLabel(profileName))
+	}
+	target, err := loadCacheFile(path)
+	if err != nil {
+		return fmt.Errorf("加载配置方案失败: %v", err)
+	}
+
+	current, err := startupRegistry.ReadValues(runKeyPath)
+	if err != nil {
+		return fmt.Errorf("打开注册表失败: %v", err)
+	}
+
+	tx := Begin()
+	for name, value := range current {
+		if idx, item := findItemByName(target, name); idx >= 0 && item.Enabled {
+			continue
+		}
+
+		name := name
+		tx.AddEntryOperation(name, func() error {
+			return RemoveFromStartup(name)
+		})
+		if idx, _ := findItemByName(target, name); idx < 0 {
+			addOrUpdateItem(target, name, value, false)
+		}
+	}
+	for _, item := range target.Items {
+		if !item.Enabled {
+			continue
+		}
+		item := item
+		tx.AddEntryOperation(item.Name, func() error {
+			return registerStartupItem(item)
+		})
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	cacheFilePath = path
+	currentProfile = profileName
+	if err := saveCache(target); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	if err := saveCurrentProfile(profileName); err != nil {
+		return fmt.Errorf("保存当前配置方案失败: %v", err)
+	}
+
+	// 撤销记录属于切换前的配置方案
+	undoStack = nil
+	markOrphansStale()
+	return nil
+}
+
+// handleSwitchProfile 处理切换配置方案
+func handleSwitchProfile() {
+	profiles, err := ListProfiles()
+	if err != nil {
+		fmt.Printf("读取配置方案失败: %v\n", err)
+		return
+	}
+
+	items := make([]ListItem, 0, len(profiles))
+	for _, profile := range profiles {
+		value := filepath.Base(profileCachePath(profile))
+		if profile == currentProfile {
+			value += "（当前）"
+		}
+		items = append(items, ListItem{Name: profileLabel(profile), Value: value})
+	}
+
+	fmt.Println("\n提示: 使用 --profile=<名称> 启动可创建新的配置方案。")
+	idx, ok := showListWithBack(items, "配置方案列表", pageSize)
+	if !ok {
+		return
+	}
+
+	selected := profiles[idx]
+	if selected == currentProfile {
+		fmt.Println("已经是当前配置方案。")
+		return
+	}
+
+	fmt.Printf("\n切换到 %s 后，不属于该方案的启动项将被禁用。\n", profileLabel(selected))
+	if !confirmYes("确定要切换吗？(y/n): ") {
+		return
+	}
+
+	if err := SwitchProfile(selected); err != nil {
+		printError("切换失败 - %v", err)
+		return
+	}
+	fmt.Printf("已切换到配置方案 %s！\n", profileLabel(selected))
+}
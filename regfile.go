@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+const (
+	// .reg 文件头
+	regFileHeader = "Windows Registry Editor Version 5.00"
+
+	// .reg 文件中启动项所在的注册表键
+	regRunKey = `HKEY_CURRENT_USER\` + runKeyPath
+)
+
+// ExportToRegFile 将启动项导出为 .reg 文件
+// 已禁用的项以注释形式写入，导入注册表时不会生效
+func ExportToRegFile(path string, items []CacheItem) error {
+	if dryRunf("导出 %d 项到 %s", len(items), path) {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(regFileHeader + "\r\n\r\n")
+	sb.WriteString("[" + regRunKey + "]\r\n")
+
+	for _, item := range items {
+		line := fmt.Sprintf(`"%s"="%s"`, escapeRegString(item.Name), escapeRegString(item.Value))
+		if !item.Enabled {
+			line = "; 已禁用: " + line
+		}
+		sb.WriteString(line + "\r\n")
+	}
+	sb.WriteString("\r\n")
+
+	// 注册表编辑器导出的 .reg 文件使用带 BOM 的 UTF-16LE 编码
+	if err := os.WriteFile(path, encodeUTF16LE(sb.String()), 0644); err != nil {
+		return fmt.Errorf("写入文件失败: %v", err)
+	}
+	return nil
+}
+
+// encodeUTF16LE 将字符串编码为带 BOM 的 UTF-16LE
+func encodeUTF16LE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	buf := make([]byte, 2, 2+len(units)*2)
+	buf[0], buf[1] = 0xFF, 0xFE
+	for _, u := range units {
+		buf = append(buf, byte(u), byte(u>>8))
+	}
+	return buf
+}
+
+// ParseError .reg 文件格式错误，Line 从 1 开始
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("第 %d 行: %s", e.Line, e.Message)
+}
+
+// regValue .reg 文件中的一个值
+type regValue struct {
+	Name   string // 默认值（@）为空字符串
+	Type   uint32 // registry.SZ、registry.EXPAND_SZ、registry.DWORD 或 registry.BINARY 等
+	Data   string // 字符串类型为内容，DWORD 为十进制数值，其他类型为空
+	Delete bool   // "name"=- 表示删除该值
+}
+
+// regSection .reg 文件中的一个键及其下的值
+type regSection struct {
+	Key    string
+	Delete bool // [-KEY] 表示删除整个键
+	Values []regValue
+}
+
+// ImportFromRegFile 从 .reg 文件中读取启动项
+// 只读取 Run 键下的 REG_SZ 和 REG_EXPAND_SZ 值；"name"=- 形式的删除项以禁用状态返回，Value 为空
+func ImportFromRegFile(path string) ([]CacheItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+
+	sections, err := parseRegFile(decodeRegFile(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var items []CacheItem
+	for _, section := range sections {
+		if section.Delete || !strings.EqualFold(section.Key, regRunKey) {
+			continue
+		}
+		for _, v := range section.Values {
+			if v.Name == "" {
+				continue
+			}
+			switch {
+			case v.Delete:
+				items = append(items, CacheItem{Name: v.Name, Enabled: false})
+			case v.Type == registry.SZ || v.Type == registry.EXPAND_SZ:
+				items = append(items, CacheItem{Name: v.Name, Value: v.Data, Enabled: true})
+			}
+		}
+	}
+	return items, nil
+}
+
+// parseRegFile 解析 .reg 文件内容
+// 支持 REGEDIT4 和 Windows Registry Editor Version 5.00 两种格式
+func parseRegFile(text string) ([]regSection, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var sections []regSection
+	var current *regSection
+	ansi := false
+	headerSeen := false
+
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+
+		// 以反斜杠结尾的行与下一行合并（长的 hex 值会分多行写）
+		for strings.HasSuffix(line, `\`) && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, `\`) + strings.TrimSpace(lines[i])
+		}
+
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if !headerSeen {
+			switch line {
+			case regFileHeader:
+			case "REGEDIT4":
+				ansi = true
+			default:
+				return nil, &ParseError{Line: lineNo, Message: "不是有效的注册表文件，缺少文件头"}
+			}
+			headerSeen = true
+			continue
+		}
+
+		// 键名行
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, &ParseError{Line: lineNo, Message: "键名缺少右括号"}
+			}
+			key := line[1 : len(line)-1]
+			section := regSection{Key: key}
+			if strings.HasPrefix(key, "-") {
+				section.Key = key[1:]
+				section.Delete = true
+			}
+			if section.Key == "" {
+				return nil, &ParseError{Line: lineNo, Message: "键名为空"}
+			}
+			sections = append(sections, section)
+			current = &sections[len(sections)-1]
+			continue
+		}
+
+		if current == nil {
+			return nil, &ParseError{Line: lineNo, Message: "值不属于任何键"}
+		}
+
+		v, err := parseRegValueLine(line, ansi)
+		if err != nil {
+			return nil, &ParseError{Line: lineNo, Message: err.Error()}
+		}
+		// 被删除的键下的值没有意义，与注册表编辑器一样忽略
+		if !current.Delete {
+			current.Values = append(current.Values, v)
+		}
+	}
+
+	if !headerSeen {
+		return nil, &ParseError{Line: 1, Message: "文件为空"}
+	}
+	return sections, nil
+}
+
+// parseRegValueLine 解析 "name"=data 或 @=data 形式的值行
+// ansi 为 true 时（REGEDIT4）hex(2) 的内容按 ANSI 编码解码，否则按 UTF-16LE
+func parseRegValueLine(line string, ansi bool) (regValue, error) {
+	var v regValue
+	var rest string
+
+	switch {
+	case strings.HasPrefix(line, "@"):
+		rest = strings.TrimSpace(line[1:])
+	case strings.HasPrefix(line, `"`):
+		name, r, err := readRegQuoted(line)
+		if err != nil {
+			return v, err
+		}
+		v.Name, rest = name, r
+	default:
+		return v, fmt.Errorf("无法识别的行: %s", line)
+	}
+
+	if !strings.HasPrefix(rest, "=") {
+		return v, fmt.Errorf("值名称后缺少 =")
+	}
+	data := strings.TrimSpace(rest[1:])
+
+	switch {
+	case data == "-":
+		v.Delete = true
+
+	case strings.HasPrefix(data, `"`):
+		s, r, err := readRegQuoted(data)
+		if err != nil {
+			return v, err
+		}
+		if r != "" {
+			return v, fmt.Errorf("字符串后有多余内容: %s", r)
+		}
+		v.Type, v.Data = registry.SZ, s
+
+	case strings.HasPrefix(strings.ToLower(data), "dword:"):
+		n, err := strconv.ParseUint(data[len("dword:"):], 16, 32)
+		if err != nil || len(data) != len("dword:")+8 {
+			return v, fmt.Errorf("无效的 DWORD 值: %s", data)
+		}
+		v.Type, v.Data = registry.DWORD, strconv.FormatUint(n, 10)
+
+	case strings.HasPrefix(strings.ToLower(data), "hex"):
+		typ, raw, err := parseRegHex(data)
+		if err != nil {
+			return v, err
+		}
+		v.Type = typ
+		if typ == registry.EXPAND_SZ {
+			if ansi {
+				v.Data = decodeANSI(bytes.TrimRight(raw, "\x00"))
+			} else {
+				v.Data = decodeUTF16LE(raw)
+			}
+		}
+
+	default:
+		return v, fmt.Errorf("无法识别的值类型: %s", data)
+	}
+	return v, nil
+}
+
+// parseRegHex 解析 hex:xx,xx 或 hex(n):xx,xx 形式的数据，返回值类型和原始字节
+func parseRegHex(data string) (uint32, []byte, error) {
+	colon := strings.Index(data, ":")
+	if colon < 0 {
+		return 0, nil, fmt.Errorf("无效的十六进制值: %s", data)
+	}
+
+	typ := uint32(registry.BINARY)
+	if prefix := strings.ToLower(data[:colon]); prefix != "hex" {
+		if !strings.HasPrefix(prefix, "hex(") || !strings.HasSuffix(prefix, ")") {
+			return 0, nil, fmt.Errorf("无效的十六进制值: %s", data)
+		}
+		n, err := strconv.ParseUint(prefix[4:len(prefix)-1], 16, 32)
+		if err != nil {
+			return 0, nil, fmt.Errorf("无效的值类型: %s", prefix)
+		}
+		typ = uint32(n)
+	}
+
+	var raw []byte
+	if body := strings.TrimSpace(data[colon+1:]); body != "" {
+		for _, part := range strings.Split(body, ",") {
+			b, err := strconv.ParseUint(strings.TrimSpace(part), 16, 8)
+			if err != nil {
+				return 0, nil, fmt.Errorf("无效的十六进制字节: %s", part)
+			}
+			raw = append(raw, byte(b))
+		}
+	}
+	return typ, raw, nil
+}
+
+// decodeRegFile 根据 BOM 将 .reg 文件内容解码为字符串
+func decodeRegFile(data []byte) string {
+	// UTF-16LE
+	if len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE {
+		return decodeUTF16LE(data[2:])
+	}
+
+	// UTF-8（带或不带 BOM）
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	// REGEDIT4 格式的文件使用系统的 ANSI 代码页
+	return decodeANSI(data)
+}
+
+// decodeUTF16LE 将 UTF-16LE 字节解码为字符串，去掉末尾的 NUL
+func decodeUTF16LE(data []byte) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])|uint16(data[i+1])<<8)
+	}
+	return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+}
+
+// decodeANSI 按系统 ANSI 代码页将字节解码为字符串
+func decodeANSI(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	n, err := windows.MultiByteToWideChar(windows.GetACP(), 0, &data[0], int32(len(data)), nil, 0)
+	if err != nil || n == 0 {
+		return string(data)
+	}
+	units := make([]uint16, n)
+	if _, err := windows.MultiByteToWideChar(windows.GetACP(), 0, &data[0], int32(len(data)), &units[0], n); err != nil {
+		return string(data)
+	}
+	return string(utf16.Decode(units))
+}
+
+// readRegQuoted 读取以引号开头的字符串，返回反转义后的内容和剩余部分
+func readRegQuoted(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", fmt.Errorf("缺少引号")
+	}
+
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 >= len(s) {
+				return "", "", fmt.Errorf("字符串以反斜杠结尾")
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				// \\ 和 \" 以及其他字符都按字面值处理
+				sb.WriteByte(s[i])
+			}
+		case '"':
+			return sb.String(), strings.TrimSpace(s[i+1:]), nil
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", "", fmt.Errorf("字符串缺少结束引号")
+}
+
+// escapeRegString 转义 .reg 文件中字符串的反斜杠、引号、换行和制表符，与 readRegQuoted 对应
+func escapeRegString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return strings.ReplaceAll(s, "\t", `\t`)
+}
@@ -0,0 +1,74 @@
+//go:build fuzz
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func FuzzParseRegFile(f *testing.F) {
+	seeds := []string{
+		// Windows Registry Editor Version 5.00
+		regFileHeader + "\r\n\r\n[" + regRunKey + "]\r\n" +
+			`"App"="\"C:\\Program Files\\App\\app.exe\" --min"` + "\r\n" +
+			`"Env"=hex(2):25,00,41,00,50,00,50,00,44,00,41,00,54,00,41,00,25,00,00,00` + "\r\n" +
+			`"Old"=-` + "\r\n" +
+			`@="default"` + "\r\n",
+		// REGEDIT4
+		"REGEDIT4\n\n[" + regRunKey + "]\n" +
+			`"Tool"="C:\\tools\\tool.exe"` + "\n" +
+			`"Count"=dword:0000000a` + "\n" +
+			`"Bin"=hex:01,02,\` + "\n  03,04\n",
+		// 删除键
+		regFileHeader + "\n\n[-HKEY_CURRENT_USER\\Software\\Old]\n\"x\"=\"y\"\n",
+		// 注释和空行
+		regFileHeader + "\n; 注释\n\n[HKEY_CURRENT_USER\\Software\\A]\n\n",
+		// 截断
+		regFileHeader + "\n[HKEY_CURRENT_USER\\Soft",
+		regFileHeader + "\n[" + regRunKey + "]\n\"App\"=\"C:\\app",
+		regFileHeader + "\n[" + regRunKey + "]\n\"App\"=hex(2):41,",
+		regFileHeader + "\n[" + regRunKey + "]\n\"App\"=\\",
+		"Windows Registry Editor",
+		// 格式错误
+		"",
+		"[HKEY_CURRENT_USER\\Software]\n",
+		regFileHeader + "\n\"App\"=\"x\"\n",
+		regFileHeader + "\n[]\n",
+		regFileHeader + "\n[-]\n",
+		regFileHeader + "\n[A]\nApp=x\n",
+		regFileHeader + "\n[A]\n\"App\"=dword:zz\n",
+		regFileHeader + "\n[A]\n\"App\"=hex()\n",
+		regFileHeader + "\n[A]\n\"App\"=hex(:00\n",
+		regFileHeader + "\n[A]\n\"App\"=\"x\" y\n",
+		regFileHeader + "\n[A]\n\"App\\\"=\"x\"\n",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		sections, err := parseRegFile(text)
+		if err != nil {
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("错误类型为 %T，want *ParseError: %v", err, err)
+			}
+			lines := strings.Count(text, "\n") + 1
+			if parseErr.Line < 1 || parseErr.Line > lines {
+				t.Fatalf("行号 %d 超出范围 [1, %d]", parseErr.Line, lines)
+			}
+			return
+		}
+
+		for _, section := range sections {
+			if section.Key == "" {
+				t.Fatal("解析结果中有空的键名")
+			}
+			if section.Delete && len(section.Values) > 0 {
+				t.Fatalf("被删除的键 %s 下不应有值", section.Key)
+			}
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+func TestParseRegFile(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []regSection
+	}{
+		{
+			name: "REGEDIT4 文件头",
+			text: "REGEDIT4\n\n[HKEY_CURRENT_USER\\Software\\A]\n\"App\"=\"C:\\\\a.exe\"\n",
+			want: []regSection{{Key: `HKEY_CURRENT_USER\Software\A`, Values: []regValue{
+				{Name: "App", Type: registry.SZ, Data: `C:\a.exe`},
+			}}},
+		},
+		{
+			name: "5.00 文件头和 CRLF 换行",
+			text: regFileHeader + "\r\n\r\n[HKEY_CURRENT_USER\\Software\\A]\r\n@=\"default\"\r\n\"App\"=\"x\"\r\n",
+			want: []regSection{{Key: `HKEY_CURRENT_USER\Software\A`, Values: []regValue{
+				{Name: "", Type: registry.SZ, Data: "default"},
+				{Name: "App", Type: registry.SZ, Data: "x"},
+			}}},
+		},
+		{
+			name: "删除键时忽略其下的值",
+			text: regFileHeader + "\n\n[-HKEY_CURRENT_USER\\Software\\Old]\n\"x\"=\"y\"\n\n[HKEY_CURRENT_USER\\Software\\New]\n",
+			want: []regSection{
+				{Key: `HKEY_CURRENT_USER\Software\Old`, Delete: true},
+				{Key: `HKEY_CURRENT_USER\Software\New`},
+			},
+		},
+		{
+			name: "删除值",
+			text: regFileHeader + "\n[A]\n\"Old\"=-\n",
+			want: []regSection{{Key: "A", Values: []regValue{{Name: "Old", Delete: true}}}},
+		},
+		{
+			name: "字符串转义",
+			text: regFileHeader + "\n[A]\n" +
+				`"Path"="\"C:\\Program Files\\App\\app.exe\" --min"` + "\n" +
+				`"Quote\"Name"="a\tb\nc"` + "\n",
+			want: []regSection{{Key: "A", Values: []regValue{
+				{Name: "Path", Type: registry.SZ, Data: `"C:\Program Files\App\app.exe" --min`},
+				{Name: `Quote"Name`, Type: registry.SZ, Data: "a\tb\nc"},
+			}}},
+		},
+		{
+			name: "DWORD 值",
+			text: regFileHeader + "\n[A]\n\"Zero\"=dword:00000000\n\"Ten\"=DWORD:0000000a\n\"Max\"=dword:ffffffff\n",
+			want: []regSection{{Key: "A", Values: []regValue{
+				{Name: "Zero", Type: registry.DWORD, Data: "0"},
+				{Name: "Ten", Type: registry.DWORD, Data: "10"},
+				{Name: "Max", Type: registry.DWORD, Data: "4294967295"},
+			}}},
+		},
+		{
+			name: "多行的 REG_EXPAND_SZ",
+			text: regFileHeader + "\n[A]\n\"Env\"=hex(2):25,00,54,00,4d,00,\\\n  50,00,25,00,00,00\n",
+			want: []regSection{{Key: "A", Values: []regValue{
+				{Name: "Env", Type: registry.EXPAND_SZ, Data: "%TMP%"},
+			}}},
+		},
+		{
+			name: "注释和空行",
+			text: regFileHeader + "\n; 注释\n\n[A]\n; \"x\"=\"y\"\n",
+			want: []regSection{{Key: "A"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRegFile(tt.text)
+			if err != nil {
+				t.Fatalf("parseRegFile: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRegFile =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRegFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantLine int
+	}{
+		{"空文件", "", 1},
+		{"缺少文件头", "[HKEY_CURRENT_USER\\Software\\A]\n", 1},
+		{"键名缺少右括号", regFileHeader + "\n\n[HKEY_CURRENT_USER\\Software\\A\n", 3},
+		{"键名为空", regFileHeader + "\n[-]\n", 2},
+		{"值不属于任何键", regFileHeader + "\n\"App\"=\"x\"\n", 2},
+		{"字符串缺少结束引号", regFileHeader + "\n[A]\n\"App\"=\"C:\\\\a.exe\n", 3},
+		{"无效的 DWORD", regFileHeader + "\n[A]\n\"App\"=dword:123\n", 3},
+		{"无效的十六进制字节", regFileHeader + "\n[A]\n\"App\"=hex:zz\n", 3},
+		{"无法识别的值类型", regFileHeader + "\n[A]\n\"App\"=qword:1\n", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseRegFile(tt.text)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("err = %v，want *ParseError", err)
+			}
+			if parseErr.Line != tt.wantLine {
+				t.Errorf("Line = %d, want %d（%v）", parseErr.Line, tt.wantLine, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import "golang.org/x/sys/windows/registry"
+
+// RegistryBackend 读写 HKCU 下注册表键的字符串值
+// 值不存在时 GetValue 和 DeleteValue 返回 registry.ErrNotExist；
+// 把 startupRegistry 换成内存中的实现即可在不修改真实注册表的情况下运行同步逻辑
+type RegistryBackend interface {
+	ReadValues(path string) (map[string]string, error)
+	GetValue(path, name string) (string, error)
+	SetValue(path, name, value string) error
+	DeleteValue(path, name string) error
+}
+
+// 启动项读写使用的注册表
+var startupRegistry RegistryBackend = currentUserRegistry{}
+
+// currentUserRegistry 读写真实的 HKEY_CURRENT_USER
+type currentUserRegistry struct{}
+
+func (currentUserRegistry) ReadValues(path string) (map[string]string, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, path, registry.QUERY_VALUE)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Close()
+
+	if _, err := key.ReadValueNames(0); err != nil {
+		return nil, err
+	}
+	return readKeyValues(key), nil
+}
+
+func (currentUserRegistry) GetValue(path, name string) (string, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, path, registry.QUERY_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue(name)
+	return value, err
+}
+
+func (currentUserRegistry) SetValue(path, name, value string) error {
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, path, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	return key.SetStringValue(name, value)
+}
+
+func (currentUserRegistry) DeleteValue(path, name string) error {
+	key, err := registry.OpenKey(registry.CURRENT_USER, path, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+
+	return key.DeleteValue(name)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// RiskLevel 启动项的风险等级，根据程序所在的位置估计
+type RiskLevel int
+
+const (
+	RiskLow RiskLevel = iota
+	RiskMedium
+	RiskHigh
+)
+
+// String 风险等级的显示名称
+func (l RiskLevel) String() string {
+	switch l {
+	case RiskMedium:
+		return "中风险"
+	case RiskHigh:
+		return "高风险"
+	}
+	return "低风险"
+}
+
+// parseRiskLevel 解析配置文件中的风险等级：low、medium 或 high
+func parseRiskLevel(s string) RiskLevel {
+	switch strings.ToLower(s) {
+	case "high":
+		return RiskHigh
+	case "medium":
+		return RiskMedium
+	}
+	return RiskLow
+}
+
+// RiskRule 一条风险判断规则，程序路径位于 Path 目录下时风险等级为 Level
+// Path 中可以使用 %VAR% 形式的环境变量
+type RiskRule struct {
+	Path           string `json:"path"`
+	Level          string `json:"level"`           // low、medium 或 high
+	WithoutVersion bool   `json:"without_version"` // 只匹配没有版本信息的程序
+}
+
+// defaultRiskRules 配置文件中没有 risk_rules 时使用的规则
+func defaultRiskRules() []RiskRule {
+	return []RiskRule{
+		{Path: `%TEMP%`, Level: "high"},
+		{Path: `%TMP%`, Level: "high"},
+		{Path: `%USERPROFILE%\Downloads`, Level: "high"},
+		{Path: `%APPDATA%`, Level: "medium", WithoutVersion: true},
+		{Path: `%ProgramFiles%`, Level: "low"},
+		{Path: `%ProgramFiles(x86)%`, Level: "low"},
+		{Path: `%SystemRoot%`, Level: "low"},
+	}
+}
+
+// riskRules 返回当前使用的风险规则
+func riskRules() []RiskRule {
+	if len(config.RiskRules) > 0 {
+		return config.RiskRules
+	}
+	return defaultRiskRules()
+}
+
+// AssessPathRisk 按风险规则估计程序的风险等级
+// 规则按顺序匹配，使用第一条匹配的规则；没有匹配的规则时为低风险
+func AssessPathRisk(path string) RiskLevel {
+	path = filepath.Clean(expandEnvPath(path))
+	for _, rule := range riskRules() {
+		dir := expandEnvPath(rule.Path)
+		// 未设置的环境变量保持原样，这类规则不参与匹配
+		if dir == "" || strings.Contains(dir, "%") {
+			continue
+		}
+		if !isUnderDir(path, filepath.Clean(dir)) {
+			continue
+		}
+		if rule.WithoutVersion {
+			if _, err := GetFileVersion(path); err == nil {
+				continue
+			}
+		}
+		return parseRiskLevel(rule.Level)
+	}
+	return RiskLow
+}
+
+// isUnderDir 判断 path 是否位于 dir 目录下（不区分大小写）
+func isUnderDir(path, dir string) bool {
+	path, dir = strings.ToLower(path), strings.ToLower(dir)
+	if !strings.HasSuffix(dir, `\`) {
+		dir += `\`
+	}
+	return strings.HasPrefix(path, dir)
+}
+
+// entryRisk 估计启动项的风险等级，无法确定程序路径时为低风险
+func entryRisk(item CacheItem) RiskLevel {
+	path, err := entryFilePath(item)
+	if err != nil {
+		return RiskLow
+	}
+	return AssessPathRisk(path)
+}
+
+// riskBadge 风险等级的标记及其颜色
+func riskBadge(level RiskLevel) (string, string) {
+	switch level {
+	case RiskHigh:
+		return "[" + level.String() + "]", colorRed
+	case RiskMedium:
+		return "[" + level.String() + "]", colorYellow
+	}
+	return "[" + level.String() + "]", colorGreen
+}
+
+// handleShowHighRisk 处理只显示高风险的启动项
+func handleShowHighRisk() {
+	showStartupStatusFiltered(statusFilter, "", false, false, true)
+}
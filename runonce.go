@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// RunOnce 键中的启动项在下次登录时运行一次，运行前由系统删除
+// HKCU 下的只对当前用户生效，HKLM 下的对所有用户生效，写入需要管理员权限
+const runOnceKeyPath = `Software\Microsoft\Windows\CurrentVersion\RunOnce`
+
+// ListRunOnceEntries 直接读取 scope 下 RunOnce 键中的启动项，不经过缓存
+func ListRunOnceEntries(scope RegistryScope) ([]CacheItem, error) {
+	key, err := registry.OpenKey(scope.rootKey(), runOnceKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		if err == registry.ErrNotExist {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("打开注册表失败: %v", err)
+	}
+	defer key.Close()
+
+	values := readKeyValues(key)
+	items := make([]CacheItem, 0, len(values))
+	for name, value := range values {
+		items = append(items, CacheItem{Name: name, Value: value, Enabled: true, Scope: scope, RunOnce: true})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+// AddToRunOnce 将命令写入 scope 下的 RunOnce 键，写入前规范化程序路径的引号
+func AddToRunOnce(command, appName string, scope RegistryScope) error {
+	command = normalizeRegistryValue(command)
+	if scope == ScopeMachineWide && !IsRunningAsAdmin() {
+		return fmt.Errorf("写入 %s 需要管理员权限", scope)
+	}
+	if dryRunf("设置注册表值 %s\\%s\\%s=%s", scope, runOnceKeyPath, appName, command) {
+		return nil
+	}
+
+	key, _, err := registry.CreateKey(scope.rootKey(), runOnceKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("打开注册表失败: %v", err)
+	}
+	defer key.Close()
+
+	if err := key.SetStringValue(appName, command); err != nil {
+		return fmt.Errorf("设置注册表值失败: %v", err)
+	}
+	auditEvent(EventAdd, "添加 RunOnce", appName)
+	return nil
+}
+
+// RemoveFromRunOnce 从 scope 下的 RunOnce 键中删除启动项，已运行过（不存在）时忽略
+func RemoveFromRunOnce(appName string, scope RegistryScope) error {
+	if scope == ScopeMachineWide && !IsRunningAsAdmin() {
+		return fmt.Errorf("修改 %s 需要管理员权限", scope)
+	}
+	if dryRunf("删除注册表值 %s\\%s\\%s", scope, runOnceKeyPath, appName) {
+		return nil
+	}
+
+	key, err := registry.OpenKey(scope.rootKey(), runOnceKeyPath, registry.SET_VALUE)
+	if err != nil {
+		if err == registry.ErrNotExist {
+			return nil
+		}
+		return fmt.Errorf("打开注册表失败: %v", err)
+	}
+	defer key.Close()
+
+	if err := key.DeleteValue(appName); err != nil && err != registry.ErrNotExist {
+		return fmt.Errorf("删除注册表值失败: %v", err)
+	}
+	auditEvent(EventRemove, "移除 RunOnce", appName)
+	return nil
+}
+
+// syncRunOnceItems 检查缓存中启用的 RunOnce 项是否仍在注册表中
+// RunOnce 项运行后被系统删除，此时在缓存中标记为禁用并记录历史，而不是删除，以便查看它已运行过
+func syncRunOnceItems(cache *CacheData) {
+	present := make(map[RegistryScope]map[string]bool)
+	for i := range cache.Items {
+		item := &cache.Items[i]
+		if !item.RunOnce || !item.Enabled {
+			continue
+		}
+
+		names, ok := present[item.Scope]
+		if !ok {
+			entries, err := ListRunOnceEntries(item.Scope)
+			if err != nil {
+				// 无法读取时保持原状态，避免误判为已运行
+				names = nil
+			} else {
+				names = make(map[string]bool, len(entries))
+				for _, entry := range entries {
+					names[entry.Name] = true
+				}
+			}
+			present[item.Scope] = names
+		}
+		if names == nil || names[item.Name] {
+			continue
+		}
+
+		item.Enabled = false
+		recordHistory(item, HistoryRunOnceFired, item.Value, "")
+	}
+}
+
+// handleRunOnce 查看和添加只运行一次的启动项（RunOnce）
+func handleRunOnce() {
+	for _, scope := range []RegistryScope{ScopeCurrentUser, ScopeMachineWide} {
+		items, err := ListRunOnceEntries(scope)
+		if err != nil {
+			printError("%v", err)
+			continue
+		}
+		fmt.Printf("\n=== RunOnce（%s） ===\n", scope)
+		if len(items) == 0 {
+			fmt.Println("没有等待运行的项。")
+		}
+		for i, item := range items {
+			fmt.Printf("%d. %s\n   %s\n", i+1, item.Name, item.Value)
+		}
+	}
+
+	if !confirmYes("\n是否添加一次性启动项？(y/n): ") {
+		return
+	}
+
+	scope := ScopeCurrentUser
+	if readInput("范围（1. 当前用户  2. 所有用户，默认 1）: ") == "2" {
+		scope = ScopeMachineWide
+		if !ensureAdmin() {
+			return
+		}
+	}
+
+	name := readInput("请输入启动项名称: ")
+	if name == "" {
+		return
+	}
+	command := readInput("请输入命令: ")
+	if command == "" {
+		return
+	}
+
+	syncIfStale()
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+	// RunOnce 项与 Run 键中的项共用缓存，名称不能重复
+	if FindDuplicateName(cache, name) {
+		fmt.Printf("名称 %s 已被其他启动项使用。\n", name)
+		return
+	}
+
+	if err := AddToRunOnce(command, name, scope); err != nil {
+		printError("添加失败 - %v", err)
+		return
+	}
+
+	item := addOrUpdateItem(cache, name, normalizeRegistryValue(command), true)
+	item.RunOnce = true
+	item.Scope = scope
+	recordHistory(item, HistoryAdded, "", item.Value)
+	if err := saveCache(cache); err != nil {
+		printError("保存缓存失败 - %v", err)
+		return
+	}
+	who := "当前用户"
+	if scope == ScopeMachineWide {
+		who = "任一用户"
+	}
+	fmt.Printf("已添加 %s，将在%s下次登录时运行一次。\n", name, who)
+}
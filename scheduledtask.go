@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TaskRunLevel 计划任务的运行级别
+type TaskRunLevel int
+
+const (
+	TaskRunLevelLimited TaskRunLevel = iota // 以普通权限运行
+	TaskRunLevelHighest                     // 以最高权限运行，创建时需要管理员权限
+)
+
+// schtasksArg 返回运行级别对应的 schtasks /RL 参数
+func (l TaskRunLevel) schtasksArg() string {
+	if l == TaskRunLevelHighest {
+		return "HIGHEST"
+	}
+	return "LIMITED"
+}
+
+// String 返回运行级别的显示名称
+func (l TaskRunLevel) String() string {
+	if l == TaskRunLevelHighest {
+		return "最高权限"
+	}
+	return "普通权限"
+}
+
+// ScheduledTaskEntry 一个在用户登录时触发的计划任务
+type ScheduledTaskEntry struct {
+	Name      string // 任务路径，如 \MyTask
+	Command   string
+	Arguments string
+	RunLevel  TaskRunLevel
+	Enabled   bool
+}
+
+// commandLine 返回计划任务的启动命令
+func (e ScheduledTaskEntry) commandLine() string {
+	return buildCommand(e.Command, e.Arguments)
+}
+
+// runSchtasks 运行 schtasks.exe，失败时返回其输出作为错误信息
+func runSchtasks(args ...string) ([]byte, error) {
+	output, err := exec.Command("schtasks.exe", args...).CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(decodeRegFile(output))
+		if message == "" {
+			message = err.Error()
+		}
+		return nil, fmt.Errorf("schtasks 执行失败: %s", message)
+	}
+	return output, nil
+}
+
+// AddToScheduledTask 创建在用户登录时运行程序的计划任务，已存在同名任务时覆盖
+func AddToScheduledTask(exePath, taskName, args string, runLevel TaskRunLevel) error {
+	absPath, err := filepath.Abs(exePath)
+	if err != nil {
+		return fmt.Errorf("获取绝对路径失败: %v", err)
+	}
+
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("文件不存在: %s", absPath)
+	}
+
+	command := buildCommand(absPath, args)
+	if dryRunf("创建计划任务 %s（登录时运行，%s）: %s", taskName, runLevel, command) {
+		return nil
+	}
+	_, err = runSchtasks("/Create", "/TN", taskName, "/TR", command,
+		"/SC", "ONLOGON", "/RL", runLevel.schtasksArg(), "/F")
+	return err
+}
+
+// RemoveScheduledTask 删除计划任务
+func RemoveScheduledTask(taskName string) error {
+	if dryRunf("删除计划任务 %s", taskName) {
+		return nil
+	}
+	_, err := runSchtasks("/Delete", "/TN", taskName, "/F")
+	return err
+}
+
+// taskXML 计划任务 XML 定义中用到的部分
+type taskXML struct {
+	Triggers struct {
+		Logon []struct{} `xml:"LogonTrigger"`
+	} `xml:"Triggers"`
+	Principals struct {
+		Principal struct {
+			RunLevel string `xml:"RunLevel"`
+		} `xml:"Principal"`
+	} `xml:"Principals"`
+	Settings struct {
+		Enabled *bool `xml:"Enabled"`
+	} `xml:"Settings"`
+	Actions struct {
+		Exec []struct {
+			Command   string `xml:"Command"`
+			Arguments string `xml:"Arguments"`
+		} `xml:"Exec"`
+	} `xml:"Actions"`
+}
+
+// ListLogonTasks 列出所有在用户登录时触发并运行程序的计划任务
+// 解析 schtasks /Query /XML 的输出，不受系统显示语言影响
+func ListLogonTasks() ([]ScheduledTaskEntry, error) {
+	output, err := runSchtasks("/Query", "/XML", "ONE")
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(decodeRegFile(output)))
+	// 输出已转换为 UTF-8，忽略声明中的 UTF-16
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	var entries []ScheduledTaskEntry
+	name := ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析计划任务失败: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.Comment:
+			// 每个任务定义之前有一行 <!-- \任务路径 --> 注释
+			name = strings.TrimSpace(string(t))
+		case xml.StartElement:
+			if t.Name.Local != "Task" {
+				continue
+			}
+			var task taskXML
+			if err := decoder.DecodeElement(&task, &t); err != nil {
+				return nil, fmt.Errorf("解析计划任务 %s 失败: %v", name, err)
+			}
+			if len(task.Triggers.Logon) == 0 || len(task.Actions.Exec) == 0 {
+				continue
+			}
+
+			entry := ScheduledTaskEntry{
+				Name:      name,
+				Command:   task.Actions.Exec[0].Command,
+				Arguments: task.Actions.Exec[0].Arguments,
+				Enabled:   task.Settings.Enabled == nil || *task.Settings.Enabled,
+			}
+			if task.Principals.Principal.RunLevel == "HighestAvailable" {
+				entry.RunLevel = TaskRunLevelHighest
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// getRunValue 读取 scope 下 Run 键中的值，不存在时返回 registry.ErrNotExist
+// 当前用户的 Run 键通过 startupRegistry 读写
+func getRunValue(scope RegistryScope, name string) (string, error) {
+	if scope == ScopeCurrentUser {
+		return startupRegistry.GetValue(runKeyPath, name)
+	}
+
+	key, err := registry.OpenKey(scope.rootKey(), runKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+	value, _, err := key.GetStringValue(name)
+	return value, err
+}
+
+// setRunValue 写入 scope 下 Run 键中的值
+func setRunValue(scope RegistryScope, name, value string) error {
+	if dryRunf("设置注册表值 %s\\%s\\%s=%s", scope, runKeyPath, name, value) {
+		return nil
+	}
+	var err error
+	if scope == ScopeCurrentUser {
+		err = startupRegistry.SetValue(runKeyPath, name, value)
+	} else {
+		var key registry.Key
+		if key, _, err = registry.CreateKey(scope.rootKey(), runKeyPath, registry.SET_VALUE); err != nil {
+			return fmt.Errorf("打开注册表失败: %v", err)
+		}
+		defer key.Close()
+		err = key.SetStringValue(name, value)
+	}
+	if err != nil {
+		return fmt.Errorf("设置注册表值失败: %v", err)
+	}
+	return nil
+}
+
+// deleteRunValue 删除 scope 下 Run 键中的值
+func deleteRunValue(scope RegistryScope, name string) error {
+	if dryRunf("删除注册表值 %s\\%s\\%s", scope, runKeyPath, name) {
+		return nil
+	}
+	var err error
+	if scope == ScopeCurrentUser {
+		err = startupRegistry.DeleteValue(runKeyPath, name)
+	} else {
+		var key registry.Key
+		if key, err = registry.OpenKey(scope.rootKey(), runKeyPath, registry.SET_VALUE); err != nil {
+			return fmt.Errorf("打开注册表失败: %v", err)
+		}
+		defer key.Close()
+		err = key.DeleteValue(name)
+	}
+	if err != nil {
+		return fmt.Errorf("删除注册表值失败: %v", err)
+	}
+	return nil
+}
+
+// scopeLabel 范围的简短显示名称
+func scopeLabel(scope RegistryScope) string {
+	if scope == ScopeMachineWide {
+		return "所有用户"
+	}
+	return "当前用户"
+}
+
+// removeFromRunKey 从 scope 下的 Run 键中删除启动项，当前用户的项同时删除生成的启动脚本
+func removeFromRunKey(name string, scope RegistryScope) error {
+	if scope != ScopeMachineWide {
+		return RemoveFromStartup(name)
+	}
+	if err := deleteRunValue(scope, name); err != nil {
+		return err
+	}
+	auditEvent(EventRemove, "移除", name)
+	return nil
+}
+
+// syncMachineItems 根据 HKLM 的 Run 键更新缓存中移到所有用户的项的启用状态
+func syncMachineItems(cache *CacheData) {
+	for i := range cache.Items {
+		item := &cache.Items[i]
+		if item.Scope != ScopeMachineWide || item.RunOnce {
+			continue
+		}
+		value, err := getRunValue(ScopeMachineWide, item.Name)
+		if err == nil {
+			item.Value = value
+			item.Enabled = true
+		} else if err == registry.ErrNotExist {
+			item.Enabled = false
+		}
+	}
+}
+
+// untrackedMachineItems 过滤掉已在缓存中记录为所有用户的项，这些项在状态列表中与其他缓存项一起显示
+func untrackedMachineItems(cache *CacheData, machineItems []CacheItem) []CacheItem {
+	var items []CacheItem
+	for _, item := range machineItems {
+		if _, cached := findItemByName(cache, item.Name); cached == nil || cached.Scope != ScopeMachineWide {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// MoveToScope 将 Run 键中的启动项移到 target 范围：先写入目标范围，成功后再从原范围删除
+// 修改 HKLM 需要管理员权限。缓存中的记录保留标签、备注和历史，只更新 Scope 并记录一条移动历史
+func MoveToScope(name string, target RegistryScope) error {
+	source := ScopeMachineWide
+	if target == ScopeMachineWide {
+		source = ScopeCurrentUser
+	}
+	if !IsRunningAsAdmin() {
+		return fmt.Errorf("修改 %s 需要管理员权限", ScopeMachineWide)
+	}
+
+	value, err := getRunValue(source, name)
+	if err == registry.ErrNotExist {
+		return fmt.Errorf("%w: %s 中没有 %s", ErrNotFound, source, name)
+	}
+	if err != nil {
+		return fmt.Errorf("读取注册表失败: %v", err)
+	}
+	if _, err := getRunValue(target, name); err == nil {
+		return fmt.Errorf("%s 中已有名为 %s 的启动项", target, name)
+	} else if err != registry.ErrNotExist {
+		return fmt.Errorf("读取注册表失败: %v", err)
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	// 启动脚本和隐藏窗口的 .vbs 保存在当前用户的缓存目录中，其他用户无法使用
+	if _, item := findItemByName(cache, name); item != nil && target == ScopeMachineWide &&
+		(hasStartWrapper(*item) || item.WindowState == windowStateHidden) {
+		return fmt.Errorf("%s 通过生成的启动脚本启动（延迟启动、工作目录或隐藏窗口），不能移到所有用户", name)
+	}
+
+	// 撤销时移回原范围；不在缓存中的项按读取到的值恢复
+	undo := newUndoRecord(OpMove, name)
+	if undo.Before == nil {
+		undo.Before = &CacheItem{Name: name, Value: value, Enabled: true}
+	}
+	undo.Before.Scope = source
+	if err := setRunValue(target, name, value); err != nil {
+		return err
+	}
+	if err := deleteRunValue(source, name); err != nil {
+		// 撤销写入，避免同一个程序在两个范围中各启动一次
+		if rbErr := deleteRunValue(target, name); rbErr != nil {
+			return fmt.Errorf("%v（撤销写入 %s 失败: %v）", err, target, rbErr)
+		}
+		return err
+	}
+
+	// 原来不在缓存中的项（如其他程序写入 HKLM 的项）按注册表中的值加入
+	idx, _ := findItemByName(cache, name)
+	if idx < 0 {
+		item := addOrUpdateItem(cache, name, value, true)
+		recordHistory(item, HistoryAdded, "", value)
+		idx, _ = findItemByName(cache, name)
+	}
+	item := &cache.Items[idx]
+	item.Scope = target
+	item.Enabled = true
+	recordHistory(item, HistoryMoved, source.String(), target.String())
+	if err := saveCache(cache); err != nil {
+		return fmt.Errorf("保存缓存失败: %v", err)
+	}
+	pushUndo(undo)
+	auditEvent(EventAdd, "移到"+scopeLabel(target), name)
+	return nil
+}
+
+// handleMoveScope 在当前用户和所有用户的 Run 键之间移动启动项
+func handleMoveScope() {
+	userItems, err := ListStartupEntries(ScopeCurrentUser)
+	if err != nil {
+		printError("读取注册表失败 - %v", err)
+		return
+	}
+	machineItems, _ := ListMachineStartupEntries()
+
+	entries := append(userItems, machineItems...)
+	items := make([]ListItem, 0, len(entries))
+	for _, item := range entries {
+		items = append(items, ListItem{Name: "[" + scopeLabel(item.Scope) + "] " + item.Name, Value: item.Value})
+	}
+	idx, ok := showListWithBack(items, "选择要移动的启动项", pageSize)
+	if !ok {
+		return
+	}
+
+	selected := entries[idx]
+	target := ScopeMachineWide
+	if selected.Scope == ScopeMachineWide {
+		target = ScopeCurrentUser
+	}
+	action := "移到" + scopeLabel(target)
+	if !confirmYes(fmt.Sprintf("将 %s %s（%s）？(y/n): ", selected.Name, action, target)) {
+		return
+	}
+	if !ensureAdmin() {
+		return
+	}
+	if err := MoveToScope(selected.Name, target); err != nil {
+		printError("移动失败 - %v", err)
+		return
+	}
+	fmt.Printf("已将 %s %s！\n", selected.Name, action)
+}
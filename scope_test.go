@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+func TestRunValueCurrentUser(t *testing.T) {
+	reg := setupTestEnv(t)
+
+	if err := setRunValue(ScopeCurrentUser, "App", `C:\app.exe`); err != nil {
+		t.Fatalf("setRunValue: %v", err)
+	}
+	if got, err := reg.GetValue(runKeyPath, "App"); err != nil || got != `C:\app.exe` {
+		t.Errorf("注册表中 App = %q (%v)", got, err)
+	}
+	if got, err := getRunValue(ScopeCurrentUser, "App"); err != nil || got != `C:\app.exe` {
+		t.Errorf("getRunValue = %q (%v)", got, err)
+	}
+
+	if err := deleteRunValue(ScopeCurrentUser, "App"); err != nil {
+		t.Fatalf("deleteRunValue: %v", err)
+	}
+	if _, err := getRunValue(ScopeCurrentUser, "App"); err != registry.ErrNotExist {
+		t.Errorf("删除后 getRunValue err = %v, want registry.ErrNotExist", err)
+	}
+	if err := deleteRunValue(ScopeCurrentUser, "App"); err == nil {
+		t.Error("删除不存在的值应返回错误")
+	}
+
+	dryRun = true
+	defer func() { dryRun = false }()
+	if err := setRunValue(ScopeMachineWide, "App", `C:\app.exe`); err != nil {
+		t.Errorf("试运行时 setRunValue 不应写入 HKLM: %v", err)
+	}
+}
+
+func TestUntrackedMachineItems(t *testing.T) {
+	cache := &CacheData{Items: []CacheItem{
+		{Name: "Moved", Value: `C:\moved.exe`, Scope: ScopeMachineWide},
+		{Name: "User", Value: `C:\user.exe`},
+	}}
+	machine := []CacheItem{
+		{Name: "Moved", Value: `C:\moved.exe`, Scope: ScopeMachineWide},
+		{Name: "User", Value: `C:\other.exe`, Scope: ScopeMachineWide},
+		{Name: "Vendor", Value: `C:\vendor.exe`, Scope: ScopeMachineWide},
+	}
+	got := untrackedMachineItems(cache, machine)
+	if len(got) != 2 || got[0].Name != "User" || got[1].Name != "Vendor" {
+		t.Errorf("untrackedMachineItems = %+v, want User 和 Vendor", got)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 匹配程度，数值越小越好
+const (
+	matchPrefix      = iota // 以查询内容开头
+	matchSubstring          // 包含查询内容
+	matchSubsequence        // 按顺序包含查询内容的所有字符
+	matchNone
+)
+
+// fuzzyMatch 判断 text 与 query 的匹配程度（不区分大小写）
+func fuzzyMatch(text, query string) int {
+	text, query = strings.ToLower(text), strings.ToLower(query)
+	switch {
+	case strings.HasPrefix(text, query):
+		return matchPrefix
+	case strings.Contains(text, query):
+		return matchSubstring
+	}
+
+	rest := []rune(query)
+	for _, r := range text {
+		if len(rest) > 0 && r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	if len(rest) == 0 {
+		return matchSubsequence
+	}
+	return matchNone
+}
+
+// SearchFuzzy 模糊搜索启动项，返回名称或启动命令与 query 匹配的项的下标
+// 按匹配程度排序：开头匹配优先于包含，包含优先于按顺序包含所有字符；名称匹配优先于命令匹配
+func SearchFuzzy(data *CacheData, query string) []int {
+	if query == "" {
+		return nil
+	}
+
+	type result struct {
+		index int
+		score int
+	}
+	var results []result
+	for i, item := range data.Items {
+		score := fuzzyMatch(item.Name, query)
+		if score == matchNone {
+			// 只有命令匹配的项排在所有名称匹配的项之后
+			valueScore := fuzzyMatch(item.Value, query)
+			if valueScore == matchNone {
+				continue
+			}
+			score = matchNone + valueScore
+		}
+		results = append(results, result{index: i, score: score})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score < results[j].score
+		}
+		return strings.ToLower(data.Items[results[i].index].Name) < strings.ToLower(data.Items[results[j].index].Name)
+	})
+
+	indices := make([]int, 0, len(results))
+	for _, r := range results {
+		indices = append(indices, r.index)
+	}
+	return indices
+}
+
+// compileSearchPattern 编译不区分大小写的正则表达式，空字符串匹配所有内容
+func compileSearchPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// SearchByRegex 按正则表达式搜索启动项，返回名称匹配 namePattern 且命令匹配 valuePattern 的项的下标
+// 两个表达式都不区分大小写，为空时匹配所有项
+func SearchByRegex(data *CacheData, namePattern, valuePattern string) ([]int, error) {
+	nameRe, err := compileSearchPattern(namePattern)
+	if err != nil {
+		return nil, fmt.Errorf("无效的名称表达式: %v", err)
+	}
+	valueRe, err := compileSearchPattern(valuePattern)
+	if err != nil {
+		return nil, fmt.Errorf("无效的命令表达式: %v", err)
+	}
+
+	var indices []int
+	for i, item := range data.Items {
+		if nameRe.MatchString(item.Name) && valueRe.MatchString(item.Value) {
+			indices = append(indices, i)
+		}
+	}
+	return indices, nil
+}
+
+// handleAdvancedSearch 处理按正则表达式搜索启动项
+func handleAdvancedSearch() {
+	namePattern := readInput("名称的正则表达式（留空匹配所有）: ")
+	valuePattern := readInput("命令的正则表达式（留空匹配所有）: ")
+
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	indices, err := SearchByRegex(cache, namePattern, valuePattern)
+	if err != nil {
+		printError("%v", err)
+		return
+	}
+	if len(indices) == 0 {
+		fmt.Println("没有找到匹配的启动项。")
+		return
+	}
+	showSearchResults(cache, indices, "高级搜索结果")
+}
+
+// handleSearch 处理搜索启动项
+func handleSearch() {
+	query := readInput("请输入要搜索的名称或路径（部分即可）: ")
+	if query == "" {
+		return
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	indices := SearchFuzzy(cache, query)
+	if len(indices) == 0 {
+		fmt.Println("没有找到匹配的启动项。")
+		return
+	}
+	showSearchResults(cache, indices, fmt.Sprintf("搜索结果：%s", query))
+}
+
+// showSearchResults 列出搜索到的启动项，选择后显示其详细信息
+func showSearchResults(cache *CacheData, indices []int, title string) {
+	items := make([]ListItem, 0, len(indices))
+	for _, i := range indices {
+		items = append(items, ListItem{
+			Name:  cache.Items[i].Name,
+			Value: cache.Items[i].Value,
+		})
+	}
+
+	idx, ok := showListWithBack(items, title, pageSize)
+	if !ok {
+		return
+	}
+
+	item := cache.Items[indices[idx]]
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Printf("%s ", item.Name)
+	printer.Print(statusChip(item.Enabled))
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("命令: %s\n", item.Value)
+	if len(item.Tags) > 0 {
+		fmt.Printf("标签: %s\n", strings.Join(item.Tags, ", "))
+	}
+	if item.Reason != "" {
+		fmt.Printf("原因: %s\n", item.Reason)
+	}
+	if item.Notes != "" {
+		fmt.Printf("备注: %s\n", item.Notes)
+	}
+	if !item.CreatedAt.IsZero() {
+		fmt.Printf("添加时间: %s\n", item.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HTTP 接口的路径前缀
+const apiEntriesPath = "/api/entries"
+
+// apiServer 通过 HTTP 提供启动项管理接口
+type apiServer struct {
+	manager *Manager
+	token   string // 修改操作所需的 Bearer 令牌
+}
+
+// runServer 在 addr 上启动 HTTP 接口，certFile 和 keyFile 都不为空时使用 TLS
+// token 为空时只允许只读请求
+func runServer(addr, token, certFile, keyFile string) error {
+	server := &apiServer{manager: NewManager(), token: token}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/health", server.handleHealth)
+	mux.HandleFunc(apiEntriesPath, server.handleEntries)
+	mux.HandleFunc(apiEntriesPath+"/", server.handleEntry)
+
+	if token == "" {
+		printer.Print("警告: 未设置 --api-token，添加、移除、启用和禁用请求都会被拒绝\n", colorYellow)
+	}
+
+	if certFile != "" && keyFile != "" {
+		fmt.Printf("正在 https://%s 上提供接口，按 Ctrl-C 退出...\n", addr)
+		return http.ListenAndServeTLS(addr, certFile, keyFile, mux)
+	}
+	fmt.Printf("正在 http://%s 上提供接口，按 Ctrl-C 退出...\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+// writeJSON 以 JSON 格式写入响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 以 {"error": "..."} 格式写入错误响应
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// errorStatus 返回错误对应的 HTTP 状态码
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrReadOnly):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// authorized 检查修改请求是否带有正确的 Bearer 令牌，失败时写入错误响应
+func (s *apiServer) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if s.token == "" {
+		writeError(w, http.StatusForbidden, fmt.Errorf("服务器未设置 API 令牌，不允许修改"))
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, fmt.Errorf("缺少或错误的 API 令牌"))
+		return false
+	}
+	return true
+}
+
+// handleHealth GET /api/health
+func (s *apiServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("不支持的方法: %s", r.Method))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleEntries GET /api/entries 列出启动项，POST /api/entries 添加启动项
+func (s *apiServer) handleEntries(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		items, err := s.manager.List()
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		if items == nil {
+			items = []CacheItem{}
+		}
+		writeJSON(w, http.StatusOK, items)
+
+	case http.MethodPost:
+		if !s.authorized(w, r) {
+			return
+		}
+		var item CacheItem
+		if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("请求格式错误: %v", err))
+			return
+		}
+		if item.Name == "" || item.Value == "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("name 和 value 不能为空"))
+			return
+		}
+		if err := s.manager.Add(item); err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		added, err := s.manager.Get(item.Name)
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, added)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("不支持的方法: %s", r.Method))
+	}
+}
+
+// handleEntry 处理单个启动项：
+// GET /api/entries/{name}、DELETE /api/entries/{name}、
+// PUT /api/entries/{name}/enable、PUT /api/entries/{name}/disable
+func (s *apiServer) handleEntry(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, apiEntriesPath+"/")
+	action := ""
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name, action = name[:i], name[i+1:]
+	}
+	if name == "" {
+		writeError(w, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	var err error
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		item, err := s.manager.Get(name)
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+		return
+	case action == "" && r.Method == http.MethodDelete:
+		if !s.authorized(w, r) {
+			return
+		}
+		err = s.manager.Remove(name)
+	case action == "enable" && r.Method == http.MethodPut:
+		if !s.authorized(w, r) {
+			return
+		}
+		err = s.manager.Enable(name)
+	case action == "disable" && r.Method == http.MethodPut:
+		if !s.authorized(w, r) {
+			return
+		}
+		err = s.manager.Disable(name, r.URL.Query().Get("reason"))
+	case action == "" || action == "enable" || action == "disable":
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("不支持的方法: %s", r.Method))
+		return
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("未知的操作: %s", action))
+		return
+	}
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
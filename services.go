@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// queryServiceConfig 以只读权限读取服务的配置，不需要管理员权限
+func queryServiceConfig(name string) (mgr.Config, error) {
+	scm, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_CONNECT)
+	if err != nil {
+		return mgr.Config{}, fmt.Errorf("连接服务管理器失败: %v", err)
+	}
+	defer windows.CloseServiceHandle(scm)
+
+	serviceName, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return mgr.Config{}, err
+	}
+	handle, err := windows.OpenService(scm, serviceName, windows.SERVICE_QUERY_CONFIG)
+	switch {
+	case err == windows.ERROR_SERVICE_DOES_NOT_EXIST:
+		return mgr.Config{}, fmt.Errorf("服务 %s 不存在", name)
+	case err == windows.ERROR_ACCESS_DENIED:
+		return mgr.Config{}, fmt.Errorf("无权读取服务 %s 的配置", name)
+	case err != nil:
+		return mgr.Config{}, fmt.Errorf("打开服务 %s 失败: %v", name, err)
+	}
+
+	service := &mgr.Service{Name: name, Handle: handle}
+	defer service.Close()
+
+	config, err := service.Config()
+	if err != nil {
+		return mgr.Config{}, fmt.Errorf("读取服务 %s 的配置失败: %v", name, err)
+	}
+	return config, nil
+}
+
+// GetServiceBinaryPath 返回服务的启动命令（BinaryPathName），可能带有参数
+func GetServiceBinaryPath(name string) (string, error) {
+	config, err := queryServiceConfig(name)
+	if err != nil {
+		return "", err
+	}
+	return config.BinaryPathName, nil
+}
+
+// runAddFromService 将服务的程序添加到 Run 键，entryName 为空时使用服务名
+// 显示服务的程序和启动方式后确认，force 为 true 时跳过确认
+func runAddFromService(name, entryName string, force bool) error {
+	config, err := queryServiceConfig(name)
+	if err != nil {
+		return err
+	}
+	exePath, args, err := parseCommandPath(config.BinaryPathName)
+	if err != nil {
+		return fmt.Errorf("无法解析服务的启动命令: %v", err)
+	}
+	if entryName == "" {
+		entryName = name
+	}
+
+	fmt.Printf("服务: %s（%s）\n", name, config.DisplayName)
+	fmt.Printf("程序路径: %s\n", exePath)
+	if args != "" {
+		fmt.Printf("启动参数: %s\n", args)
+	}
+	if config.StartType == mgr.StartAutomatic {
+		printer.Print(fmt.Sprintf("警告: 服务 %s 已设置为自动启动，不添加到 Run 键也会随系统启动\n", name), colorYellow)
+	}
+	if !force && !confirmYes(fmt.Sprintf("确认将其作为启动项 %s 添加到 Run 键？(y/n): ", entryName)) {
+		fmt.Println("已取消。")
+		return nil
+	}
+	return runAddProgram(exePath, entryName, args, "", force)
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	ole32                = windows.NewLazySystemDLL("ole32.dll")
+	procCoCreateInstance = ole32.NewProc("CoCreateInstance")
+)
+
+var (
+	clsidShellLink  = windows.GUID{Data1: 0x00021401, Data4: [8]byte{0xC0, 0, 0, 0, 0, 0, 0, 0x46}}
+	iidIShellLinkW  = windows.GUID{Data1: 0x000214F9, Data4: [8]byte{0xC0, 0, 0, 0, 0, 0, 0, 0x46}}
+	iidIPersistFile = windows.GUID{Data1: 0x0000010B, Data4: [8]byte{0xC0, 0, 0, 0, 0, 0, 0, 0x46}}
+)
+
+const (
+	clsctxInprocServer = 0x1
+	stgmRead           = 0x0
+
+	// CoInitializeEx 在当前线程已初始化时返回 S_FALSE
+	sFalse = syscall.Errno(1)
+	// 当前线程已用其他并发模型初始化
+	rpcEChangedMode = syscall.Errno(0x80010106)
+)
+
+// IUnknown 方法在虚函数表中的序号
+const (
+	methodQueryInterface = 0
+	methodRelease        = 2
+)
+
+// IShellLinkW 方法在虚函数表中的序号
+const (
+	shellLinkGetPath             = 3
+	shellLinkGetDescription      = 6
+	shellLinkSetDescription      = 7
+	shellLinkGetWorkingDirectory = 8
+	shellLinkSetWorkingDirectory = 9
+	shellLinkGetArguments        = 10
+	shellLinkSetArguments        = 11
+	shellLinkGetIconLocation     = 16
+	shellLinkSetIconLocation     = 17
+	shellLinkSetPath             = 20
+)
+
+// IPersistFile 方法在虚函数表中的序号
+const (
+	persistFileLoad = 5
+	persistFileSave = 6
+)
+
+// ShortcutInfo 快捷方式（.lnk）文件的属性
+type ShortcutInfo struct {
+	TargetPath       string
+	Arguments        string
+	WorkingDirectory string
+	Description      string
+	IconPath         string
+}
+
+// comObject COM 接口指针，第一个字段指向虚函数表
+type comObject struct {
+	vtbl *[32]uintptr
+}
+
+// call 调用虚函数表中第 method 个方法
+func (o *comObject) call(method int, args ...uintptr) error {
+	args = append([]uintptr{uintptr(unsafe.Pointer(o))}, args...)
+	hr, _, _ := syscall.SyscallN(o.vtbl[method], args...)
+	if int32(hr) < 0 {
+		return fmt.Errorf("COM 调用失败: HRESULT 0x%08X", uint32(hr))
+	}
+	return nil
+}
+
+// release 释放 COM 接口
+func (o *comObject) release() {
+	if o != nil {
+		o.call(methodRelease)
+	}
+}
+
+// withShellLink 创建 IShellLinkW 和 IPersistFile 接口并调用 fn
+// 无论 fn 是否成功，接口都会被释放
+func withShellLink(fn func(link, persist *comObject) error) error {
+	// COM 套间与线程绑定
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	err := windows.CoInitializeEx(0, windows.COINIT_APARTMENTTHREADED)
+	switch err {
+	case nil, sFalse:
+		defer windows.CoUninitialize()
+	case rpcEChangedMode:
+		// 已经以其他模式初始化，无需也不能再次反初始化
+	default:
+		return fmt.Errorf("初始化 COM 失败: %v", err)
+	}
+
+	var link *comObject
+	hr, _, _ := procCoCreateInstance.Call(
+		uintptr(unsafe.Pointer(&clsidShellLink)),
+		0,
+		clsctxInprocServer,
+		uintptr(unsafe.Pointer(&iidIShellLinkW)),
+		uintptr(unsafe.Pointer(&link)),
+	)
+	if int32(hr) < 0 {
+		return fmt.Errorf("创建 ShellLink 对象失败: HRESULT 0x%08X", uint32(hr))
+	}
+	defer link.release()
+
+	var persist *comObject
+	if err := link.call(methodQueryInterface, uintptr(unsafe.Pointer(&iidIPersistFile)), uintptr(unsafe.Pointer(&persist))); err != nil {
+		return fmt.Errorf("获取 IPersistFile 接口失败: %v", err)
+	}
+	defer persist.release()
+
+	return fn(link, persist)
+}
+
+// setString 调用以单个字符串为参数的 IShellLinkW 方法
+func (o *comObject) setString(method int, value string) error {
+	ptr, err := windows.UTF16PtrFromString(value)
+	if err != nil {
+		return err
+	}
+	return o.call(method, uintptr(unsafe.Pointer(ptr)))
+}
+
+// getString 调用以缓冲区和长度为参数的 IShellLinkW 方法
+func (o *comObject) getString(method int, size int, extra ...uintptr) (string, error) {
+	buf := make([]uint16, size)
+	args := append([]uintptr{uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf))}, extra...)
+	if err := o.call(method, args...); err != nil {
+		return "", err
+	}
+	return windows.UTF16ToString(buf), nil
+}
+
+// CreateShortcut 创建指向 target 的快捷方式文件
+// iconPath 为空时使用目标程序自身的图标
+func CreateShortcut(target, args, workDir, description, iconPath, linkPath string) error {
+	return withShellLink(func(link, persist *comObject) error {
+		if err := link.setString(shellLinkSetPath, target); err != nil {
+			return fmt.Errorf("设置目标路径失败: %v", err)
+		}
+		if err := link.setString(shellLinkSetArguments, args); err != nil {
+			return fmt.Errorf("设置参数失败: %v", err)
+		}
+		if err := link.setString(shellLinkSetWorkingDirectory, workDir); err != nil {
+			return fmt.Errorf("设置工作目录失败: %v", err)
+		}
+		if err := link.setString(shellLinkSetDescription, description); err != nil {
+			return fmt.Errorf("设置描述失败: %v", err)
+		}
+		if iconPath != "" {
+			icon, err := windows.UTF16PtrFromString(iconPath)
+			if err != nil {
+				return err
+			}
+			if err := link.call(shellLinkSetIconLocation, uintptr(unsafe.Pointer(icon)), 0); err != nil {
+				return fmt.Errorf("设置图标失败: %v", err)
+			}
+		}
+
+		path, err := windows.UTF16PtrFromString(linkPath)
+		if err != nil {
+			return err
+		}
+		if err := persist.call(persistFileSave, uintptr(unsafe.Pointer(path)), 1); err != nil {
+			return fmt.Errorf("保存快捷方式失败: %v", err)
+		}
+		return nil
+	})
+}
+
+// ReadShortcut 读取快捷方式文件的属性
+func ReadShortcut(linkPath string) (ShortcutInfo, error) {
+	var info ShortcutInfo
+	err := withShellLink(func(link, persist *comObject) error {
+		path, err := windows.UTF16PtrFromString(linkPath)
+		if err != nil {
+			return err
+		}
+		if err := persist.call(persistFileLoad, uintptr(unsafe.Pointer(path)), stgmRead); err != nil {
+			return fmt.Errorf("读取快捷方式失败: %v", err)
+		}
+
+		if info.TargetPath, err = link.getString(shellLinkGetPath, windows.MAX_PATH, 0, 0); err != nil {
+			return fmt.Errorf("读取目标路径失败: %v", err)
+		}
+		if info.Arguments, err = link.getString(shellLinkGetArguments, 1024); err != nil {
+			return fmt.Errorf("读取参数失败: %v", err)
+		}
+		if info.WorkingDirectory, err = link.getString(shellLinkGetWorkingDirectory, windows.MAX_PATH); err != nil {
+			return fmt.Errorf("读取工作目录失败: %v", err)
+		}
+		if info.Description, err = link.getString(shellLinkGetDescription, 1024); err != nil {
+			return fmt.Errorf("读取描述失败: %v", err)
+		}
+
+		var iconIndex int32
+		if info.IconPath, err = link.getString(shellLinkGetIconLocation, windows.MAX_PATH, uintptr(unsafe.Pointer(&iconIndex))); err != nil {
+			return fmt.Errorf("读取图标失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return ShortcutInfo{}, err
+	}
+	return info, nil
+}
+
+// runAddFromShortcut 将快捷方式指向的程序连同参数和工作目录添加到自启动
+// name 为空时使用快捷方式的文件名；显示解析结果后确认，force 为 true 时跳过确认
+func runAddFromShortcut(linkPath, name string, force bool) error {
+	info, err := ReadShortcut(linkPath)
+	if err != nil {
+		return err
+	}
+	if info.TargetPath == "" {
+		return fmt.Errorf("快捷方式没有指向文件（可能指向应用商店应用或特殊文件夹）")
+	}
+	if name == "" {
+		base := filepath.Base(linkPath)
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	fmt.Printf("快捷方式: %s\n", linkPath)
+	fmt.Printf("程序路径: %s\n", info.TargetPath)
+	if info.Arguments != "" {
+		fmt.Printf("启动参数: %s\n", info.Arguments)
+	}
+	if info.WorkingDirectory != "" {
+		fmt.Printf("工作目录: %s\n", info.WorkingDirectory)
+	}
+	if !force && !confirmYes(fmt.Sprintf("确认将 %s 添加到自启动？(y/n): ", name)) {
+		fmt.Println("已取消。")
+		return nil
+	}
+	return runAddProgram(info.TargetPath, name, info.Arguments, info.WorkingDirectory, force)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShortcutRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	target := writeTestFile(t, dir, `App\app.exe`)
+	icon := writeTestFile(t, dir, `App\app.ico`)
+	linkPath := filepath.Join(dir, "App.lnk")
+
+	want := ShortcutInfo{
+		TargetPath:       target,
+		Arguments:        `--min --config "C:\My Config\app.ini"`,
+		WorkingDirectory: filepath.Dir(target),
+		Description:      "测试快捷方式",
+		IconPath:         icon,
+	}
+	if err := CreateShortcut(want.TargetPath, want.Arguments, want.WorkingDirectory, want.Description, want.IconPath, linkPath); err != nil {
+		t.Fatalf("CreateShortcut: %v", err)
+	}
+	if !isExistingFile(linkPath) {
+		t.Fatalf("没有生成 %s", linkPath)
+	}
+
+	got, err := ReadShortcut(linkPath)
+	if err != nil {
+		t.Fatalf("ReadShortcut: %v", err)
+	}
+	// 路径不区分大小写
+	if !strings.EqualFold(got.TargetPath, want.TargetPath) {
+		t.Errorf("TargetPath = %q, want %q", got.TargetPath, want.TargetPath)
+	}
+	if got.Arguments != want.Arguments {
+		t.Errorf("Arguments = %q, want %q", got.Arguments, want.Arguments)
+	}
+	if !strings.EqualFold(got.WorkingDirectory, want.WorkingDirectory) {
+		t.Errorf("WorkingDirectory = %q, want %q", got.WorkingDirectory, want.WorkingDirectory)
+	}
+	if got.Description != want.Description {
+		t.Errorf("Description = %q, want %q", got.Description, want.Description)
+	}
+	if !strings.EqualFold(got.IconPath, want.IconPath) {
+		t.Errorf("IconPath = %q, want %q", got.IconPath, want.IconPath)
+	}
+}
+
+func TestReadShortcutMissing(t *testing.T) {
+	if _, err := ReadShortcut(filepath.Join(t.TempDir(), "missing.lnk")); err == nil {
+		t.Error("读取不存在的快捷方式应返回错误")
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 快照保存在缓存文件所在目录的 snapshots 子目录中，每个快照是一个 JSON 格式的缓存文件
+const snapshotDirName = "snapshots"
+
+// --revert 的来源前缀
+const (
+	revertSnapshotPrefix = "snapshot:"
+	revertBackupPrefix   = "backup:"
+)
+
+// snapshotDir 返回保存快照的目录
+func snapshotDir() string {
+	return filepath.Join(filepath.Dir(cacheFilePath), snapshotDirName)
+}
+
+// snapshotPath 返回名为 name 的快照文件路径，名称不能包含路径分隔符等文件名中不允许的字符
+func snapshotPath(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `\/:*?"<>|`) {
+		return "", fmt.Errorf("无效的快照名称 %q", name)
+	}
+	return filepath.Join(snapshotDir(), name+".json"), nil
+}
+
+// listSnapshots 返回已有快照的名称，按名称排序
+func listSnapshots() []string {
+	matches, _ := filepath.Glob(filepath.Join(snapshotDir(), "*.json"))
+	names := make([]string, 0, len(matches))
+	for _, path := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(path), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CreateSnapshot 根据注册表同步缓存后将其保存为名为 name 的快照，返回快照文件的路径
+// 已有同名快照时除非 force 为 true，否则不覆盖
+func CreateSnapshot(name string, force bool) (string, error) {
+	path, err := snapshotPath(name)
+	if err != nil {
+		return "", err
+	}
+	if isExistingFile(path) && !force {
+		return "", fmt.Errorf("快照 %s 已存在，使用 --force 覆盖", name)
+	}
+
+	if err := syncCacheFromRegistry(); err != nil {
+		return "", err
+	}
+	cache, err := loadCache()
+	if err != nil {
+		return "", fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	if dryRunf("保存快照 %s（%d 项）", path, len(cache.Items)) {
+		return path, nil
+	}
+	if err := os.MkdirAll(snapshotDir(), 0755); err != nil {
+		return "", fmt.Errorf("创建快照目录失败: %v", err)
+	}
+	if err := saveCacheAuto(path, cache); err != nil {
+		return "", fmt.Errorf("保存快照失败: %v", err)
+	}
+	return path, nil
+}
+
+// loadBackupItems 按扩展名读取备份文件中的启动项：.reg、.csv，其他文件按缓存文件（.json、.toml 及其 .bak）读取
+func loadBackupItems(path string) ([]CacheItem, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".reg":
+		return ImportFromRegFile(path)
+	case ".csv":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return ImportFromCSV(file)
+	}
+
+	data, err := loadCacheAuto(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取缓存备份失败: %v", err)
+	}
+	return data.Items, nil
+}
+
+// revertSource 恢复来源中的启动项，以及来源能记录哪些信息
+type revertSource struct {
+	Items       []CacheItem
+	HasDisabled bool // 记录了禁用的项；.reg 文件中禁用的项只是注释，读取时被忽略
+	HasStart    bool // 记录了窗口状态、延迟和工作目录；只有快照和缓存备份有这些信息
+}
+
+// loadRevertSource 解析 snapshot:<名称> 或 backup:<文件>，返回要恢复到的启动项
+func loadRevertSource(source string) (*revertSource, error) {
+	switch {
+	case strings.HasPrefix(source, revertSnapshotPrefix):
+		name := strings.TrimPrefix(source, revertSnapshotPrefix)
+		path, err := snapshotPath(name)
+		if err != nil {
+			return nil, err
+		}
+		if !isExistingFile(path) {
+			if names := listSnapshots(); len(names) > 0 {
+				return nil, fmt.Errorf("快照 %s 不存在（已有快照: %s）", name, strings.Join(names, ", "))
+			}
+			return nil, fmt.Errorf("快照 %s 不存在", name)
+		}
+		data, err := loadCacheAuto(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取快照失败: %v", err)
+		}
+		return &revertSource{Items: data.Items, HasDisabled: true, HasStart: true}, nil
+	case strings.HasPrefix(source, revertBackupPrefix):
+		path := strings.TrimPrefix(source, revertBackupPrefix)
+		items, err := loadBackupItems(path)
+		if err != nil {
+			return nil, err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		return &revertSource{
+			Items:       items,
+			HasDisabled: ext != ".reg",
+			HasStart:    ext != ".reg" && ext != ".csv",
+		}, nil
+	}
+	return nil, fmt.Errorf("无法识别的恢复来源 %s（可选 snapshot:<名称> 或 backup:<文件>）", source)
+}
+
+// revertChange 恢复时对一个启动项的修改
+type revertChange struct {
+	Item   CacheItem // 恢复后的启动项；Remove 为 true 时为当前的启动项
+	Remove bool      // 启动项不在恢复来源中，需要移除
+	IsNew  bool      // 当前没有这个启动项
+}
+
+// isUserRunItem 检查启动项是否位于当前用户的 Run 键，恢复只处理这些启动项
+func isUserRunItem(item CacheItem) bool {
+	return item.Scope == ScopeCurrentUser && !item.RunOnce
+}
+
+// sameStartConfig 检查两个启动项的启动方式（窗口状态、延迟和工作目录）是否相同
+func sameStartConfig(a, b CacheItem) bool {
+	return a.WindowState == b.WindowState && a.DelaySeconds == b.DelaySeconds && a.WorkingDir == b.WorkingDir
+}
+
+// startConfigLabel 启动方式的简短说明，直接启动时返回空字符串
+func startConfigLabel(item CacheItem) string {
+	var parts []string
+	if label := windowStateLabel(item.WindowState); label != "" {
+		parts = append(parts, label)
+	}
+	if item.DelaySeconds > 0 {
+		parts = append(parts, fmt.Sprintf("延迟 %d 秒", item.DelaySeconds))
+	}
+	if item.WorkingDir != "" {
+		parts = append(parts, "工作目录 "+item.WorkingDir)
+	}
+	return strings.Join(parts, "，")
+}
+
+// planRevert 比较当前缓存与恢复来源，返回需要的修改
+// 来源中的启动项按名称添加或修改，当前有但来源中没有的启动项被移除；
+// .reg 文件中删除的值（"name"=-）只将已有的启动项标记为禁用。
+// 来源没有记录禁用的项时，当前禁用的项保持不变；没有记录启动方式时，命令未变的项沿用当前的启动方式
+func planRevert(current *CacheData, source *revertSource) []revertChange {
+	var changes []revertChange
+	wanted := make(map[string]bool)
+	for _, item := range source.Items {
+		if !isUserRunItem(item) {
+			continue
+		}
+		wanted[item.Name] = true
+
+		idx, cur := findItemByName(current, item.Name)
+		if item.Value == "" {
+			if idx < 0 {
+				continue
+			}
+			item.Value = cur.Value
+		}
+		if idx >= 0 && !source.HasStart && cur.Value == item.Value {
+			item.WindowState, item.DelaySeconds, item.WorkingDir = cur.WindowState, cur.DelaySeconds, cur.WorkingDir
+		}
+		if idx >= 0 && cur.Value == item.Value && cur.Enabled == item.Enabled && sameStartConfig(*cur, item) {
+			continue
+		}
+		changes = append(changes, revertChange{Item: item, IsNew: idx < 0})
+	}
+
+	for _, item := range current.Items {
+		if !isUserRunItem(item) || wanted[item.Name] {
+			continue
+		}
+		if !item.Enabled && !source.HasDisabled {
+			continue
+		}
+		changes = append(changes, revertChange{Item: item, Remove: true})
+	}
+	return changes
+}
+
+// removeRevertedItem 从注册表和缓存中移除恢复来源中没有的启动项
+func removeRevertedItem(cache *CacheData, name string) error {
+	exists, err := IsCommandInStartup(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := RemoveFromStartup(name); err != nil {
+			return err
+		}
+	}
+	removeItem(cache, name)
+	return nil
+}
+
+// Revert 将当前用户 Run 键中的启动项恢复到 source 所指的快照或备份，source 为 snapshot:<名称> 或 backup:<文件>
+// 恢复前总是先为当前状态创建快照；所有修改作为一个事务执行，任一项失败时全部回滚
+// force 为 true 时不显示修改并确认
+func Revert(source string, force bool) error {
+	target, err := loadRevertSource(source)
+	if err != nil {
+		return err
+	}
+
+	// 同一秒内多次恢复时加上序号，不覆盖之前的快照
+	stamp := "before-revert-" + time.Now().Format("20060102-150405")
+	autoName := stamp
+	for i := 2; ; i++ {
+		if path, _ := snapshotPath(autoName); !isExistingFile(path) {
+			break
+		}
+		autoName = fmt.Sprintf("%s-%d", stamp, i)
+	}
+	path, err := CreateSnapshot(autoName, false)
+	if err != nil {
+		return fmt.Errorf("创建恢复前的快照失败: %v", err)
+	}
+	fmt.Printf("已将当前状态保存为快照 %s（%s）\n", autoName, path)
+
+	cache, err := loadCache()
+	if err != nil {
+		return fmt.Errorf("加载缓存失败: %v", err)
+	}
+	changes := planRevert(cache, target)
+	if len(changes) == 0 {
+		fmt.Println("当前的启动项与恢复来源一致，无需修改。")
+		return nil
+	}
+
+	fmt.Printf("恢复到 %s 需要 %d 处修改：\n", source, len(changes))
+	for _, change := range changes {
+		switch {
+		case change.Remove:
+			fmt.Printf("  - 移除 %s（%s）\n", change.Item.Name, change.Item.Value)
+		case change.IsNew:
+			fmt.Printf("  + 添加 %s（%s）: %s\n", change.Item.Name, revertItemLabel(change.Item), change.Item.Value)
+		default:
+			fmt.Printf("  ~ 修改 %s（%s）: %s\n", change.Item.Name, revertItemLabel(change.Item), change.Item.Value)
+		}
+	}
+	if !force && !confirmYes("确认恢复？(y/n): ") {
+		fmt.Println("已取消。")
+		return nil
+	}
+
+	tx := Begin()
+	for _, change := range changes {
+		change := change
+		tx.AddEntryOperation(change.Item.Name, func() error {
+			if change.Remove {
+				if err := removeRevertedItem(cache, change.Item.Name); err != nil {
+					return fmt.Errorf("移除 %s 失败: %v", change.Item.Name, err)
+				}
+				return nil
+			}
+			if err := applyImportedItem(cache, change.Item); err != nil {
+				return fmt.Errorf("恢复 %s 失败: %v", change.Item.Name, err)
+			}
+			return nil
+		})
+	}
+	if err := commitImport(tx, cache); err != nil {
+		return err
+	}
+	fmt.Printf("已恢复到 %s，如需撤销可运行 autostart --revert=%s%s\n", source, revertSnapshotPrefix, autoName)
+	return nil
+}
+
+// revertItemLabel 恢复后的启用状态和启动方式
+func revertItemLabel(item CacheItem) string {
+	if label := startConfigLabel(item); label != "" {
+		return enabledLabel(item.Enabled) + "，" + label
+	}
+	return enabledLabel(item.Enabled)
+}
+
+// runSnapshot 命令行模式下为当前状态创建快照
+func runSnapshot(name string, force bool) error {
+	path, err := CreateSnapshot(name, force)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已保存快照 %s（%s）\n", name, path)
+	return nil
+}
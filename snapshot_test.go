@@ -0,0 +1,210 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotPath(t *testing.T) {
+	setupTestEnv(t)
+	for _, name := range []string{"", ".", "..", `..\x`, "a/b", "a:b"} {
+		if _, err := snapshotPath(name); err == nil {
+			t.Errorf("snapshotPath(%q) 应返回错误", name)
+		}
+	}
+	path, err := snapshotPath("before-install")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(snapshotDir(), "before-install.json"); path != want {
+		t.Errorf("snapshotPath = %q, want %q", path, want)
+	}
+}
+
+func TestRevertSnapshot(t *testing.T) {
+	reg := setupTestEnv(t)
+	for name, value := range map[string]string{"A": `C:\a.exe`, "B": `C:\b.exe`} {
+		if err := AddCommandEntry(name, value, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := CreateSnapshot("before-install", false); err != nil {
+		t.Fatalf("CreateSnapshot: %v", err)
+	}
+	if _, err := CreateSnapshot("before-install", false); err == nil {
+		t.Error("没有 force 时不应覆盖已有的快照")
+	}
+
+	// 快照之后：移除 A，修改 B，添加 C
+	if err := RemoveEntry("A"); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddCommandEntry("B", `C:\b.exe --changed`, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddCommandEntry("C", `C:\c.exe`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Revert(revertSnapshotPrefix+"before-install", true); err != nil {
+		t.Fatalf("Revert: %v", err)
+	}
+	for name, want := range map[string]string{"A": `C:\a.exe`, "B": `C:\b.exe`} {
+		if got, err := reg.GetValue(runKeyPath, name); err != nil || got != want {
+			t.Errorf("注册表中 %s = %q (%v), want %q", name, got, err, want)
+		}
+	}
+	if _, err := reg.GetValue(runKeyPath, "C"); err == nil {
+		t.Error("C 不在快照中，应被移除")
+	}
+	cache, err := loadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.Items) != 2 {
+		t.Errorf("缓存中有 %d 项，want 2", len(cache.Items))
+	}
+
+	// 恢复前自动创建的快照可以撤销这次恢复
+	snapshots := listSnapshots()
+	if len(snapshots) != 2 {
+		t.Fatalf("快照 = %v, want before-install 和恢复前的自动快照", snapshots)
+	}
+	if err := Revert(revertSnapshotPrefix+snapshots[1], true); err != nil {
+		t.Fatalf("Revert: %v", err)
+	}
+	if _, err := reg.GetValue(runKeyPath, "C"); err != nil {
+		t.Errorf("撤销恢复后应有 C: %v", err)
+	}
+	if len(listSnapshots()) != 3 {
+		t.Errorf("快照 = %v, want 3 个", listSnapshots())
+	}
+}
+
+func TestRevertBackup(t *testing.T) {
+	reg := setupTestEnv(t)
+	if err := AddCommandEntry("Old", `C:\old.exe`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := filepath.Join(t.TempDir(), "autostart-backup.csv")
+	if err := os.WriteFile(backup, []byte("Name,Value,Enabled\nApp,C:\\app.exe,true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Revert(revertBackupPrefix+backup, true); err != nil {
+		t.Fatalf("Revert: %v", err)
+	}
+	if got, err := reg.GetValue(runKeyPath, "App"); err != nil || got != `C:\app.exe` {
+		t.Errorf("注册表中 App = %q (%v)", got, err)
+	}
+	if _, err := reg.GetValue(runKeyPath, "Old"); err == nil {
+		t.Error("Old 不在备份中，应被移除")
+	}
+
+	if _, err := loadRevertSource("before-install"); err == nil {
+		t.Error("没有前缀的来源应返回错误")
+	}
+	if _, err := loadRevertSource(revertSnapshotPrefix + "missing"); err == nil {
+		t.Error("不存在的快照应返回错误")
+	}
+}
+
+func TestRevertRegBackupKeepsDisabled(t *testing.T) {
+	reg := setupTestEnv(t)
+	for name, value := range map[string]string{"A": `C:\a.exe`, "B": `C:\b.exe`} {
+		if err := AddCommandEntry(name, value, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := DisableEntry("B", "不常用"); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := loadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup := filepath.Join(t.TempDir(), "autostart-backup.reg")
+	if err := ExportToRegFile(backup, cache.Items); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddCommandEntry("C", `C:\c.exe`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Revert(revertBackupPrefix+backup, true); err != nil {
+		t.Fatalf("Revert: %v", err)
+	}
+	if _, err := reg.GetValue(runKeyPath, "C"); err == nil {
+		t.Error("C 不在备份中，应被移除")
+	}
+	cache, err = loadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, b := findItemByName(cache, "B")
+	if b == nil {
+		t.Fatal(".reg 备份不记录禁用的项，恢复时应保留已禁用的 B")
+	}
+	if b.Enabled || b.LastDisabledReason != "不常用" || len(b.History) == 0 {
+		t.Errorf("B = %+v, 应保持禁用并保留禁用原因和历史", *b)
+	}
+}
+
+func TestRevertStartConfig(t *testing.T) {
+	reg := setupTestEnv(t)
+	if err := AddCommandEntry("A", `C:\a.exe`, ""); err != nil {
+		t.Fatal(err)
+	}
+	// setDelay 修改 A 的延迟秒数并重新注册
+	setDelay := func(seconds int) {
+		t.Helper()
+		cache, err := loadCache()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, item := findItemByName(cache, "A")
+		item.DelaySeconds = seconds
+		if err := registerStartupItem(*item); err != nil {
+			t.Fatal(err)
+		}
+		if err := saveCache(cache); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setDelay(30)
+	if _, err := CreateSnapshot("delayed", false); err != nil {
+		t.Fatal(err)
+	}
+	setDelay(0)
+
+	cache, err := loadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := loadRevertSource(revertSnapshotPrefix + "delayed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes := planRevert(cache, source); len(changes) != 1 || changes[0].Item.DelaySeconds != 30 {
+		t.Fatalf("只修改了延迟时 planRevert = %+v, want 1 处修改", changes)
+	}
+
+	if err := Revert(revertSnapshotPrefix+"delayed", true); err != nil {
+		t.Fatalf("Revert: %v", err)
+	}
+	want := CacheItem{Name: "A", Value: `C:\a.exe`, DelaySeconds: 30}
+	if got, err := reg.GetValue(runKeyPath, "A"); err != nil || got != startWrapperCommand(want) {
+		t.Errorf("注册表中 A = %q (%v), want 启动脚本 %q", got, err, startWrapperCommand(want))
+	}
+	if !isExistingFile(wrapperPath("A", delayWrapperSuffix)) {
+		t.Error("恢复时应重新生成启动脚本")
+	}
+	cache, err = loadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, item := findItemByName(cache, "A"); item.DelaySeconds != 30 || item.Value != `C:\a.exe` {
+		t.Errorf("缓存中 A = %+v, want 延迟 30 秒且 Value 为原命令", *item)
+	}
+}
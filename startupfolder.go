@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// StartupFolderEntry 启动文件夹中的一个快捷方式
+type StartupFolderEntry struct {
+	Name       string // 快捷方式名称（不含 .lnk）
+	LinkPath   string
+	TargetPath string
+	Arguments  string
+	WorkingDir string
+}
+
+// getStartupFolder 获取当前用户的启动文件夹
+// 即 %APPDATA%\Microsoft\Windows\Start Menu\Programs\Startup
+func getStartupFolder() (string, error) {
+	dir, err := windows.KnownFolderPath(windows.FOLDERID_Startup, 0)
+	if err != nil {
+		return "", fmt.Errorf("获取启动文件夹失败: %v", err)
+	}
+	return dir, nil
+}
+
+// startupLinkPath 获取启动文件夹中快捷方式的完整路径
+func startupLinkPath(linkName string) (string, error) {
+	dir, err := getStartupFolder()
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(filepath.Ext(linkName), ".lnk") {
+		linkName += ".lnk"
+	}
+	return filepath.Join(dir, linkName), nil
+}
+
+// AddToStartupFolder 在启动文件夹中创建程序的快捷方式
+func AddToStartupFolder(exePath, linkName string, args string, workDir string) error {
+	absPath, err := filepath.Abs(exePath)
+	if err != nil {
+		return fmt.Errorf("获取绝对路径失败: %v", err)
+	}
+
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("文件不存在: %s", absPath)
+	}
+
+	// 默认以程序所在目录作为工作目录
+	if workDir == "" {
+		workDir = filepath.Dir(absPath)
+	}
+
+	linkPath, err := startupLinkPath(linkName)
+	if err != nil {
+		return err
+	}
+
+	if dryRunf("创建快捷方式 %s -> %s", linkPath, buildCommand(absPath, args)) {
+		return nil
+	}
+	return CreateShortcut(absPath, args, workDir, "", "", linkPath)
+}
+
+// RemoveFromStartupFolder 删除启动文件夹中的快捷方式
+func RemoveFromStartupFolder(linkName string) error {
+	linkPath, err := startupLinkPath(linkName)
+	if err != nil {
+		return err
+	}
+
+	if dryRunf("删除快捷方式 %s", linkPath) {
+		return nil
+	}
+
+	if err := os.Remove(linkPath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("快捷方式不存在")
+		}
+		return fmt.Errorf("删除快捷方式失败: %v", err)
+	}
+	return nil
+}
+
+// ListStartupFolderEntries 列出启动文件夹中的快捷方式并解析其目标
+func ListStartupFolderEntries() ([]StartupFolderEntry, error) {
+	dir, err := getStartupFolder()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("无法读取启动文件夹: %v", err)
+	}
+
+	var result []StartupFolderEntry
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".lnk") {
+			continue
+		}
+
+		linkPath := filepath.Join(dir, entry.Name())
+		item := StartupFolderEntry{
+			Name:     strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+			LinkPath: linkPath,
+		}
+
+		// 无法解析的快捷方式也列出，目标留空
+		if info, err := ReadShortcut(linkPath); err == nil {
+			item.TargetPath = info.TargetPath
+			item.Arguments = info.Arguments
+			item.WorkingDir = info.WorkingDirectory
+		}
+		result = append(result, item)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// commandLine 返回快捷方式对应的启动命令
+func (e StartupFolderEntry) commandLine() string {
+	if e.TargetPath == "" {
+		return "(无法解析快捷方式目标)"
+	}
+	return buildCommand(e.TargetPath, e.Arguments)
+}
@@ -0,0 +1,89 @@
+package main
+
+import "fmt"
+
+// Stats 启动项统计
+type Stats struct {
+	Enabled  int
+	Disabled int
+	Total    int
+	Orphaned int
+}
+
+var (
+	// 缓存的统计结果，缓存文件保存后失效
+	cachedStats *Stats
+
+	// 最近一次 FindOrphanedEntries 得到的失效项数量，-1 表示尚未检查
+	orphanedCount = -1
+	// 检查之后是否又有添加或移除操作
+	orphanedStale bool
+)
+
+// computeStats 统计启用、禁用的启动项数量，失效项数量取最近一次检查的结果
+func computeStats(data *CacheData) Stats {
+	var stats Stats
+	for _, item := range data.Items {
+		if item.Enabled {
+			stats.Enabled++
+		} else {
+			stats.Disabled++
+		}
+	}
+	stats.Total = len(data.Items)
+	if orphanedCount > 0 {
+		stats.Orphaned = orphanedCount
+	}
+	return stats
+}
+
+// currentStats 返回缓存的统计结果，失效时重新统计
+func currentStats() (Stats, error) {
+	if cachedStats != nil {
+		return *cachedStats, nil
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		return Stats{}, err
+	}
+	stats := computeStats(cache)
+	cachedStats = &stats
+	return stats, nil
+}
+
+// invalidateStats 使缓存的统计结果失效
+func invalidateStats() {
+	cachedStats = nil
+}
+
+// markOrphansStale 标记失效项数量需要重新检查
+func markOrphansStale() {
+	orphanedStale = true
+}
+
+// recordOrphanCount 记录 FindOrphanedEntries 的检查结果
+func recordOrphanCount(n int) {
+	orphanedCount = n
+	orphanedStale = false
+	invalidateStats()
+}
+
+// statsSummary 主菜单标题下显示的统计摘要
+func statsSummary() string {
+	stats, err := currentStats()
+	if err != nil {
+		return ""
+	}
+
+	summary := fmt.Sprintf("启动项: %d 个启用，%d 个禁用", stats.Enabled, stats.Disabled)
+	switch {
+	case orphanedCount < 0:
+		summary += "，失效项未检查"
+	case orphanedStale:
+		summary += fmt.Sprintf("，%d 个失效（已过期）", stats.Orphaned)
+	default:
+		summary += fmt.Sprintf("，%d 个失效", stats.Orphaned)
+	}
+	return summary
+}
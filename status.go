@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// EntryStatus 单个启动项的详细状态
+type EntryStatus struct {
+	Name         string     `json:"name"`
+	RegistryKey  string     `json:"registry_key"`
+	Value        string     `json:"value"`
+	Enabled      bool       `json:"enabled"`
+	InRegistry   bool       `json:"in_registry"`
+	FilePath     string     `json:"file_path,omitempty"`
+	FileExists   bool       `json:"file_exists"`
+	FileVersion  string     `json:"file_version,omitempty"`
+	Publisher    string     `json:"publisher,omitempty"`
+	Signature    string     `json:"signature"`
+	FileHash     string     `json:"file_hash,omitempty"`    // 添加时记录的 SHA-256
+	HashMatches  *bool      `json:"hash_matches,omitempty"` // 当前文件与记录的哈希是否一致，没有记录时为空
+	Risk         string     `json:"risk"`
+	RunningPIDs  []uint32   `json:"running_pids,omitempty"`
+	RunCount     int        `json:"run_count"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	DelaySeconds int        `json:"delay_seconds,omitempty"`
+	WorkingDir   string     `json:"working_dir,omitempty"`
+	WindowState  string     `json:"window_state,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Notes        string     `json:"notes,omitempty"`
+	Reason       string     `json:"reason,omitempty"`
+}
+
+// entryInRegistry 检查启动项当前是否在它所在的注册表键中
+func entryInRegistry(item CacheItem) bool {
+	var items []CacheItem
+	switch {
+	case item.RunOnce:
+		items, _ = ListRunOnceEntries(item.Scope)
+	case item.Scope == ScopeMachineWide:
+		items, _ = ListStartupEntries(ScopeMachineWide)
+	default:
+		exists, _ := IsCommandInStartup(item.Name)
+		return exists
+	}
+	for _, live := range items {
+		if live.Name == item.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEntryStatus 收集启动项的详细状态，缓存中没有时从注册表查找
+func GetEntryStatus(name string) (EntryStatus, error) {
+	item, err := findEntry(name)
+	if err != nil {
+		return EntryStatus{}, err
+	}
+
+	keyPath := runKeyPath
+	if item.RunOnce {
+		keyPath = runOnceKeyPath
+	}
+	status := EntryStatus{
+		Name:         item.Name,
+		RegistryKey:  item.Scope.String() + `\` + keyPath,
+		Value:        item.Value,
+		Enabled:      item.Enabled,
+		InRegistry:   entryInRegistry(item),
+		Signature:    SignatureUnknown.String(),
+		FileHash:     item.FileHash,
+		Risk:         entryRisk(item).String(),
+		RunCount:     item.RunCount,
+		CreatedAt:    item.CreatedAt,
+		UpdatedAt:    item.UpdatedAt,
+		ExpiresAt:    item.ExpiresAt,
+		DelaySeconds: item.DelaySeconds,
+		WorkingDir:   item.WorkingDir,
+		WindowState:  item.WindowState,
+		Tags:         item.Tags,
+		Notes:        item.Notes,
+		Reason:       item.Reason,
+	}
+
+	if path, err := entryFilePath(item); err == nil {
+		status.FilePath = path
+		status.FileExists = true
+		status.FileVersion, _ = GetFileVersion(path)
+		status.Publisher, _ = GetFilePublisher(path)
+		status.Signature = VerifySignature(path).String()
+		if item.FileHash != "" {
+			current, _ := computeFileHash(path)
+			matches := strings.EqualFold(current, item.FileHash)
+			status.HashMatches = &matches
+		}
+	} else if exePath, _, err := entryCommandLine(item); err == nil {
+		status.FilePath = exePath
+	}
+
+	if processes, err := FindEntryProcesses(item); err == nil {
+		for _, p := range processes {
+			status.RunningPIDs = append(status.RunningPIDs, p.PID)
+		}
+	}
+	return status, nil
+}
+
+// formatStatusTime 格式化详细状态中的时间，零值显示为 -
+func formatStatusTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// runStatus 输出单个启动项的详细状态，outputFormat 为 text、json 或 ndjson（一行 JSON）
+func runStatus(name, outputFormat string) error {
+	status, err := GetEntryStatus(name)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" || outputFormat == "ndjson" {
+		encoder := json.NewEncoder(os.Stdout)
+		if outputFormat == "json" {
+			encoder.SetIndent("", "  ")
+		}
+		return encoder.Encode(status)
+	}
+
+	yesNo := func(b bool) string {
+		if b {
+			return "是"
+		}
+		return "否"
+	}
+	fmt.Printf("名称: %s\n", status.Name)
+	fmt.Printf("注册表键: %s\n", status.RegistryKey)
+	fmt.Printf("启动命令: %s\n", status.Value)
+	fmt.Printf("状态: %s（注册表中%s）\n", enabledLabel(status.Enabled), map[bool]string{true: "存在", false: "不存在"}[status.InRegistry])
+	if status.FilePath != "" {
+		fmt.Printf("程序文件: %s（%s）\n", status.FilePath, map[bool]string{true: "存在", false: "不存在"}[status.FileExists])
+	}
+	if status.FileVersion != "" {
+		fmt.Printf("文件版本: %s\n", status.FileVersion)
+	}
+	if status.Publisher != "" {
+		fmt.Printf("发布者: %s\n", status.Publisher)
+	}
+	fmt.Printf("数字签名: %s\n", status.Signature)
+	if status.FileHash != "" {
+		fmt.Printf("SHA-256: %s\n", status.FileHash)
+		if status.HashMatches != nil {
+			fmt.Printf("哈希一致: %s\n", yesNo(*status.HashMatches))
+		}
+	}
+	fmt.Printf("风险等级: %s\n", status.Risk)
+	if len(status.RunningPIDs) > 0 {
+		pids := make([]string, len(status.RunningPIDs))
+		for i, pid := range status.RunningPIDs {
+			pids[i] = fmt.Sprint(pid)
+		}
+		fmt.Printf("正在运行: PID %s\n", strings.Join(pids, ", "))
+	} else {
+		fmt.Println("正在运行: 否")
+	}
+	fmt.Printf("运行次数: %d\n", status.RunCount)
+	fmt.Printf("添加时间: %s\n", formatStatusTime(status.CreatedAt))
+	fmt.Printf("修改时间: %s\n", formatStatusTime(status.UpdatedAt))
+	if status.ExpiresAt != nil {
+		fmt.Printf("到期日期: %s\n", status.ExpiresAt.Format("2006-01-02"))
+	}
+	if status.DelaySeconds > 0 {
+		fmt.Printf("延迟启动: %d 秒\n", status.DelaySeconds)
+	}
+	if status.WorkingDir != "" {
+		fmt.Printf("工作目录: %s\n", status.WorkingDir)
+	}
+	if status.WindowState != "" && status.WindowState != windowStateNormal {
+		fmt.Printf("窗口状态: %s\n", status.WindowState)
+	}
+	if len(status.Tags) > 0 {
+		fmt.Printf("标签: %s\n", strings.Join(status.Tags, ", "))
+	}
+	if status.Notes != "" {
+		fmt.Printf("备注: %s\n", status.Notes)
+	}
+	if status.Reason != "" {
+		fmt.Printf("开机启动原因: %s\n", status.Reason)
+	}
+	return nil
+}
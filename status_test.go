@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGetEntryStatus(t *testing.T) {
+	reg := setupTestEnv(t)
+	dir := t.TempDir()
+	exe := writeTestFile(t, dir, "app.exe")
+	hash, err := computeFileHash(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := buildCommand(exe, "--min")
+	reg.SetValue(runKeyPath, "App", value)
+	items := []CacheItem{
+		{Name: "App", Value: value, Enabled: true, FileHash: hash},
+		{Name: "Stale", Value: buildCommand(exe, ""), Enabled: true, FileHash: "00"},
+	}
+	if err := saveCache(&CacheData{Items: items}); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := GetEntryStatus("App")
+	if err != nil {
+		t.Fatalf("GetEntryStatus: %v", err)
+	}
+	if !status.InRegistry || !status.FileExists {
+		t.Errorf("InRegistry = %v, FileExists = %v, want true", status.InRegistry, status.FileExists)
+	}
+	if status.HashMatches == nil || !*status.HashMatches {
+		t.Errorf("HashMatches = %v, want true", status.HashMatches)
+	}
+	if status.Signature != SignatureNone.String() {
+		t.Errorf("Signature = %q, want %q", status.Signature, SignatureNone.String())
+	}
+
+	status, err = GetEntryStatus("Stale")
+	if err != nil {
+		t.Fatalf("GetEntryStatus: %v", err)
+	}
+	if status.InRegistry {
+		t.Error("Stale 不在注册表中")
+	}
+	if status.HashMatches == nil || *status.HashMatches {
+		t.Errorf("HashMatches = %v, want false", status.HashMatches)
+	}
+
+	if err := os.Remove(exe); err != nil {
+		t.Fatal(err)
+	}
+	status, err = GetEntryStatus("App")
+	if err != nil {
+		t.Fatalf("GetEntryStatus: %v", err)
+	}
+	if status.FileExists || status.HashMatches != nil {
+		t.Errorf("FileExists = %v, HashMatches = %v, want false 和 nil", status.FileExists, status.HashMatches)
+	}
+
+	if _, err := GetEntryStatus("Missing"); err == nil {
+		t.Error("不存在的启动项应返回错误")
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// ErrProcessExitedImmediately 试运行的程序启动后很快以非零代码退出
+var ErrProcessExitedImmediately = errors.New("程序启动后立即退出")
+
+// 启动后等待多久检查程序是否仍在运行
+const testLaunchWait = 500 * time.Millisecond
+
+// testProcess 试运行中仍在运行的进程
+type testProcess struct {
+	name string
+	cmd  *exec.Cmd
+	done chan struct{}
+}
+
+// 最近一次试运行启动的进程，程序退出后为 nil
+var runningTest *testProcess
+
+// TestLaunch 试运行启动项：启动程序（不等待其退出），500 毫秒后检查是否仍在运行
+// 程序仍在运行或已正常退出时返回 nil，以非零代码退出时返回 ErrProcessExitedImmediately
+func TestLaunch(item CacheItem) error {
+	exePath, args, err := entryCommandLine(item)
+	if err != nil {
+		return err
+	}
+
+	if dryRunf("试运行 %s", buildCommand(exePath, args)) {
+		return nil
+	}
+
+	// 参数按注册表中的原样传递，不再重新加引号
+	cmd := exec.Command(exePath)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: buildCommand(exePath, args)}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动失败: %v", err)
+	}
+
+	proc := &testProcess{name: item.Name, cmd: cmd, done: make(chan struct{})}
+	var waitErr error
+	go func() {
+		waitErr = cmd.Wait()
+		close(proc.done)
+	}()
+
+	select {
+	case <-proc.done:
+		if waitErr != nil {
+			var exitErr *exec.ExitError
+			if errors.As(waitErr, &exitErr) {
+				return fmt.Errorf("%w（退出代码 %d）", ErrProcessExitedImmediately, exitErr.ExitCode())
+			}
+			return fmt.Errorf("%w: %v", ErrProcessExitedImmediately, waitErr)
+		}
+		return nil
+	case <-time.After(testLaunchWait):
+		runningTest = proc
+		return nil
+	}
+}
+
+// entryCommandLine 返回启动项实际运行的程序和参数，去掉最小化、隐藏窗口的包装命令
+func entryCommandLine(item CacheItem) (string, string, error) {
+	value := unwrapWindowState(item.Value, item.Name, item.WindowState)
+	exePath, args, err := parseCommandPath(value)
+	if err != nil {
+		return "", "", err
+	}
+	if item.Arguments != "" {
+		args = item.Arguments
+	}
+	return exePath, args, nil
+}
+
+// 以 SYSTEM 身份运行启动项时使用的临时计划任务
+const runAsSystemTaskName = `\AutostartManagerRunAsSystem`
+
+// findEntry 查找启动项，缓存中没有时依次读取当前用户和所有用户的 Run 键
+func findEntry(name string) (CacheItem, error) {
+	cache, err := loadCache()
+	if err != nil {
+		return CacheItem{}, fmt.Errorf("加载缓存失败: %v", err)
+	}
+	if _, item := findItemByName(cache, name); item != nil {
+		return *item, nil
+	}
+
+	value, err := startupRegistry.GetValue(runKeyPath, name)
+	if err == nil {
+		return CacheItem{Name: name, Value: value, Enabled: true}, nil
+	}
+	if err != registry.ErrNotExist {
+		return CacheItem{}, fmt.Errorf("查询注册表失败: %v", err)
+	}
+	machineItems, _ := ListStartupEntries(ScopeMachineWide)
+	for _, item := range machineItems {
+		if item.Name == name {
+			return item, nil
+		}
+	}
+	return CacheItem{}, fmt.Errorf("启动项 %s 不存在", name)
+}
+
+// RunEntry 立即运行启动项，不必重启即可检查它能否正常启动
+// detach 为 false 时等待程序退出，程序的输出直接显示在控制台，以非零代码退出时返回 *exec.ExitError；
+// detach 为 true 时在后台启动后立即返回
+func RunEntry(name string, detach bool) error {
+	item, err := findEntry(name)
+	if err != nil {
+		return err
+	}
+	exePath, args, err := entryCommandLine(item)
+	if err != nil {
+		return err
+	}
+
+	command := buildCommand(exePath, args)
+	if dryRunf("运行 %s", command) {
+		return nil
+	}
+
+	// 参数按注册表中的原样传递，不再重新加引号
+	cmd := exec.Command(exePath)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: command}
+	cmd.Dir = item.WorkingDir
+	if detach {
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("启动失败: %v", err)
+		}
+		fmt.Printf("已在后台启动 %s（PID %d）\n", name, cmd.Process.Pid)
+		return cmd.Process.Release()
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	fmt.Printf("正在运行 %s，等待程序退出...\n", command)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return err
+		}
+		return fmt.Errorf("启动失败: %v", err)
+	}
+	return nil
+}
+
+// RunEntryAsSystem 通过临时计划任务以 SYSTEM 身份运行启动项，需要管理员权限
+// 程序运行在服务会话中，看不到它的窗口和输出；任务启动后即删除，不影响已启动的程序
+func RunEntryAsSystem(name string) error {
+	if !IsRunningAsAdmin() {
+		return fmt.Errorf("以 SYSTEM 身份运行需要管理员权限")
+	}
+	item, err := findEntry(name)
+	if err != nil {
+		return err
+	}
+	exePath, args, err := entryCommandLine(item)
+	if err != nil {
+		return err
+	}
+
+	command := buildCommand(exePath, args)
+	if dryRunf("以 SYSTEM 身份运行 %s", command) {
+		return nil
+	}
+	if _, err := runSchtasks("/Create", "/TN", runAsSystemTaskName, "/TR", command,
+		"/SC", "ONCE", "/ST", "00:00", "/RU", "SYSTEM", "/RL", "HIGHEST", "/F"); err != nil {
+		return err
+	}
+	defer runSchtasks("/Delete", "/TN", runAsSystemTaskName, "/F")
+
+	if _, err := runSchtasks("/Run", "/TN", runAsSystemTaskName); err != nil {
+		return err
+	}
+	fmt.Printf("已以 SYSTEM 身份启动 %s（运行在服务会话中，看不到窗口和输出）\n", name)
+	return nil
+}
+
+// isRunning 检查试运行的进程是否仍在运行
+func (p *testProcess) isRunning() bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// KillTestProcess 结束试运行启动的进程
+func KillTestProcess() error {
+	if runningTest == nil || !runningTest.isRunning() {
+		runningTest = nil
+		return fmt.Errorf("没有正在运行的试运行进程")
+	}
+	if err := runningTest.cmd.Process.Kill(); err != nil {
+		return fmt.Errorf("结束进程失败: %v", err)
+	}
+	<-runningTest.done
+	runningTest = nil
+	return nil
+}
+
+// handleTestLaunch 处理试运行启动项
+func handleTestLaunch() {
+	// 上一次试运行的进程仍在运行时先询问是否结束
+	if runningTest != nil && runningTest.isRunning() {
+		fmt.Printf("\n上次试运行的 %s 仍在运行（PID %d）。\n", runningTest.name, runningTest.cmd.Process.Pid)
+		if confirmYes("是否结束该进程？(y/n): ") {
+			if err := KillTestProcess(); err != nil {
+				printError("%v", err)
+			} else {
+				fmt.Println("已结束试运行的进程。")
+			}
+		}
+	}
+
+	cache, err := loadCache()
+	if err != nil {
+		fmt.Printf("加载缓存失败: %v\n", err)
+		return
+	}
+
+	items := make([]ListItem, 0, len(cache.Items))
+	for _, item := range cache.Items {
+		items = append(items, ListItem{
+			Name:  item.Name,
+			Value: item.Value,
+		})
+	}
+
+	idx, ok := showListWithBack(items, "选择要试运行的启动项", pageSize)
+	if !ok {
+		return
+	}
+
+	selected := cache.Items[idx]
+	runningTest = nil
+	if err := TestLaunch(selected); err != nil {
+		printError("试运行失败 - %v", err)
+		return
+	}
+	if runningTest == nil {
+		fmt.Println("启动成功：程序已正常退出。")
+		return
+	}
+
+	fmt.Printf("启动成功：进程仍在运行（PID %d）\n", runningTest.cmd.Process.Pid)
+	if confirmYes("是否结束试运行的进程？(y/n): ") {
+		if err := KillTestProcess(); err != nil {
+			printError("%v", err)
+			return
+		}
+		fmt.Println("已结束试运行的进程。")
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// 为启动项生成的辅助文件的后缀，事务回滚时一并恢复
+var entryFileSuffixes = []string{delayWrapperSuffix, workDirWrapperSuffix, "_hidden.vbs"}
+
+// TransactionStep 事务中一个操作的执行记录
+type TransactionStep struct {
+	Index   int
+	Applied bool
+	Err     error
+
+	inverse func() error // 撤销该操作的函数，nil 表示无需撤销
+	partial bool         // 操作失败时也可以调用 inverse 撤销已完成的部分
+}
+
+// transactionOperation 事务中的一个操作，执行后返回撤销它的函数
+type transactionOperation struct {
+	run     func() (inverse func() error, err error)
+	partial bool
+}
+
+// Transaction 将多个操作作为一个整体执行
+// 每个操作执行时记录它的撤销函数，任一操作失败时 Rollback 按相反顺序逐个撤销已执行的操作
+type Transaction struct {
+	operations []transactionOperation
+	steps      []TransactionStep
+}
+
+// Begin 开始一个新事务
+func Begin() *Transaction {
+	return &Transaction{}
+}
+
+// AddOperation 向事务中添加一个操作，操作在 Commit 时按顺序执行
+// 执行前记录整个 Run 键和各启动项的辅助文件，回滚时据此恢复；只修改一个启动项的操作应使用 AddEntryOperation，
+// 回滚时不会影响其他启动项
+func (tx *Transaction) AddOperation(op func() error) {
+	tx.operations = append(tx.operations, transactionOperation{
+		run: func() (func() error, error) {
+			restore, err := snapshotRunKeyState()
+			if err != nil {
+				return nil, err
+			}
+			return restore, op()
+		},
+	})
+}
+
+// AddEntryOperation 向事务中添加一个只修改启动项 name 的操作
+// 执行前记录 name 在 Run 键中的值和为它生成的辅助文件，回滚时恢复到执行前的状态，
+// 其他启动项即使在事务期间被其他程序修改也不受影响
+func (tx *Transaction) AddEntryOperation(name string, op func() error) {
+	tx.operations = append(tx.operations, transactionOperation{
+		run: func() (func() error, error) {
+			restore, err := snapshotEntryState(name)
+			if err != nil {
+				return nil, err
+			}
+			return restore, op()
+		},
+		partial: true,
+	})
+}
+
+// Steps 返回已执行操作的记录
+func (tx *Transaction) Steps() []TransactionStep {
+	return tx.steps
+}
+
+// Commit 按顺序执行所有操作，任一操作失败时回滚已执行的操作
+func (tx *Transaction) Commit() error {
+	for i, op := range tx.operations {
+		inverse, err := op.run()
+		tx.steps = append(tx.steps, TransactionStep{
+			Index:   i,
+			Applied: err == nil,
+			Err:     err,
+			inverse: inverse,
+			partial: op.partial,
+		})
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("第 %d 步失败: %v（回滚失败: %v）", i+1, err, rbErr)
+			}
+			return fmt.Errorf("第 %d 步失败，已回滚: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Rollback 按相反顺序撤销已执行的操作
+// 失败的操作如果可以撤销部分完成的修改也一并撤销；某一步撤销失败时继续撤销其余的步骤
+func (tx *Transaction) Rollback() error {
+	var firstErr error
+	for i := len(tx.steps) - 1; i >= 0; i-- {
+		step := &tx.steps[i]
+		if step.inverse == nil || (!step.Applied && !step.partial) {
+			continue
+		}
+		if err := step.inverse(); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("撤销第 %d 步失败: %v", step.Index+1, err)
+			}
+			continue
+		}
+		step.Applied = false
+	}
+	return firstErr
+}
+
+// snapshotEntryState 记录启动项 name 在 Run 键中的值和辅助文件，返回恢复到该状态的函数
+func snapshotEntryState(name string) (func() error, error) {
+	value, err := startupRegistry.GetValue(runKeyPath, name)
+	inRegistry := err == nil
+	if err != nil && err != registry.ErrNotExist {
+		return nil, fmt.Errorf("查询注册表值失败: %v", err)
+	}
+
+	// 执行前存在的文件恢复原内容，不存在的文件在恢复时删除
+	files := make(map[string][]byte)
+	var missing []string
+	for _, suffix := range entryFileSuffixes {
+		path := wrapperPath(name, suffix)
+		data, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+			missing = append(missing, path)
+		case err != nil:
+			return nil, fmt.Errorf("读取 %s 失败: %v", path, err)
+		default:
+			files[path] = data
+		}
+	}
+
+	return func() error {
+		// 试运行时操作没有真正执行，无需恢复
+		if dryRun {
+			return nil
+		}
+
+		if inRegistry {
+			if err := startupRegistry.SetValue(runKeyPath, name, value); err != nil {
+				return fmt.Errorf("恢复 %s 失败: %v", name, err)
+			}
+		} else if err := startupRegistry.DeleteValue(runKeyPath, name); err != nil && err != registry.ErrNotExist {
+			return fmt.Errorf("删除 %s 失败: %v", name, err)
+		}
+
+		for _, path := range missing {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("删除 %s 失败: %v", path, err)
+			}
+		}
+		for path, data := range files {
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return fmt.Errorf("恢复 %s 失败: %v", path, err)
+			}
+		}
+		return nil
+	}, nil
+}
+
+// snapshotRunKeyState 记录 Run 键中所有启动项的值和辅助文件，返回恢复到该状态的函数
+// 恢复时删除之后新加入的值及其辅助文件
+func snapshotRunKeyState() (func() error, error) {
+	values, err := startupRegistry.ReadValues(runKeyPath)
+	if err != nil && err != registry.ErrNotExist {
+		return nil, fmt.Errorf("读取注册表失败: %v", err)
+	}
+	restores := make([]func() error, 0, len(values))
+	for name := range values {
+		restore, err := snapshotEntryState(name)
+		if err != nil {
+			return nil, err
+		}
+		restores = append(restores, restore)
+	}
+
+	return func() error {
+		if dryRun {
+			return nil
+		}
+		current, err := startupRegistry.ReadValues(runKeyPath)
+		if err != nil && err != registry.ErrNotExist {
+			return fmt.Errorf("读取注册表失败: %v", err)
+		}
+		for name := range current {
+			if _, existed := values[name]; existed {
+				continue
+			}
+			if err := startupRegistry.DeleteValue(runKeyPath, name); err != nil && err != registry.ErrNotExist {
+				return fmt.Errorf("删除 %s 失败: %v", name, err)
+			}
+			for _, suffix := range entryFileSuffixes {
+				if err := os.Remove(wrapperPath(name, suffix)); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("删除 %s 失败: %v", wrapperPath(name, suffix), err)
+				}
+			}
+		}
+		for _, restore := range restores {
+			if err := restore(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransactionRollback(t *testing.T) {
+	reg := setupTestEnv(t)
+	reg.SetValue(runKeyPath, "A", "old")
+
+	tx := Begin()
+	tx.AddOperation(func() error {
+		reg.SetValue(runKeyPath, "A", "new")
+		return reg.SetValue(runKeyPath, "B", "added")
+	})
+	tx.AddEntryOperation("C", func() error {
+		reg.SetValue(runKeyPath, "C", "partial")
+		return errors.New("失败")
+	})
+	if err := tx.Commit(); err == nil {
+		t.Fatal("Commit 应返回第 2 步的错误")
+	}
+
+	if got, _ := reg.GetValue(runKeyPath, "A"); got != "old" {
+		t.Errorf("A = %q, want 回滚到 old", got)
+	}
+	for _, name := range []string{"B", "C"} {
+		if _, err := reg.GetValue(runKeyPath, name); err == nil {
+			t.Errorf("%s 应在回滚时删除", name)
+		}
+	}
+	if steps := tx.Steps(); len(steps) != 2 || steps[0].Applied || steps[1].Err == nil {
+		t.Errorf("Steps = %+v", steps)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// OperationType 可撤销的操作类型
+type OperationType string
+
+const (
+	OpAdd     OperationType = "add"
+	OpRemove  OperationType = "remove"
+	OpEnable  OperationType = "enable"
+	OpDisable OperationType = "disable"
+	OpRename  OperationType = "rename"
+	OpEdit    OperationType = "edit"
+	OpMove    OperationType = "move"
+)
+
+// 最多保留的撤销层数
+const maxUndoLevels = 10
+
+// UndoRecord 一次操作的撤销记录
+type UndoRecord struct {
+	OperationType OperationType
+	Name          string     // 操作后的启动项名称（重命名时为新名称）
+	Before        *CacheItem // 操作前的快照，nil 表示操作前不存在
+	Timestamp     time.Time
+}
+
+// 撤销栈，只保存在内存中
+var undoStack []UndoRecord
+
+// newUndoRecord 在操作执行前记录启动项 name 的当前状态
+func newUndoRecord(op OperationType, name string) UndoRecord {
+	return UndoRecord{
+		OperationType: op,
+		Name:          name,
+		Before:        snapshotItem(name),
+		Timestamp:     time.Now(),
+	}
+}
+
+// pushUndo 将撤销记录压入撤销栈，超出层数时丢弃最早的记录
+func pushUndo(record UndoRecord) {
+	// 每次可撤销的修改之后，之前检查的失效项数量都可能已过期
+	markOrphansStale()
+
+	undoStack = append(undoStack, record)
+	if len(undoStack) > maxUndoLevels {
+		undoStack = undoStack[len(undoStack)-maxUndoLevels:]
+	}
+}
+
+// snapshotItem 获取启动项的当前状态，优先使用缓存，其次使用注册表
+func snapshotItem(name string) *CacheItem {
+	if cache, err := loadCache(); err == nil {
+		if idx, item := findItemByName(cache, name); idx >= 0 {
+			return item
+		}
+	}
+
+	value, err := startupRegistry.GetValue(runKeyPath, name)
+	if err != nil {
+		return nil
+	}
+	return &CacheItem{Name: name, Value: value, Enabled: true}
+}
+
+// undoLastOperation 撤销最近一次操作，将注册表和缓存恢复到操作前的状态
+func undoLastOperation() (UndoRecord, error) {
+	if len(undoStack) == 0 {
+		return UndoRecord{}, fmt.Errorf("没有可撤销的操作")
+	}
+	record := undoStack[len(undoStack)-1]
+
+	cache, err := loadCache()
+	if err != nil {
+		return record, fmt.Errorf("加载缓存失败: %v", err)
+	}
+
+	// 先清除操作后的状态，移到所有用户的项从 HKLM 删除
+	if _, after := findItemByName(cache, record.Name); after != nil && after.Scope == ScopeMachineWide && !after.RunOnce {
+		if _, err := getRunValue(ScopeMachineWide, record.Name); err == nil {
+			if err := deleteRunValue(ScopeMachineWide, record.Name); err != nil {
+				return record, err
+			}
+		}
+	}
+	exists, err := IsCommandInStartup(record.Name)
+	if err != nil {
+		return record, err
+	}
+	if exists {
+		if err := RemoveFromStartup(record.Name); err != nil {
+			return record, err
+		}
+	}
+	removeItem(cache, record.Name)
+
+	// 再恢复操作前的快照
+	if before := record.Before; before != nil {
+		if before.Enabled {
+			if err := registerStartupItem(*before); err != nil {
+				return record, err
+			}
+		} else if exists, _ := IsCommandInStartup(before.Name); exists {
+			if err := RemoveFromStartup(before.Name); err != nil {
+				return record, err
+			}
+		}
+		removeItem(cache, before.Name)
+		cache.Items = append(cache.Items, *before)
+	}
+
+	if err := saveCache(cache); err != nil {
+		return record, fmt.Errorf("保存缓存失败: %v", err)
+	}
+
+	undoStack = undoStack[:len(undoStack)-1]
+	markOrphansStale()
+	return record, nil
+}
+
+// operationLabel 操作类型的显示名称
+func operationLabel(op OperationType) string {
+	switch op {
+	case OpAdd:
+		return "添加"
+	case OpRemove:
+		return "移除"
+	case OpEnable:
+		return "启用"
+	case OpDisable:
+		return "禁用"
+	case OpRename:
+		return "重命名"
+	case OpEdit:
+		return "编辑"
+	case OpMove:
+		return "移动"
+	}
+	return string(op)
+}
+
+// handleUndo 处理撤销上一步操作
+func handleUndo() {
+	if len(undoStack) == 0 {
+		fmt.Println("没有可撤销的操作。")
+		return
+	}
+
+	record := undoStack[len(undoStack)-1]
+	fmt.Printf("\n上一步操作: %s %s（%s）\n", operationLabel(record.OperationType), record.Name,
+		record.Timestamp.Format("15:04:05"))
+	if !confirmYes("确定要撤销吗？(y/n): ") {
+		return
+	}
+
+	if _, err := undoLastOperation(); err != nil {
+		printError("撤销失败 - %v", err)
+		return
+	}
+	fmt.Printf("已撤销%s %s！\n", operationLabel(record.OperationType), record.Name)
+}
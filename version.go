@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// readVersionInfo 读取文件的版本信息资源
+func readVersionInfo(exePath string) ([]byte, error) {
+	var zero windows.Handle
+	size, err := windows.GetFileVersionInfoSize(exePath, &zero)
+	if err != nil {
+		return nil, fmt.Errorf("文件不包含版本信息: %v", err)
+	}
+
+	buf := make([]byte, size)
+	if err := windows.GetFileVersionInfo(exePath, 0, size, unsafe.Pointer(&buf[0])); err != nil {
+		return nil, fmt.Errorf("读取版本信息失败: %v", err)
+	}
+	return buf, nil
+}
+
+// GetFileVersion 获取程序的文件版本（VS_FIXEDFILEINFO 中的 FileVersion）
+func GetFileVersion(exePath string) (string, error) {
+	buf, err := readVersionInfo(exePath)
+	if err != nil {
+		return "", err
+	}
+
+	var info *windows.VS_FIXEDFILEINFO
+	var size uint32
+	if err := windows.VerQueryValue(unsafe.Pointer(&buf[0]), `\`, unsafe.Pointer(&info), &size); err != nil {
+		return "", fmt.Errorf("读取文件版本失败: %v", err)
+	}
+	if size < uint32(unsafe.Sizeof(*info)) {
+		return "", fmt.Errorf("文件版本信息无效")
+	}
+
+	return fmt.Sprintf("%d.%d.%d.%d",
+		info.FileVersionMS>>16, info.FileVersionMS&0xFFFF,
+		info.FileVersionLS>>16, info.FileVersionLS&0xFFFF), nil
+}
+
+// GetFilePublisher 获取程序的发布者（字符串表中的 CompanyName）
+func GetFilePublisher(exePath string) (string, error) {
+	buf, err := readVersionInfo(exePath)
+	if err != nil {
+		return "", err
+	}
+	block := unsafe.Pointer(&buf[0])
+
+	// 优先使用文件声明的语言和代码页，其次尝试常见的组合
+	var translations []string
+	var langs *[1 << 16]struct{ Language, CodePage uint16 }
+	var size uint32
+	if err := windows.VerQueryValue(block, `\VarFileInfo\Translation`, unsafe.Pointer(&langs), &size); err == nil {
+		for i := 0; i < int(size)/4; i++ {
+			translations = append(translations, fmt.Sprintf("%04x%04x", langs[i].Language, langs[i].CodePage))
+		}
+	}
+	translations = append(translations, "040904b0", "040904e4", "080404b0")
+
+	for _, t := range translations {
+		var value *uint16
+		var length uint32
+		if err := windows.VerQueryValue(block, `\StringFileInfo\`+t+`\CompanyName`, unsafe.Pointer(&value), &length); err != nil || length == 0 {
+			continue
+		}
+		return windows.UTF16PtrToString(value), nil
+	}
+	return "", fmt.Errorf("文件未声明发布者")
+}
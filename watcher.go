@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// ChangeType 启动项变化类型
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// ChangeEvent 注册表 Run 键中的一次启动项变化
+type ChangeEvent struct {
+	Name       string
+	ChangeType ChangeType
+	NewValue   string
+}
+
+// RegistryWatcher 监听 Run 键的变化，并通过 Events 发送变化事件
+type RegistryWatcher struct {
+	Events chan ChangeEvent
+
+	key      registry.Key
+	notify   windows.Handle
+	stop     windows.Handle
+	quit     chan struct{}
+	done     chan struct{}
+	snapshot map[string]string
+	stopOnce sync.Once
+}
+
+// NewRegistryWatcher 创建 Run 键监听器，调用 Start 后开始监听
+func NewRegistryWatcher() *RegistryWatcher {
+	return &RegistryWatcher{
+		Events: make(chan ChangeEvent),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start 开始监听 Run 键
+func (w *RegistryWatcher) Start() error {
+	// 变化通知需要真实的注册表句柄，因此不经过 startupRegistry
+	key, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE|registry.NOTIFY)
+	if err != nil {
+		return fmt.Errorf("打开注册表失败: %v", err)
+	}
+
+	// 自动重置的事件，每次收到通知后重新注册
+	notify, err := windows.CreateEvent(nil, 0, 0, nil)
+	if err != nil {
+		key.Close()
+		return fmt.Errorf("创建通知事件失败: %v", err)
+	}
+
+	stop, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		windows.CloseHandle(notify)
+		key.Close()
+		return fmt.Errorf("创建停止事件失败: %v", err)
+	}
+
+	w.key = key
+	w.notify = notify
+	w.stop = stop
+	w.snapshot = readKeyValues(key)
+
+	go w.loop()
+	return nil
+}
+
+// Stop 停止监听并关闭 Events
+func (w *RegistryWatcher) Stop() error {
+	var err error
+	w.stopOnce.Do(func() {
+		close(w.quit)
+		if e := windows.SetEvent(w.stop); e != nil {
+			err = fmt.Errorf("停止监听失败: %v", e)
+		}
+		<-w.done
+
+		windows.CloseHandle(w.notify)
+		windows.CloseHandle(w.stop)
+		w.key.Close()
+	})
+	return err
+}
+
+// loop 等待注册表通知，比较前后快照并发送变化事件
+func (w *RegistryWatcher) loop() {
+	defer close(w.done)
+	defer close(w.Events)
+
+	filter := uint32(windows.REG_NOTIFY_CHANGE_NAME | windows.REG_NOTIFY_CHANGE_LAST_SET)
+	for {
+		err := windows.RegNotifyChangeKeyValue(windows.Handle(w.key), false, filter, w.notify, true)
+		if err != nil {
+			return
+		}
+
+		event, err := windows.WaitForMultipleObjects([]windows.Handle{w.notify, w.stop}, false, windows.INFINITE)
+		if err != nil || event != windows.WAIT_OBJECT_0 {
+			return
+		}
+
+		current := readKeyValues(w.key)
+		for _, change := range diffKeyValues(w.snapshot, current) {
+			select {
+			case w.Events <- change:
+			case <-w.quit:
+				return
+			}
+		}
+		w.snapshot = current
+	}
+}
+
+// WatchRegistry 监听 Run 键变化，ctx 取消时停止监听并关闭返回的通道
+func WatchRegistry(ctx context.Context) (<-chan ChangeEvent, error) {
+	watcher := NewRegistryWatcher()
+	if err := watcher.Start(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		watcher.Stop()
+	}()
+
+	return watcher.Events, nil
+}
+
+// readKeyValues 读取注册表键下的所有字符串值
+func readKeyValues(key registry.Key) map[string]string {
+	values := make(map[string]string)
+
+	names, err := key.ReadValueNames(0)
+	if err != nil {
+		return values
+	}
+
+	for _, name := range names {
+		value, _, err := key.GetStringValue(name)
+		if err == nil {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// diffKeyValues 比较前后两次读取的值，返回变化事件
+func diffKeyValues(before, after map[string]string) []ChangeEvent {
+	var changes []ChangeEvent
+	for name, value := range after {
+		old, exists := before[name]
+		if !exists {
+			changes = append(changes, ChangeEvent{Name: name, ChangeType: ChangeAdded, NewValue: value})
+		} else if old != value {
+			changes = append(changes, ChangeEvent{Name: name, ChangeType: ChangeModified, NewValue: value})
+		}
+	}
+	for name := range before {
+		if _, exists := after[name]; !exists {
+			changes = append(changes, ChangeEvent{Name: name, ChangeType: ChangeRemoved})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 启动时的窗口状态
+const (
+	windowStateNormal    = "normal"
+	windowStateMinimized = "minimized"
+	windowStateHidden    = "hidden"
+)
+
+// isValidWindowState 检查窗口状态是否有效，空字符串视为 normal
+func isValidWindowState(state string) bool {
+	switch state {
+	case "", windowStateNormal, windowStateMinimized, windowStateHidden:
+		return true
+	}
+	return false
+}
+
+// windowStateLabel 窗口状态的显示名称，正常窗口返回空字符串
+func windowStateLabel(state string) string {
+	switch state {
+	case windowStateMinimized:
+		return "最小化"
+	case windowStateHidden:
+		return "隐藏"
+	}
+	return ""
+}
+
+// wrapperPath 获取为启动项生成的辅助文件路径，与缓存文件位于同一目录
+// 注册表值名称可以包含 \ 等文件名中不允许的字符，这些字符替换为 _，生成的文件不会落在缓存目录之外
+func wrapperPath(appName, suffix string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`\/:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, appName)
+	return filepath.Join(filepath.Dir(cacheFilePath), name+suffix)
+}
+
+// windowStateCommand 根据窗口状态包装启动命令
+// minimized 通过 cmd 的 start /min 启动，hidden 通过 wscript 运行生成的 .vbs 文件
+func windowStateCommand(command, appName, windowState string) string {
+	switch windowState {
+	case windowStateMinimized:
+		return `cmd.exe /c start "" /min ` + command
+	case windowStateHidden:
+		return fmt.Sprintf(`wscript.exe "%s"`, wrapperPath(appName, "_hidden.vbs"))
+	}
+	return command
+}
+
+// removeWindowStateWrapper 删除为 hidden 状态生成的 .vbs 文件，文件不存在时忽略
+func removeWindowStateWrapper(appName string) error {
+	path := wrapperPath(appName, "_hidden.vbs")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if dryRunf("删除文件 %s", path) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除启动脚本失败: %v", err)
+	}
+	return nil
+}
+
+// unwrapWindowState 还原被窗口状态包装前的启动命令
+// hidden 状态需要读取生成的 .vbs 文件，读取失败时返回原值
+func unwrapWindowState(value, appName, windowState string) string {
+	switch windowState {
+	case windowStateMinimized:
+		return strings.TrimPrefix(value, `cmd.exe /c start "" /min `)
+	case windowStateHidden:
+		data, err := os.ReadFile(wrapperPath(appName, "_hidden.vbs"))
+		if err != nil {
+			return value
+		}
+		script := decodeRegFile(data)
+		start := strings.Index(script, `.Run "`)
+		end := strings.LastIndex(script, `", 0,`)
+		if start < 0 || end < start {
+			return value
+		}
+		return strings.ReplaceAll(script[start+len(`.Run "`):end], `""`, `"`)
+	}
+	return value
+}
+
+// writeWindowStateWrapper 为 hidden 状态生成 .vbs 文件，其他状态无需生成文件
+func writeWindowStateWrapper(command, appName, windowState string) error {
+	if windowState != windowStateHidden {
+		return nil
+	}
+
+	// Run 的第二个参数 0 表示隐藏窗口，VBScript 字符串中的引号需要写成两个
+	script := fmt.Sprintf("CreateObject(\"WScript.Shell\").Run \"%s\", 0, False\r\n",
+		strings.ReplaceAll(command, `"`, `""`))
+
+	if dryRunf("生成文件 %s", wrapperPath(appName, "_hidden.vbs")) {
+		return nil
+	}
+
+	// 带 BOM 的 UTF-16LE 保证非 ASCII 路径能被 wscript 正确读取
+	if err := os.WriteFile(wrapperPath(appName, "_hidden.vbs"), encodeUTF16LE(script), 0644); err != nil {
+		return fmt.Errorf("生成启动脚本失败: %v", err)
+	}
+	return nil
+}
+
+// moveWindowStateWrapper 启动项改名为 newName 时按新名称重新生成 .vbs，并更新 item.Value 指向新文件
+// 旧名称的 .vbs 由调用方在改名成功后删除
+func moveWindowStateWrapper(item *CacheItem, newName string) error {
+	if item.WindowState != windowStateHidden {
+		return nil
+	}
+	command := unwrapWindowState(item.Value, item.Name, item.WindowState)
+	if err := writeWindowStateWrapper(command, newName, item.WindowState); err != nil {
+		return err
+	}
+	item.Value = windowStateCommand(command, newName, item.WindowState)
+	return nil
+}
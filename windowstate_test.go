@@ -0,0 +1,29 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWrapperPath(t *testing.T) {
+	setupTestEnv(t)
+	dir := filepath.Dir(cacheFilePath)
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"App", "App_delay.cmd"},
+		{`..\..\Startup\evil`, ".._.._Startup_evil_delay.cmd"},
+		{"../x", ".._x_delay.cmd"},
+		{`C:\a`, "C__a_delay.cmd"},
+	}
+	for _, tt := range tests {
+		got := wrapperPath(tt.name, delayWrapperSuffix)
+		if got != filepath.Join(dir, tt.want) {
+			t.Errorf("wrapperPath(%q) = %q, want %q", tt.name, got, filepath.Join(dir, tt.want))
+		}
+		if filepath.Dir(got) != dir {
+			t.Errorf("wrapperPath(%q) 不在缓存目录中: %s", tt.name, got)
+		}
+	}
+}
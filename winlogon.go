@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// Winlogon 键的位置，其中 Userinit 和 Shell 的值在用户登录时运行
+const winlogonKeyPath = `SOFTWARE\Microsoft\Windows NT\CurrentVersion\Winlogon`
+
+// winlogonDefaults Winlogon 各值中系统默认的程序
+var winlogonDefaults = map[string]string{
+	"Userinit": "userinit.exe",
+	"Shell":    "explorer.exe",
+}
+
+// WinlogonEntry Winlogon 的 Userinit 或 Shell 值中的一个程序
+type WinlogonEntry struct {
+	Key       string // Userinit 或 Shell
+	Value     string
+	IsDefault bool // 是否为系统默认的程序
+}
+
+// ListWinlogonEntries 读取 HKLM 中 Winlogon 的 Userinit 和 Shell 值
+// 值中以逗号分隔的每个程序作为一项返回，恶意软件常在默认程序之后追加自己的程序
+func ListWinlogonEntries() ([]WinlogonEntry, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, winlogonKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		return nil, fmt.Errorf("打开注册表失败: %v", err)
+	}
+	defer key.Close()
+
+	var entries []WinlogonEntry
+	for _, name := range []string{"Userinit", "Shell"} {
+		value, _, err := key.GetStringValue(name)
+		if err != nil {
+			continue
+		}
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			exePath, _, err := parseCommandPath(part)
+			if err != nil {
+				exePath = part
+			}
+			entries = append(entries, WinlogonEntry{
+				Key:       name,
+				Value:     part,
+				IsDefault: strings.EqualFold(filepath.Base(exePath), winlogonDefaults[name]),
+			})
+		}
+	}
+	return entries, nil
+}
+
+// showWinlogonEntries 显示 Winlogon 钩子，非默认的程序以 [!] 警告
+func showWinlogonEntries() {
+	entries, err := ListWinlogonEntries()
+	if err != nil {
+		fmt.Printf("读取 Winlogon 失败: %v\n", err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Println("\n" + strings.Repeat("-", 60))
+	fmt.Println("Winlogon 钩子（只读）：")
+	fmt.Println(strings.Repeat("-", 60))
+	hasWarning := false
+	for i, entry := range entries {
+		fmt.Printf("%d. [%s] %s", i+1, entry.Key, entry.Value)
+		if !entry.IsDefault {
+			fmt.Print(" ")
+			printer.Print("[!]", colorYellow)
+			hasWarning = true
+		}
+		fmt.Println()
+	}
+	if hasWarning {
+		printer.Print("警告: [!] 表示 Winlogon 中有非系统默认的程序，请确认其来源。\n", colorYellow)
+	}
+}